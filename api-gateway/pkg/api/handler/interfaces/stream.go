@@ -4,4 +4,10 @@ import "github.com/labstack/echo/v4"
 
 type StreamHandler interface {
 	Upload(ctx echo.Context) error
+	UploadRaw(ctx echo.Context) error
+	Download(ctx echo.Context) error
+	GetFile(ctx echo.Context) error
+	ListFiles(ctx echo.Context) error
+	DeleteFile(ctx echo.Context) error
+	RestoreFile(ctx echo.Context) error
 }
@@ -0,0 +1,12 @@
+package interfaces
+
+import "github.com/labstack/echo/v4"
+
+type TusHandler interface {
+	Resumable(next echo.HandlerFunc) echo.HandlerFunc
+	Options(ctx echo.Context) error
+	Create(ctx echo.Context) error
+	Head(ctx echo.Context) error
+	Patch(ctx echo.Context) error
+	Delete(ctx echo.Context) error
+}
@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"api-gateway/pkg/logger"
+	"context"
+	"log/slog"
+	"net/http"
+)
+
+// To log the failed call to stream service, as an error if the failure of the server and a warning otherwise
+func logClientError(ctx context.Context, log *slog.Logger, statusCode int, msg, fileID string, err error) {
+
+	level := slog.LevelWarn
+	if statusCode >= http.StatusInternalServerError {
+		level = slog.LevelError
+	}
+
+	attrs := []any{slog.Int("status", statusCode), slog.String(logger.KeyError, err.Error())}
+	if fileID != "" {
+		attrs = append(attrs, slog.String(logger.KeyFileID, fileID))
+	}
+
+	log.Log(ctx, level, msg, attrs...)
+}
@@ -3,71 +3,301 @@ package handler
 import (
 	"api-gateway/pkg/api/handler/interfaces"
 	clientinterface "api-gateway/pkg/client/interfaces"
+	"api-gateway/pkg/logger"
+	"api-gateway/pkg/metrics"
 	"api-gateway/pkg/models/request"
 	"api-gateway/pkg/utils"
-	"context"
+	"io"
+	"log/slog"
+	"mime"
 	"net/http"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
 
 type streamHandler struct {
-	client clientinterface.StreamClient
+	client  clientinterface.StreamClient
+	logger  *slog.Logger
+	metrics *metrics.Metrics
 }
 
-func NewStreamHandler(client clientinterface.StreamClient) interfaces.StreamHandler {
+func NewStreamHandler(client clientinterface.StreamClient, logger *slog.Logger,
+	metrics *metrics.Metrics) interfaces.StreamHandler {
 	return &streamHandler{
-		client: client,
+		client:  client,
+		logger:  logger,
+		metrics: metrics,
 	}
 }
 
+// max size of a form value on the multipart upload
+const maxFormValueSize = 1024
+
 func (s *streamHandler) Upload(ctx echo.Context) error {
-	// get file name from form values
-	name := ctx.FormValue("name")
-	if name == "" {
+
+	// read the multipart body part by part, so the file streamed without buffering
+	reader, err := ctx.Request().MultipartReader()
+	if err != nil {
 		return ctx.JSON(http.StatusBadRequest, echo.Map{
-			"message": "File name not provided",
+
+			"message": "Failed to read multipart form",
+			"error":   err.Error(),
 		})
 	}
-	// get file as form file
-	fh, err := ctx.FormFile("file")
+
+	// the form values should send before the file
+	values := make(map[string]string)
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			if err == io.EOF {
+				return ctx.JSON(http.StatusBadRequest, echo.Map{
+					"message": "Failed to get file from request",
+				})
+			}
+			return ctx.JSON(http.StatusBadRequest, echo.Map{
+
+				"message": "Failed to read multipart form",
+				"error":   err.Error(),
+			})
+		}
+
+		if part.FormName() == "file" {
+			// get file name from form values
+			if values["name"] == "" {
+				return ctx.JSON(http.StatusBadRequest, echo.Map{
+					"message": "File name not provided before the file",
+				})
+			}
+			return s.upload(ctx, values["name"], "", values["sha256"], nil, part)
+		}
+
+		value, err := io.ReadAll(io.LimitReader(part, maxFormValueSize+1))
+		if err != nil || len(value) > maxFormValueSize {
+			return ctx.JSON(http.StatusBadRequest, echo.Map{
+				"message": "Invalid form value " + part.FormName(),
+			})
+		}
+		values[part.FormName()] = string(value)
+	}
+}
+
+func (s *streamHandler) UploadRaw(ctx echo.Context) error {
+
+	req := ctx.Request()
+
+	// content type of the file is the request content type
+	var contentType string
+	if header := req.Header.Get(echo.HeaderContentType); header != "" {
+		mediaType, params, err := mime.ParseMediaType(header)
+		if err != nil {
+			return ctx.JSON(http.StatusBadRequest, echo.Map{
+
+				"message": "Invalid content type",
+				"error":   err.Error(),
+			})
+		}
+		contentType = mime.FormatMediaType(mediaType, params)
+	}
+
+	// declare the size if the content length known
+	var size *int64
+	if req.ContentLength >= 0 {
+		size = &req.ContentLength
+	}
+
+	return s.upload(ctx, ctx.Param("name"), contentType, ctx.QueryParam("sha256"), size, req.Body)
+}
+
+// To upload the file data from the body to client.
+// the content type detected from the data if not provided
+func (s *streamHandler) upload(ctx echo.Context, name, contentType, sha256 string, size *int64,
+	body io.Reader) error {
+
+	if contentType == "" {
+		var err error
+		contentType, body, err = utils.DetectContentType(body)
+		if err != nil {
+			return ctx.JSON(http.StatusBadRequest, echo.Map{
+
+				"message": "Failed to get file content type",
+				"error":   err.Error(),
+			})
+		}
+	}
+
+	upload := s.metrics.StartUpload(body)
+	fileDetails := request.FileDetails{
+		Name:        name,
+		ContentType: contentType,
+		Body:        upload,
+		Size:        size,
+		SHA256:      sha256,
+	}
+	// upload the file to client, the upload cancelled if the request cancelled
+	id, err := s.client.Upload(ctx.Request().Context(), fileDetails)
+	upload.End(ctx.Request().Context(), err)
 	if err != nil {
-		return ctx.JSON(http.StatusBadRequest, echo.Map{
+		statusCode := utils.GetHTTPStatusCode(err)
+		logClientError(ctx.Request().Context(), s.logger, statusCode, "failed to upload file", "", err)
+		return ctx.JSON(statusCode, echo.Map{
 
-			"message": "Failed to get file from request",
+			"message": "Failed to upload file",
 			"error":   err.Error(),
 		})
 	}
-	// get the file content type
-	contentType, err := utils.GetContentType(fh)
 
+	s.logger.InfoContext(ctx.Request().Context(), "file upload completed", logger.KeyFileID, id)
+
+	return ctx.JSON(http.StatusOK, echo.Map{
+
+		"message": "File upload completed",
+		"File ID": id,
+	})
+}
+
+func (s *streamHandler) Download(ctx echo.Context) error {
+
+	fileID := ctx.Param("id")
+
+	// get the file details and file data reader from client
+	fileDetails, file, err := s.client.Download(ctx.Request().Context(), fileID)
+	if err != nil {
+		statusCode := utils.GetHTTPStatusCode(err)
+		logClientError(ctx.Request().Context(), s.logger, statusCode, "failed to download file", fileID, err)
+		return ctx.JSON(statusCode, echo.Map{
+
+			"message": "Failed to download file",
+			"error":   err.Error(),
+		})
+	}
+	defer file.Close()
+
+	// set the file name to save the file on client side
+	ctx.Response().Header().Set(echo.HeaderContentDisposition,
+		mime.FormatMediaType("attachment", map[string]string{"filename": fileDetails.Name}))
+
+	return ctx.Stream(http.StatusOK, fileDetails.ContentType, file)
+}
+
+func (s *streamHandler) GetFile(ctx echo.Context) error {
+
+	fileID := ctx.Param("id")
+
+	fileDetails, err := s.client.GetFile(ctx.Request().Context(), fileID)
+	if err != nil {
+		statusCode := utils.GetHTTPStatusCode(err)
+		logClientError(ctx.Request().Context(), s.logger, statusCode, "failed to get file details", fileID, err)
+		return ctx.JSON(statusCode, echo.Map{
+
+			"message": "Failed to get file details",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, echo.Map{
+
+		"message": "Successfully got file details",
+		"file":    fileDetails,
+	})
+}
+
+func (s *streamHandler) ListFiles(ctx echo.Context) error {
+
+	listReq := request.ListFiles{
+		Cursor:      ctx.QueryParam("cursor"),
+		ContentType: ctx.QueryParam("content_type"),
+		NamePrefix:  ctx.QueryParam("name_prefix"),
+		SortBy:      ctx.QueryParam("sort_by"),
+	}
+
+	// parse the optional query params
+	err := echo.QueryParamsBinder(ctx).
+		Int("limit", &listReq.Limit).
+		Bool("deleted", &listReq.Deleted).
+		Time("uploaded_after", &listReq.UploadedAfter, time.RFC3339).
+		Time("uploaded_before", &listReq.UploadedBefore, time.RFC3339).
+		BindError()
 	if err != nil {
 		return ctx.JSON(http.StatusBadRequest, echo.Map{
 
-			"message": "Failed to get file content type",
+			"message": "Invalid query params",
 			"error":   err.Error(),
 		})
 	}
 
-	fileDetails := request.FileDetails{
-		Name:        name,
-		FileHeader:  fh,
-		ContentType: contentType,
+	switch ctx.QueryParam("order") {
+	case "", "asc":
+	case "desc":
+		listReq.Descending = true
+	default:
+		return ctx.JSON(http.StatusBadRequest, echo.Map{
+
+			"message": "Invalid query params",
+			"error":   "order should be asc or desc",
+		})
 	}
-	/// upload the file to client
-	id, err := s.client.Upload(context.Background(), fileDetails)
+
+	fileList, err := s.client.ListFiles(ctx.Request().Context(), listReq)
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, echo.Map{
+		statusCode := utils.GetHTTPStatusCode(err)
+		logClientError(ctx.Request().Context(), s.logger, statusCode, "failed to list files", "", err)
+		return ctx.JSON(statusCode, echo.Map{
 
-			"message": "Failed  upload file",
+			"message": "Failed to list files",
 			"error":   err.Error(),
 		})
 	}
 
 	return ctx.JSON(http.StatusOK, echo.Map{
 
-		"message": "File upload completed",
-		"File ID": id,
+		"message":     "Successfully listed files",
+		"files":       fileList.Files,
+		"next_cursor": fileList.NextCursor,
 	})
+}
 
+func (s *streamHandler) DeleteFile(ctx echo.Context) error {
+
+	fileID := ctx.Param("id")
+
+	purgeAt, err := s.client.DeleteFile(ctx.Request().Context(), fileID)
+	if err != nil {
+		statusCode := utils.GetHTTPStatusCode(err)
+		logClientError(ctx.Request().Context(), s.logger, statusCode, "failed to delete file", fileID, err)
+		return ctx.JSON(statusCode, echo.Map{
+
+			"message": "Failed to delete file",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, echo.Map{
+
+		"message":  "File moved to trash",
+		"purge_at": purgeAt,
+	})
+}
+
+func (s *streamHandler) RestoreFile(ctx echo.Context) error {
+
+	fileID := ctx.Param("id")
+
+	fileDetails, err := s.client.RestoreFile(ctx.Request().Context(), fileID)
+	if err != nil {
+		statusCode := utils.GetHTTPStatusCode(err)
+		logClientError(ctx.Request().Context(), s.logger, statusCode, "failed to restore file", fileID, err)
+		return ctx.JSON(statusCode, echo.Map{
+
+			"message": "Failed to restore file",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, echo.Map{
+
+		"message": "File restored from trash",
+		"file":    fileDetails,
+	})
 }
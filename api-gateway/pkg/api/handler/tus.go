@@ -0,0 +1,313 @@
+package handler
+
+import (
+	"api-gateway/pkg/api/handler/interfaces"
+	clientinterface "api-gateway/pkg/client/interfaces"
+	"api-gateway/pkg/logger"
+	"api-gateway/pkg/metrics"
+	"api-gateway/pkg/models/request"
+	"api-gateway/pkg/models/response"
+	"api-gateway/pkg/utils"
+	"encoding/base64"
+	"errors"
+	"log/slog"
+	"net/http"
+	"path"
+	"strconv"
+	"stream-sdk/pkg/sdk"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// tus resumable upload protocol (https://tus.io/protocols/resumable-upload)
+const (
+	tusVersion          = "1.0.0"
+	tusExtensions       = "creation,termination,checksum,expiration"
+	tusChecksums        = "md5,sha1,sha256"
+	tusOffsetStreamType = "application/offset+octet-stream"
+
+	headerTusResumable         = "Tus-Resumable"
+	headerTusVersion           = "Tus-Version"
+	headerTusExtension         = "Tus-Extension"
+	headerTusChecksumAlgorithm = "Tus-Checksum-Algorithm"
+	headerUploadLength         = "Upload-Length"
+	headerUploadOffset         = "Upload-Offset"
+	headerUploadMetadata       = "Upload-Metadata"
+	headerUploadChecksum       = "Upload-Checksum"
+	headerUploadExpires        = "Upload-Expires"
+
+	// status code of tus checksum extension for a part not matching the checksum
+	statusChecksumMismatch = 460
+
+	defaultTusFileName    = "untitled"
+	defaultTusContentType = "application/octet-stream"
+)
+
+type tusHandler struct {
+	client  clientinterface.StreamClient
+	logger  *slog.Logger
+	metrics *metrics.Metrics
+}
+
+func NewTusHandler(client clientinterface.StreamClient, logger *slog.Logger,
+	metrics *metrics.Metrics) interfaces.TusHandler {
+	return &tusHandler{
+		client:  client,
+		logger:  logger,
+		metrics: metrics,
+	}
+}
+
+// To set the protocol version on all responses and reject the requests with a version not supported
+func (t *tusHandler) Resumable(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+
+		ctx.Response().Header().Set(headerTusResumable, tusVersion)
+
+		// the options request used to discover the version, so not required the version
+		if ctx.Request().Method != http.MethodOptions &&
+			ctx.Request().Header.Get(headerTusResumable) != tusVersion {
+			ctx.Response().Header().Set(headerTusVersion, tusVersion)
+			return ctx.JSON(http.StatusPreconditionFailed, echo.Map{
+				"message": "Unsupported tus version",
+			})
+		}
+
+		return next(ctx)
+	}
+}
+
+func (t *tusHandler) Options(ctx echo.Context) error {
+
+	header := ctx.Response().Header()
+	header.Set(headerTusVersion, tusVersion)
+	header.Set(headerTusExtension, tusExtensions)
+	header.Set(headerTusChecksumAlgorithm, tusChecksums)
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (t *tusHandler) Create(ctx echo.Context) error {
+
+	length, err := strconv.ParseInt(ctx.Request().Header.Get(headerUploadLength), 10, 64)
+	if err != nil || length < 0 {
+		return ctx.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Invalid " + headerUploadLength,
+		})
+	}
+
+	metadata, err := parseTusMetadata(ctx.Request().Header.Get(headerUploadMetadata))
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, echo.Map{
+
+			"message": "Invalid " + headerUploadMetadata,
+			"error":   err.Error(),
+		})
+	}
+
+	fileDetails := request.FileDetails{
+		Name:        metadata["filename"],
+		ContentType: metadata["filetype"],
+		Size:        &length,
+	}
+	if fileDetails.Name == "" {
+		fileDetails.Name = defaultTusFileName
+	}
+	if fileDetails.ContentType == "" {
+		fileDetails.ContentType = defaultTusContentType
+	}
+
+	sessionID, err := t.client.CreateUploadSession(ctx.Request().Context(), fileDetails)
+	if err != nil {
+		statusCode := utils.GetHTTPStatusCode(err)
+		logClientError(ctx.Request().Context(), t.logger, statusCode, "failed to create upload", "", err)
+		return ctx.JSON(statusCode, echo.Map{
+
+			"message": "Failed to create upload",
+			"error":   err.Error(),
+		})
+	}
+
+	// an empty file not going to receive any data, so complete it on creation
+	if length == 0 {
+		_, err = t.client.UploadSessionPart(ctx.Request().Context(), sessionID, 0, nil, strings.NewReader(""))
+		if err != nil {
+			statusCode := utils.GetHTTPStatusCode(err)
+			logClientError(ctx.Request().Context(), t.logger, statusCode, "failed to complete empty upload", sessionID, err)
+			return ctx.JSON(statusCode, echo.Map{
+
+				"message": "Failed to complete empty upload",
+				"error":   err.Error(),
+			})
+		}
+	}
+
+	session, err := t.client.GetUploadSession(ctx.Request().Context(), sessionID)
+	if err == nil {
+		setUploadExpires(ctx, session)
+	}
+
+	ctx.Response().Header().Set(echo.HeaderLocation, path.Join(ctx.Request().URL.Path, sessionID))
+
+	return ctx.NoContent(http.StatusCreated)
+}
+
+func (t *tusHandler) Head(ctx echo.Context) error {
+
+	session, err := t.client.GetUploadSession(ctx.Request().Context(), ctx.Param("id"))
+	if err != nil {
+		statusCode := utils.GetHTTPStatusCode(err)
+		logClientError(ctx.Request().Context(), t.logger, statusCode, "failed to get upload", ctx.Param("id"), err)
+		return ctx.JSON(statusCode, echo.Map{
+
+			"message": "Failed to get upload",
+			"error":   err.Error(),
+		})
+	}
+
+	header := ctx.Response().Header()
+	header.Set(echo.HeaderCacheControl, "no-store")
+	header.Set(headerUploadOffset, strconv.FormatInt(session.Offset, 10))
+	if session.Size != nil {
+		header.Set(headerUploadLength, strconv.FormatInt(*session.Size, 10))
+	}
+	setUploadExpires(ctx, session)
+
+	return ctx.NoContent(http.StatusOK)
+}
+
+func (t *tusHandler) Patch(ctx echo.Context) error {
+
+	req := ctx.Request()
+	if req.Header.Get(echo.HeaderContentType) != tusOffsetStreamType {
+		return ctx.JSON(http.StatusUnsupportedMediaType, echo.Map{
+			"message": "Content type should be " + tusOffsetStreamType,
+		})
+	}
+
+	offset, err := strconv.ParseInt(req.Header.Get(headerUploadOffset), 10, 64)
+	if err != nil || offset < 0 {
+		return ctx.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Invalid " + headerUploadOffset,
+		})
+	}
+
+	var checksum *request.PartChecksum
+	if header := req.Header.Get(headerUploadChecksum); header != "" {
+		if checksum, err = parseTusChecksum(header); err != nil {
+			return ctx.JSON(http.StatusBadRequest, echo.Map{
+
+				"message": "Invalid " + headerUploadChecksum,
+				"error":   err.Error(),
+			})
+		}
+	}
+
+	// the offset verified by the service with the committed offset of the session
+	upload := t.metrics.StartUpload(req.Body)
+	session, err := t.client.UploadSessionPart(req.Context(), ctx.Param("id"), offset, checksum, upload)
+	upload.End(req.Context(), err)
+	if err != nil {
+		statusCode := getTusStatusCode(err)
+		logClientError(req.Context(), t.logger, statusCode, "failed to upload", ctx.Param("id"), err)
+		return ctx.JSON(statusCode, echo.Map{
+
+			"message": "Failed to upload",
+			"error":   err.Error(),
+		})
+	}
+
+	ctx.Response().Header().Set(headerUploadOffset, strconv.FormatInt(session.Offset, 10))
+	if session.Completed {
+		t.logger.InfoContext(req.Context(), "file upload completed", logger.KeyFileID, session.ID)
+	}
+
+	// expire time extended on each part uploaded
+	if !session.Completed {
+		if session, err = t.client.GetUploadSession(req.Context(), session.ID); err == nil {
+			setUploadExpires(ctx, session)
+		}
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (t *tusHandler) Delete(ctx echo.Context) error {
+
+	err := t.client.DeleteUploadSession(ctx.Request().Context(), ctx.Param("id"))
+	if err != nil {
+		statusCode := utils.GetHTTPStatusCode(err)
+		logClientError(ctx.Request().Context(), t.logger, statusCode, "failed to terminate upload", ctx.Param("id"), err)
+		return ctx.JSON(statusCode, echo.Map{
+
+			"message": "Failed to terminate upload",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// To set the expire time of the session if the session is going to expire
+func setUploadExpires(ctx echo.Context, session response.UploadSession) {
+	if !session.Completed && !session.ExpiresAt.IsZero() {
+		ctx.Response().Header().Set(headerUploadExpires, session.ExpiresAt.UTC().Format(http.TimeFormat))
+	}
+}
+
+// To get the http status code of error with the tus specific status codes,
+// the errors of the same code told apart by the reason of the stream service
+func getTusStatusCode(err error) int {
+	switch sdk.ErrorReason(err) {
+	case sdk.ReasonChecksumMismatch:
+		return statusChecksumMismatch
+	case sdk.ReasonSizeMismatch:
+		return http.StatusBadRequest
+	case sdk.ReasonOffsetMismatch:
+		return http.StatusConflict
+	default:
+		return utils.GetHTTPStatusCode(err)
+	}
+}
+
+// To parse the upload metadata as comma separated key and base64 encoded value pairs (value is optional)
+func parseTusMetadata(header string) (map[string]string, error) {
+
+	metadata := make(map[string]string)
+	if header == "" {
+		return metadata, nil
+	}
+
+	for _, pair := range strings.Split(header, ",") {
+		key, encoded, _ := strings.Cut(strings.TrimSpace(pair), " ")
+		if key == "" {
+			return nil, errors.New("metadata key should not be empty")
+		}
+		value, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, errors.New("invalid value of metadata " + key)
+		}
+		metadata[key] = string(value)
+	}
+
+	return metadata, nil
+}
+
+// To parse the upload checksum as the algorithm and base64 encoded digest separated by space
+func parseTusChecksum(header string) (*request.PartChecksum, error) {
+
+	algorithm, encoded, found := strings.Cut(header, " ")
+	if !found || algorithm == "" {
+		return nil, errors.New("checksum should be the algorithm and the digest")
+	}
+	digest, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.New("invalid checksum digest")
+	}
+
+	return &request.PartChecksum{
+		Algorithm: algorithm,
+		Digest:    digest,
+	}, nil
+}
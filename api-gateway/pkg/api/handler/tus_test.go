@@ -0,0 +1,365 @@
+package handler
+
+import (
+	"api-gateway/pkg/metrics"
+	"api-gateway/pkg/mock/mock_client"
+	"api-gateway/pkg/models/request"
+	"api-gateway/pkg/models/response"
+	"context"
+	"crypto/sha1"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"stream-sdk/pkg/sdk"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// logger to discard the logs of the tests
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// To create the status error of stream service with the reason on the error info detail
+func statusWithReason(t *testing.T, code codes.Code, reason string) error {
+
+	st, err := status.New(code, reason).WithDetails(&errdetails.ErrorInfo{Reason: reason, Domain: "stream-service"})
+	require.NoError(t, err)
+
+	return st.Err()
+}
+
+func TestGetTusStatusCode(t *testing.T) {
+
+	testCases := map[string]struct {
+		err                error
+		expectedStatusCode int
+	}{
+		"checksum_mismatch_should_return_checksum_mismatch": {
+			err:                statusWithReason(t, codes.DataLoss, sdk.ReasonChecksumMismatch),
+			expectedStatusCode: statusChecksumMismatch,
+		},
+		"size_mismatch_should_return_bad_request": {
+			err:                statusWithReason(t, codes.DataLoss, sdk.ReasonSizeMismatch),
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"offset_mismatch_should_return_conflict": {
+			err:                statusWithReason(t, codes.FailedPrecondition, sdk.ReasonOffsetMismatch),
+			expectedStatusCode: http.StatusConflict,
+		},
+		"data_loss_without_reason_should_return_unprocessable_entity": {
+			err:                status.Error(codes.DataLoss, "data loss"),
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		"not_found_should_return_not_found": {
+			err:                status.Error(codes.NotFound, "upload session not found"),
+			expectedStatusCode: http.StatusNotFound,
+		},
+		"not_status_error_should_return_internal_server_error": {
+			err:                errors.New("connection lost"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for name, test := range testCases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			assert.Equal(t, test.expectedStatusCode, getTusStatusCode(test.err))
+		})
+	}
+}
+
+// To create the server routing the tus requests to the handler as on the api server
+func newTusTestServer(client *mock_client.MockStreamClient) *echo.Echo {
+
+	handler := NewTusHandler(client, discardLogger, metrics.NewMetrics())
+
+	engine := echo.New()
+	tus := engine.Group("/tus", handler.Resumable)
+	tus.OPTIONS("", handler.Options)
+	tus.POST("", handler.Create)
+	tus.OPTIONS("/:id", handler.Options)
+	tus.HEAD("/:id", handler.Head)
+	tus.PATCH("/:id", handler.Patch)
+	tus.DELETE("/:id", handler.Delete)
+
+	return engine
+}
+
+func TestTusHandler(t *testing.T) {
+
+	size := int64(10)
+	expiresAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	expires := "Fri, 02 Jan 2026 03:04:05 GMT"
+
+	testCases := map[string]struct {
+		method             string
+		target             string
+		headers            map[string]string
+		body               string
+		buildStub          func(mockClient *mock_client.MockStreamClient)
+		expectedStatusCode int
+		expectedHeaders    map[string]string
+	}{
+		"options_should_return_protocol_details": {
+			method:             http.MethodOptions,
+			target:             "/tus",
+			buildStub:          func(mockClient *mock_client.MockStreamClient) {},
+			expectedStatusCode: http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				headerTusResumable:         tusVersion,
+				headerTusVersion:           tusVersion,
+				headerTusExtension:         tusExtensions,
+				headerTusChecksumAlgorithm: tusChecksums,
+			},
+		},
+		"unsupported_version_should_return_precondition_failed": {
+			method:             http.MethodPost,
+			target:             "/tus",
+			headers:            map[string]string{headerTusResumable: "0.2.2", headerUploadLength: "10"},
+			buildStub:          func(mockClient *mock_client.MockStreamClient) {},
+			expectedStatusCode: http.StatusPreconditionFailed,
+			expectedHeaders:    map[string]string{headerTusVersion: tusVersion},
+		},
+		"create_without_length_should_return_bad_request": {
+			method:             http.MethodPost,
+			target:             "/tus",
+			headers:            map[string]string{headerTusResumable: tusVersion},
+			buildStub:          func(mockClient *mock_client.MockStreamClient) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"create_with_negative_length_should_return_bad_request": {
+			method:             http.MethodPost,
+			target:             "/tus",
+			headers:            map[string]string{headerTusResumable: tusVersion, headerUploadLength: "-1"},
+			buildStub:          func(mockClient *mock_client.MockStreamClient) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"create_with_invalid_metadata_should_return_bad_request": {
+			method: http.MethodPost,
+			target: "/tus",
+			headers: map[string]string{headerTusResumable: tusVersion, headerUploadLength: "10",
+				headerUploadMetadata: "filename not-base64!"},
+			buildStub:          func(mockClient *mock_client.MockStreamClient) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"create_should_create_session_with_metadata": {
+			method: http.MethodPost,
+			target: "/tus",
+			headers: map[string]string{headerTusResumable: tusVersion, headerUploadLength: "10",
+				headerUploadMetadata: "filename ZmlsZS50eHQ=,filetype dGV4dC9wbGFpbg==,is_confidential"},
+			buildStub: func(mockClient *mock_client.MockStreamClient) {
+				mockClient.EXPECT().CreateUploadSession(gomock.Any(), request.FileDetails{
+					Name: "file.txt", ContentType: "text/plain", Size: &size,
+				}).Times(1).Return("session_id", nil)
+				mockClient.EXPECT().GetUploadSession(gomock.Any(), "session_id").Times(1).
+					Return(response.UploadSession{ID: "session_id", Size: &size, ExpiresAt: expiresAt}, nil)
+			},
+			expectedStatusCode: http.StatusCreated,
+			expectedHeaders: map[string]string{
+				echo.HeaderLocation: "/tus/session_id",
+				headerUploadExpires: expires,
+			},
+		},
+		"create_empty_file_should_complete_upload": {
+			method:  http.MethodPost,
+			target:  "/tus",
+			headers: map[string]string{headerTusResumable: tusVersion, headerUploadLength: "0"},
+			buildStub: func(mockClient *mock_client.MockStreamClient) {
+				empty := int64(0)
+				mockClient.EXPECT().CreateUploadSession(gomock.Any(), request.FileDetails{
+					Name: defaultTusFileName, ContentType: defaultTusContentType, Size: &empty,
+				}).Times(1).Return("session_id", nil)
+				mockClient.EXPECT().UploadSessionPart(gomock.Any(), "session_id", int64(0), nil, gomock.Any()).
+					Times(1).Return(response.UploadSession{ID: "session_id", Completed: true}, nil)
+				mockClient.EXPECT().GetUploadSession(gomock.Any(), "session_id").Times(1).
+					Return(response.UploadSession{ID: "session_id", Completed: true}, nil)
+			},
+			expectedStatusCode: http.StatusCreated,
+			expectedHeaders: map[string]string{
+				echo.HeaderLocation: "/tus/session_id",
+				headerUploadExpires: "",
+			},
+		},
+		"head_should_return_offset_and_length": {
+			method:  http.MethodHead,
+			target:  "/tus/session_id",
+			headers: map[string]string{headerTusResumable: tusVersion},
+			buildStub: func(mockClient *mock_client.MockStreamClient) {
+				mockClient.EXPECT().GetUploadSession(gomock.Any(), "session_id").Times(1).
+					Return(response.UploadSession{ID: "session_id", Offset: 4, Size: &size, ExpiresAt: expiresAt}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedHeaders: map[string]string{
+				headerUploadOffset:      "4",
+				headerUploadLength:      "10",
+				headerUploadExpires:     expires,
+				echo.HeaderCacheControl: "no-store",
+			},
+		},
+		"head_of_unknown_session_should_return_not_found": {
+			method:  http.MethodHead,
+			target:  "/tus/session_id",
+			headers: map[string]string{headerTusResumable: tusVersion},
+			buildStub: func(mockClient *mock_client.MockStreamClient) {
+				mockClient.EXPECT().GetUploadSession(gomock.Any(), "session_id").Times(1).
+					Return(response.UploadSession{}, status.Error(codes.NotFound, "upload session not found"))
+			},
+			expectedStatusCode: http.StatusNotFound,
+		},
+		"patch_with_invalid_content_type_should_return_unsupported_media_type": {
+			method: http.MethodPatch,
+			target: "/tus/session_id",
+			headers: map[string]string{headerTusResumable: tusVersion, headerUploadOffset: "0",
+				echo.HeaderContentType: "application/octet-stream"},
+			body:               "data",
+			buildStub:          func(mockClient *mock_client.MockStreamClient) {},
+			expectedStatusCode: http.StatusUnsupportedMediaType,
+		},
+		"patch_without_offset_should_return_bad_request": {
+			method:             http.MethodPatch,
+			target:             "/tus/session_id",
+			headers:            map[string]string{headerTusResumable: tusVersion, echo.HeaderContentType: tusOffsetStreamType},
+			body:               "data",
+			buildStub:          func(mockClient *mock_client.MockStreamClient) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"patch_with_invalid_checksum_should_return_bad_request": {
+			method: http.MethodPatch,
+			target: "/tus/session_id",
+			headers: map[string]string{headerTusResumable: tusVersion, headerUploadOffset: "0",
+				echo.HeaderContentType: tusOffsetStreamType, headerUploadChecksum: "sha1"},
+			body:               "data",
+			buildStub:          func(mockClient *mock_client.MockStreamClient) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"patch_should_upload_part_from_offset": {
+			method: http.MethodPatch,
+			target: "/tus/session_id",
+			headers: map[string]string{headerTusResumable: tusVersion, headerUploadOffset: "4",
+				echo.HeaderContentType: tusOffsetStreamType,
+				headerUploadChecksum:   "sha1 oXyaqmHoChv3HQ2FCvTluqmAC70="},
+			body: "data",
+			buildStub: func(mockClient *mock_client.MockStreamClient) {
+				digest := sha1.Sum([]byte("data"))
+				checksum := &request.PartChecksum{Algorithm: "sha1", Digest: digest[:]}
+				mockClient.EXPECT().UploadSessionPart(gomock.Any(), "session_id", int64(4), checksum, gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, sessionID string, offset int64,
+						checksum *request.PartChecksum, body io.Reader) (response.UploadSession, error) {
+						data, err := io.ReadAll(body)
+						if err != nil || string(data) != "data" {
+							return response.UploadSession{}, errors.New("unexpected body")
+						}
+						return response.UploadSession{ID: sessionID, Offset: 8, Size: &size}, nil
+					})
+				mockClient.EXPECT().GetUploadSession(gomock.Any(), "session_id").Times(1).
+					Return(response.UploadSession{ID: "session_id", Offset: 8, Size: &size, ExpiresAt: expiresAt}, nil)
+			},
+			expectedStatusCode: http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				headerUploadOffset:  "8",
+				headerUploadExpires: expires,
+			},
+		},
+		"patch_completing_upload_should_not_return_expires": {
+			method: http.MethodPatch,
+			target: "/tus/session_id",
+			headers: map[string]string{headerTusResumable: tusVersion, headerUploadOffset: "8",
+				echo.HeaderContentType: tusOffsetStreamType},
+			body: "da",
+			buildStub: func(mockClient *mock_client.MockStreamClient) {
+				mockClient.EXPECT().UploadSessionPart(gomock.Any(), "session_id", int64(8), nil, gomock.Any()).
+					Times(1).Return(response.UploadSession{ID: "session_id", Offset: 10, Completed: true}, nil)
+			},
+			expectedStatusCode: http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				headerUploadOffset:  "10",
+				headerUploadExpires: "",
+			},
+		},
+		"patch_with_offset_mismatch_should_return_conflict": {
+			method: http.MethodPatch,
+			target: "/tus/session_id",
+			headers: map[string]string{headerTusResumable: tusVersion, headerUploadOffset: "0",
+				echo.HeaderContentType: tusOffsetStreamType},
+			body: "data",
+			buildStub: func(mockClient *mock_client.MockStreamClient) {
+				mockClient.EXPECT().UploadSessionPart(gomock.Any(), "session_id", int64(0), nil, gomock.Any()).
+					Times(1).
+					Return(response.UploadSession{}, statusWithReason(t, codes.FailedPrecondition, sdk.ReasonOffsetMismatch))
+			},
+			expectedStatusCode: http.StatusConflict,
+		},
+		"patch_with_checksum_mismatch_should_return_checksum_mismatch": {
+			method: http.MethodPatch,
+			target: "/tus/session_id",
+			headers: map[string]string{headerTusResumable: tusVersion, headerUploadOffset: "0",
+				echo.HeaderContentType: tusOffsetStreamType, headerUploadChecksum: "sha1 AAAA"},
+			body: "data",
+			buildStub: func(mockClient *mock_client.MockStreamClient) {
+				mockClient.EXPECT().UploadSessionPart(gomock.Any(), "session_id", int64(0), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(response.UploadSession{}, statusWithReason(t, codes.DataLoss, sdk.ReasonChecksumMismatch))
+			},
+			expectedStatusCode: statusChecksumMismatch,
+		},
+		"delete_should_terminate_upload": {
+			method:  http.MethodDelete,
+			target:  "/tus/session_id",
+			headers: map[string]string{headerTusResumable: tusVersion},
+			buildStub: func(mockClient *mock_client.MockStreamClient) {
+				mockClient.EXPECT().DeleteUploadSession(gomock.Any(), "session_id").Times(1).Return(nil)
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		"delete_of_completed_upload_should_return_conflict": {
+			method:  http.MethodDelete,
+			target:  "/tus/session_id",
+			headers: map[string]string{headerTusResumable: tusVersion},
+			buildStub: func(mockClient *mock_client.MockStreamClient) {
+				mockClient.EXPECT().DeleteUploadSession(gomock.Any(), "session_id").Times(1).
+					Return(status.Error(codes.FailedPrecondition, "upload session already completed"))
+			},
+			expectedStatusCode: http.StatusConflict,
+		},
+	}
+
+	for name, test := range testCases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			ctl := gomock.NewController(t)
+			mockClient := mock_client.NewMockStreamClient(ctl)
+			test.buildStub(mockClient)
+
+			req := httptest.NewRequest(test.method, test.target, strings.NewReader(test.body))
+			for key, value := range test.headers {
+				req.Header.Set(key, value)
+			}
+			rec := httptest.NewRecorder()
+			newTusTestServer(mockClient).ServeHTTP(rec, req)
+
+			assert.Equal(t, test.expectedStatusCode, rec.Code)
+			// every response should carry the protocol version
+			assert.Equal(t, tusVersion, rec.Header().Get(headerTusResumable))
+			for key, value := range test.expectedHeaders {
+				assert.Equal(t, value, rec.Header().Get(key), key)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"api-gateway/pkg/auth/interfaces"
+	"net/http"
+	"stream-sdk/pkg/sdk"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// To authenticate the requests by the bearer token, the subject of the token forwarded to stream service
+// as the owner of the request
+func Authenticate(verifier interfaces.TokenVerifier) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+
+			// the preflight and tus discovery requests carry no credentials
+			if ctx.Request().Method == http.MethodOptions {
+				return next(ctx)
+			}
+
+			scheme, token, _ := strings.Cut(ctx.Request().Header.Get(echo.HeaderAuthorization), " ")
+			if !strings.EqualFold(scheme, "Bearer") || token == "" {
+				ctx.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+				return ctx.JSON(http.StatusUnauthorized, echo.Map{
+					"message": "Missing bearer token",
+				})
+			}
+
+			// the reason not returned to the client, not to help forging the tokens
+			subject, err := verifier.Verify(token)
+			if err != nil {
+				ctx.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+				return ctx.JSON(http.StatusUnauthorized, echo.Map{
+					"message": "Invalid bearer token",
+				})
+			}
+
+			request := ctx.Request()
+			ctx.SetRequest(request.WithContext(sdk.ContextWithOwner(request.Context(), subject)))
+
+			return next(ctx)
+		}
+	}
+}
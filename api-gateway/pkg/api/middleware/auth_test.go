@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"stream-sdk/pkg/sdk"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// token verifier of the function
+type verifierFunc func(token string) (string, error)
+
+func (f verifierFunc) Verify(token string) (string, error) {
+	return f(token)
+}
+
+func TestAuthenticate(t *testing.T) {
+
+	verifier := verifierFunc(func(token string) (string, error) {
+		if token != "valid" {
+			return "", errors.New("token signature is invalid: crypto/rsa: verification error")
+		}
+		return "owner", nil
+	})
+
+	testCases := map[string]struct {
+		method                  string
+		authorization           string
+		expectedStatus          int
+		expectedBody            string
+		expectedWWWAuthenticate string
+	}{
+		"valid_token_should_forward_owner": {
+			method:         http.MethodGet,
+			authorization:  "Bearer valid",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "owner",
+		},
+		"scheme_should_be_case_insensitive": {
+			method:         http.MethodGet,
+			authorization:  "bearer valid",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "owner",
+		},
+		"options_should_not_require_token": {
+			method:         http.MethodOptions,
+			expectedStatus: http.StatusOK,
+		},
+		"missing_token_should_return_unauthorized": {
+			method:                  http.MethodGet,
+			expectedStatus:          http.StatusUnauthorized,
+			expectedBody:            `{"message":"Missing bearer token"}` + "\n",
+			expectedWWWAuthenticate: "Bearer",
+		},
+		"basic_auth_should_return_unauthorized": {
+			method:                  http.MethodGet,
+			authorization:           "Basic dXNlcjpwYXNz",
+			expectedStatus:          http.StatusUnauthorized,
+			expectedBody:            `{"message":"Missing bearer token"}` + "\n",
+			expectedWWWAuthenticate: "Bearer",
+		},
+		"invalid_token_should_return_generic_unauthorized": {
+			method:                  http.MethodGet,
+			authorization:           "Bearer invalid",
+			expectedStatus:          http.StatusUnauthorized,
+			expectedBody:            `{"message":"Invalid bearer token"}` + "\n",
+			expectedWWWAuthenticate: `Bearer error="invalid_token"`,
+		},
+	}
+
+	for name, test := range testCases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			engine := echo.New()
+			engine.Use(Authenticate(verifier))
+			handler := func(ctx echo.Context) error {
+				return ctx.String(http.StatusOK, sdk.OwnerFromContext(ctx.Request().Context()))
+			}
+			engine.GET("/files", handler)
+			engine.OPTIONS("/files", handler)
+
+			req := httptest.NewRequest(test.method, "/files", nil)
+			if test.authorization != "" {
+				req.Header.Set(echo.HeaderAuthorization, test.authorization)
+			}
+			rec := httptest.NewRecorder()
+			engine.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.expectedStatus, rec.Code)
+			assert.Equal(t, test.expectedBody, rec.Body.String())
+			assert.Equal(t, test.expectedWWWAuthenticate, rec.Header().Get(echo.HeaderWWWAuthenticate))
+		})
+	}
+}
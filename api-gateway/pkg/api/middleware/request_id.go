@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"stream-sdk/pkg/sdk"
+
+	"github.com/labstack/echo/v4"
+)
+
+// max length of a request id accepted from the request header
+const maxRequestIDLength = 128
+
+// To set the id of the request on the response header and forward it to stream service, the id of the
+// request header used if valid, so the id of a proxy in front of the gateway kept on the logs
+func RequestID() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+
+			id := ctx.Request().Header.Get(echo.HeaderXRequestID)
+			if !validRequestID(id) {
+				id = newRequestID()
+			}
+			ctx.Response().Header().Set(echo.HeaderXRequestID, id)
+
+			request := ctx.Request()
+			ctx.SetRequest(request.WithContext(sdk.ContextWithRequestID(request.Context(), id)))
+
+			return next(ctx)
+		}
+	}
+}
+
+// To generate a random request id of 128 bits as hex
+func newRequestID() string {
+
+	id := make([]byte, 16)
+	// never fails on the supported platforms
+	rand.Read(id)
+
+	return hex.EncodeToString(id)
+}
+
+// To check the request id has only the printable characters allowed on metadata, without spaces
+func validRequestID(id string) bool {
+
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		if c < '!' || c > '~' {
+			return false
+		}
+	}
+
+	return true
+}
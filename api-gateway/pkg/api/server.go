@@ -2,7 +2,10 @@ package api
 
 import (
 	"api-gateway/pkg/api/handler/interfaces"
+	"api-gateway/pkg/api/middleware"
+	authinterface "api-gateway/pkg/auth/interfaces"
 	"api-gateway/pkg/config"
+	"api-gateway/pkg/metrics"
 
 	"github.com/labstack/echo/v4"
 )
@@ -13,11 +16,36 @@ type Server struct {
 }
 
 // NewServerHTTP creates a new server with given handler functions
-func NewServerHTTP(cfg config.Config, streamHandler interfaces.StreamHandler) *Server {
+func NewServerHTTP(cfg config.Config, streamHandler interfaces.StreamHandler,
+	tusHandler interfaces.TusHandler, verifier authinterface.TokenVerifier, metrics *metrics.Metrics) *Server {
 
 	engine := echo.New()
 
-	engine.POST("/upload", streamHandler.Upload)
+	// the id of the request forwarded to stream service to correlate the logs
+	engine.Use(middleware.RequestID())
+
+	// metrics scraped without the credentials of a user
+	engine.GET("/metrics", echo.WrapHandler(metrics.Handler()))
+
+	// all the files owned by the authenticated subject
+	api := engine.Group("", middleware.Authenticate(verifier))
+
+	api.POST("/upload", streamHandler.Upload)
+	api.PUT("/files/:name", streamHandler.UploadRaw)
+	api.GET("/files", streamHandler.ListFiles)
+	api.GET("/files/:id", streamHandler.Download)
+	api.GET("/files/:id/meta", streamHandler.GetFile)
+	api.DELETE("/files/:id", streamHandler.DeleteFile)
+	api.POST("/files/:id/restore", streamHandler.RestoreFile)
+
+	// tus resumable uploads
+	tus := api.Group("/tus", tusHandler.Resumable)
+	tus.OPTIONS("", tusHandler.Options)
+	tus.POST("", tusHandler.Create)
+	tus.OPTIONS("/:id", tusHandler.Options)
+	tus.HEAD("/:id", tusHandler.Head)
+	tus.PATCH("/:id", tusHandler.Patch)
+	tus.DELETE("/:id", tusHandler.Delete)
 
 	return &Server{
 		engine: engine,
@@ -0,0 +1,6 @@
+package interfaces
+
+type TokenVerifier interface {
+	// returns the subject of the token if the signature and the claims are valid
+	Verify(token string) (string, error)
+}
@@ -0,0 +1,225 @@
+package auth
+
+import (
+	"api-gateway/pkg/logger"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var ErrKeyNotFound = errors.New("no key found to verify token")
+
+// json web key (rfc 7517) with the fields of the rsa, ec and okp public keys
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type publicKey struct {
+	id  string
+	alg string // empty if the key not restricted to an algorithm
+	key crypto.PublicKey
+}
+
+// public keys to verify the tokens, found by the key id on the token header. reloaded from the jwks file
+// if modified, so the rotated keys used without a restart
+type keySet struct {
+	path   string
+	logger *slog.Logger
+
+	mu      sync.Mutex
+	keys    []publicKey
+	modTime time.Time
+}
+
+// To create the key set of the jwks file with the keys loaded
+func newKeySet(path string, logger *slog.Logger) (*keySet, error) {
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read jwks file: %w", err)
+	}
+	keys, err := loadJWKS(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &keySet{path: path, logger: logger, keys: keys, modTime: info.ModTime()}, nil
+}
+
+// To load the public keys from the jwks file, the keys not used for signature are skipped
+func loadJWKS(path string) ([]publicKey, error) {
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read jwks file: %w", err)
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &jwks); err != nil {
+		return nil, fmt.Errorf("failed to parse jwks file: %w", err)
+	}
+
+	var keys []publicKey
+	for i, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %d on jwks file: %w", i, err)
+		}
+		keys = append(keys, publicKey{id: jwk.Kid, alg: jwk.Alg, key: key})
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no signing keys on jwks file")
+	}
+
+	return keys, nil
+}
+
+// To get the keys, reloaded if the file modified since loaded.
+// the loaded keys kept if the reload failed, the file may be partially written on rotation
+func (s *keySet) current() []publicKey {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info, err := os.Stat(s.path)
+	if err == nil && info.ModTime().Equal(s.modTime) {
+		return s.keys
+	}
+
+	var keys []publicKey
+	if err == nil {
+		keys, err = loadJWKS(s.path)
+	}
+	if err != nil {
+		s.logger.Error("failed to reload jwks file, using the loaded keys", logger.KeyError, err)
+		return s.keys
+	}
+	s.keys, s.modTime = keys, info.ModTime()
+
+	return keys
+}
+
+// To find the key to verify the token by the key id, the only key used if the token has no key id
+func (s *keySet) find(token *jwt.Token) (interface{}, error) {
+
+	keys := s.current()
+
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" && len(keys) > 1 {
+		return nil, fmt.Errorf("%w: token has no key id", ErrKeyNotFound)
+	}
+
+	for _, key := range keys {
+		if kid != "" && key.id != kid {
+			continue
+		}
+		if key.alg != "" && key.alg != token.Method.Alg() {
+			return nil, fmt.Errorf("%w: key %q not allowed for %s", ErrKeyNotFound, kid, token.Method.Alg())
+		}
+		return key.key, nil
+	}
+
+	return nil, fmt.Errorf("%w: unknown key id %q", ErrKeyNotFound, kid)
+}
+
+// To convert the json web key to the public key of its type
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeKeyParam(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeKeyParam(k.E)
+		if err != nil {
+			return nil, err
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() < 2 || exponent.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid rsa exponent")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeKeyParam(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeKeyParam(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(key.X, key.Y) {
+			return nil, errors.New("ec point not on curve")
+		}
+		return key, nil
+
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeKeyParam(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid ed25519 key size")
+		}
+		return ed25519.PublicKey(x), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+// To decode the base64url encoded parameter of the key
+func decodeKeyParam(param string) ([]byte, error) {
+
+	if param == "" {
+		return nil, errors.New("missing key parameter")
+	}
+	data, err := base64.RawURLEncoding.DecodeString(param)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key parameter: %w", err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,107 @@
+package auth
+
+import (
+	"api-gateway/pkg/config"
+	"crypto/rand"
+	"crypto/rsa"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadJWKS(t *testing.T) {
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		data          string
+		expectedError bool
+	}{
+		"invalid_json_should_return_error": {
+			data:          "{",
+			expectedError: true,
+		},
+		"no_keys_should_return_error": {
+			data:          `{"keys":[]}`,
+			expectedError: true,
+		},
+		"only_encryption_keys_should_return_error": {
+			data:          `{"keys":[{"kty":"RSA","use":"enc","n":"AQAB","e":"AQAB"}]}`,
+			expectedError: true,
+		},
+		"unsupported_key_type_should_return_error": {
+			data:          `{"keys":[{"kty":"oct","k":"c2VjcmV0"}]}`,
+			expectedError: true,
+		},
+		"ec_point_not_on_curve_should_return_error": {
+			data:          `{"keys":[{"kty":"EC","crv":"P-256","x":"AQ","y":"AQ"}]}`,
+			expectedError: true,
+		},
+	}
+
+	for name, test := range testCases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "jwks.json")
+			require.NoError(t, os.WriteFile(path, []byte(test.data), 0o600))
+
+			_, err := loadJWKS(path)
+			assert.Equal(t, test.expectedError, err != nil)
+		})
+	}
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, path, time.Now(), rsaJWK(&key.PublicKey, "key-1"))
+	keys, err := loadJWKS(path)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Equal(t, "key-1", keys[0].id)
+	assert.Equal(t, &key.PublicKey, keys[0].key)
+}
+
+func TestKeySetReload(t *testing.T) {
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	rotatedKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	loadedAt := time.Now().Add(-time.Minute)
+	writeJWKS(t, path, loadedAt, rsaJWK(&key.PublicKey, "key-1"))
+
+	verifier, err := NewTokenVerifier(config.Config{AuthJWKSFile: path}, discardLogger)
+	require.NoError(t, err)
+
+	_, err = verifier.Verify(signRS256(t, rotatedKey, "key-2", validClaims()))
+	assert.ErrorIs(t, err, ErrKeyNotFound, "rotated key should not be known before the file modified")
+
+	// the keys of the rotated file should be used without a restart
+	writeJWKS(t, path, loadedAt.Add(time.Second), rsaJWK(&key.PublicKey, "key-1"),
+		rsaJWK(&rotatedKey.PublicKey, "key-2"))
+
+	subject, err := verifier.Verify(signRS256(t, rotatedKey, "key-2", validClaims()))
+	assert.NoError(t, err)
+	assert.Equal(t, "owner", subject)
+
+	// the loaded keys should be kept if the file is invalid
+	require.NoError(t, os.WriteFile(path, []byte(`{"keys":`), 0o600))
+	require.NoError(t, os.Chtimes(path, loadedAt.Add(time.Second*2), loadedAt.Add(time.Second*2)))
+
+	_, err = verifier.Verify(signRS256(t, rotatedKey, "key-2", validClaims()))
+	assert.NoError(t, err)
+
+	// the removed key should not be accepted after the reload
+	writeJWKS(t, path, loadedAt.Add(time.Second*3), rsaJWK(&rotatedKey.PublicKey, "key-2"))
+
+	_, err = verifier.Verify(signRS256(t, key, "key-1", validClaims()))
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+}
@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"api-gateway/pkg/auth/interfaces"
+	"api-gateway/pkg/config"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrMissingSubject    = errors.New("token has no subject")
+	ErrMissingExpiration = errors.New("token has no expiration time")
+)
+
+// signing methods allowed for each kind of key, so a token can't choose a method the key not meant for
+var (
+	hmacMethods      = []string{"HS256", "HS384", "HS512"}
+	publicKeyMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512",
+		"EdDSA"}
+)
+
+type tokenVerifier struct {
+	parser  *jwt.Parser
+	keyFunc jwt.Keyfunc
+}
+
+// To create the verifier of the bearer tokens with the keys of the jwks file or with the hmac secret
+func NewTokenVerifier(cfg config.Config, logger *slog.Logger) (interfaces.TokenVerifier, error) {
+
+	var (
+		methods []string
+		keyFunc jwt.Keyfunc
+	)
+	switch {
+	case cfg.AuthJWKSFile != "":
+		keys, err := newKeySet(cfg.AuthJWKSFile, logger)
+		if err != nil {
+			return nil, err
+		}
+		methods, keyFunc = publicKeyMethods, keys.find
+	case cfg.AuthHMACSecret != "":
+		secret := []byte(cfg.AuthHMACSecret)
+		methods = hmacMethods
+		keyFunc = func(*jwt.Token) (interface{}, error) {
+			return secret, nil
+		}
+	default:
+		return nil, errors.New("either jwks file or hmac secret required to verify tokens")
+	}
+
+	opts := []jwt.ParserOption{jwt.WithValidMethods(methods)}
+	if cfg.AuthIssuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.AuthIssuer))
+	}
+	if cfg.AuthAudience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.AuthAudience))
+	}
+
+	return &tokenVerifier{
+		parser:  jwt.NewParser(opts...),
+		keyFunc: keyFunc,
+	}, nil
+}
+
+func (v *tokenVerifier) Verify(token string) (string, error) {
+
+	var claims jwt.RegisteredClaims
+	if _, err := v.parser.ParseWithClaims(token, &claims, v.keyFunc); err != nil {
+		return "", fmt.Errorf("invalid token: %w", err)
+	}
+
+	// the tokens never expiring are not accepted
+	if claims.ExpiresAt == nil {
+		return "", ErrMissingExpiration
+	}
+	if claims.Subject == "" {
+		return "", ErrMissingSubject
+	}
+
+	return claims.Subject, nil
+}
@@ -0,0 +1,211 @@
+package auth
+
+import (
+	"api-gateway/pkg/config"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"log/slog"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// To get the json web key of the rsa public key with the key id
+func rsaJWK(key *rsa.PublicKey, kid string) jsonWebKey {
+	return jsonWebKey{
+		Kty: "RSA",
+		Kid: kid,
+		Use: "sig",
+		Alg: "RS256",
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+// To write the jwks file of the keys with the modification time, so the modification detected on rewrite
+func writeJWKS(t *testing.T, path string, modTime time.Time, keys ...jsonWebKey) {
+
+	data, err := json.Marshal(map[string]interface{}{"keys": keys})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+// To sign the token of the claims with the rsa key of the key id
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.Claims) string {
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+
+	return signed
+}
+
+func validClaims() jwt.RegisteredClaims {
+	return jwt.RegisteredClaims{
+		Subject:   "owner",
+		Issuer:    "issuer",
+		Audience:  jwt.ClaimStrings{"api-gateway"},
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}
+}
+
+func TestTokenVerifier(t *testing.T) {
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, path, time.Now(), rsaJWK(&key.PublicKey, "key-1"))
+
+	verifier, err := NewTokenVerifier(config.Config{
+		AuthJWKSFile: path,
+		AuthIssuer:   "issuer",
+		AuthAudience: "api-gateway",
+	}, discardLogger)
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		token           func(t *testing.T) string
+		expectedSubject string
+		expectedError   error // nil if any error expected on the invalid tokens
+		expectedValid   bool
+	}{
+		"valid_token_should_return_subject": {
+			token: func(t *testing.T) string {
+				return signRS256(t, key, "key-1", validClaims())
+			},
+			expectedSubject: "owner",
+			expectedValid:   true,
+		},
+		"none_algorithm_should_return_error": {
+			token: func(t *testing.T) string {
+				token := jwt.NewWithClaims(jwt.SigningMethodNone, validClaims())
+				token.Header["kid"] = "key-1"
+				signed, err := token.SignedString(jwt.UnsafeAllowNoneSignatureType)
+				require.NoError(t, err)
+				return signed
+			},
+		},
+		"hmac_signed_with_public_key_should_return_error": {
+			token: func(t *testing.T) string {
+				// the public key known to anyone used as the hmac secret
+				der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+				require.NoError(t, err)
+				secret := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+				token := jwt.NewWithClaims(jwt.SigningMethodHS256, validClaims())
+				token.Header["kid"] = "key-1"
+				signed, err := token.SignedString(secret)
+				require.NoError(t, err)
+				return signed
+			},
+		},
+		"signed_by_other_key_should_return_error": {
+			token: func(t *testing.T) string {
+				return signRS256(t, otherKey, "key-1", validClaims())
+			},
+		},
+		"unknown_key_id_should_return_key_not_found": {
+			token: func(t *testing.T) string {
+				return signRS256(t, key, "key-2", validClaims())
+			},
+			expectedError: ErrKeyNotFound,
+		},
+		"missing_expiration_should_return_error": {
+			token: func(t *testing.T) string {
+				claims := validClaims()
+				claims.ExpiresAt = nil
+				return signRS256(t, key, "key-1", claims)
+			},
+			expectedError: ErrMissingExpiration,
+		},
+		"expired_token_should_return_error": {
+			token: func(t *testing.T) string {
+				claims := validClaims()
+				claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+				return signRS256(t, key, "key-1", claims)
+			},
+			expectedError: jwt.ErrTokenExpired,
+		},
+		"missing_subject_should_return_error": {
+			token: func(t *testing.T) string {
+				claims := validClaims()
+				claims.Subject = ""
+				return signRS256(t, key, "key-1", claims)
+			},
+			expectedError: ErrMissingSubject,
+		},
+		"issuer_mismatch_should_return_error": {
+			token: func(t *testing.T) string {
+				claims := validClaims()
+				claims.Issuer = "other-issuer"
+				return signRS256(t, key, "key-1", claims)
+			},
+			expectedError: jwt.ErrTokenInvalidIssuer,
+		},
+		"audience_mismatch_should_return_error": {
+			token: func(t *testing.T) string {
+				claims := validClaims()
+				claims.Audience = jwt.ClaimStrings{"other-service"}
+				return signRS256(t, key, "key-1", claims)
+			},
+			expectedError: jwt.ErrTokenInvalidAudience,
+		},
+	}
+
+	for name, test := range testCases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			subject, err := verifier.Verify(test.token(t))
+			if test.expectedValid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+			if test.expectedError != nil {
+				assert.ErrorIs(t, err, test.expectedError)
+			}
+			assert.Equal(t, test.expectedSubject, subject)
+		})
+	}
+}
+
+func TestTokenVerifierHMAC(t *testing.T) {
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	verifier, err := NewTokenVerifier(config.Config{AuthHMACSecret: "secret"}, discardLogger)
+	require.NoError(t, err)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, validClaims())
+	signed, err := token.SignedString([]byte("secret"))
+	require.NoError(t, err)
+
+	subject, err := verifier.Verify(signed)
+	assert.NoError(t, err)
+	assert.Equal(t, "owner", subject)
+
+	// the public key methods should not be accepted for the hmac secret
+	_, err = verifier.Verify(signRS256(t, key, "", validClaims()))
+	assert.ErrorIs(t, err, jwt.ErrTokenSignatureInvalid)
+}
@@ -2,9 +2,27 @@ package interfaces
 
 import (
 	"api-gateway/pkg/models/request"
+	"api-gateway/pkg/models/response"
 	"context"
+	"io"
+	"time"
 )
 
 type StreamClient interface {
 	Upload(ctx context.Context, file request.FileDetails) (string, error)
+	// progress func called with the total size of data stored by stream service on each ack
+	UploadWithProgress(ctx context.Context, file request.FileDetails, progress func(written int64)) (string, error)
+	Download(ctx context.Context, fileID string) (response.FileDetails, io.ReadCloser, error)
+	GetFile(ctx context.Context, fileID string) (response.FileDetails, error)
+	ListFiles(ctx context.Context, req request.ListFiles) (response.FileList, error)
+	// returns the time of the file will be permanently removed
+	DeleteFile(ctx context.Context, fileID string) (time.Time, error)
+	RestoreFile(ctx context.Context, fileID string) (response.FileDetails, error)
+	// returns the id of the created upload session
+	CreateUploadSession(ctx context.Context, file request.FileDetails) (string, error)
+	GetUploadSession(ctx context.Context, sessionID string) (response.UploadSession, error)
+	// upload the body as a part of the session from the offset, the data discarded if checksum provided and not matching
+	UploadSessionPart(ctx context.Context, sessionID string, offset int64, checksum *request.PartChecksum,
+		body io.Reader) (response.UploadSession, error)
+	DeleteUploadSession(ctx context.Context, sessionID string) error
 }
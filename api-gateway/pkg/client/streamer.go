@@ -4,93 +4,220 @@ import (
 	"api-gateway/pkg/client/interfaces"
 	"api-gateway/pkg/config"
 	"api-gateway/pkg/models/request"
-	"api-gateway/pkg/pb"
+	"api-gateway/pkg/models/response"
 	"context"
 	"fmt"
 	"io"
-	"log"
-
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"log/slog"
+	"os"
+	"stream-sdk/pkg/sdk"
+	"time"
 )
 
 type streamClient struct {
-	client pb.StreamServiceClient
+	client             *sdk.Client
+	multipartThreshold int64
 }
 
-var streamSize = 500
-
-func NewStreamClient(cfg config.Config) (interfaces.StreamClient, error) {
+func NewStreamClient(cfg config.Config, logger *slog.Logger) (interfaces.StreamClient, error) {
 
 	addr := fmt.Sprintf("%s:%s", cfg.StreamServiceHost, cfg.StreamServicePort)
 
-	cc, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	opts := []sdk.Option{
+		sdk.WithUploadTimeout(cfg.UploadTimeout),
+		sdk.WithRequestTimeout(cfg.RequestTimeout),
+		sdk.WithMultipart(cfg.MultipartThreshold, cfg.MultipartPartSize, cfg.MultipartConcurrency),
+		sdk.WithChunkSize(cfg.ChunkSize, cfg.AdaptiveChunkSize),
+		sdk.WithLogger(logger),
+		// the certificates reloaded on the new connections, so renewed without a restart
+		sdk.WithTLS(sdk.TLSConfig{
+			CertFile:   cfg.TLSCertFile,
+			KeyFile:    cfg.TLSKeyFile,
+			CAFile:     cfg.TLSCAFile,
+			ServerName: cfg.TLSServerName,
+		}),
+	}
+
+	client, err := sdk.NewClient(addr, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to dial to stream service: %w", err)
+		return nil, err
 	}
-	client := pb.NewStreamServiceClient(cc)
 
 	return &streamClient{
-		client: client,
+		client:             client,
+		multipartThreshold: cfg.MultipartThreshold,
 	}, nil
 }
 
+// To convert the file details of the upload to the upload options
+func uploadOptions(fileDetails request.FileDetails) []sdk.UploadOption {
+
+	var opts []sdk.UploadOption
+	if fileDetails.Size != nil {
+		opts = append(opts, sdk.WithSize(*fileDetails.Size))
+	}
+	if fileDetails.SHA256 != "" {
+		opts = append(opts, sdk.WithSHA256(fileDetails.SHA256))
+	}
+
+	return opts
+}
+
 func (c *streamClient) Upload(ctx context.Context, fileDetails request.FileDetails) (string, error) {
 
-	// get the stream service
-	streamSvc, err := c.client.Upload(ctx)
+	// the request body read only once, so the body of a large file spooled to a temp file
+	// to read the parts at offsets and upload them over parallel streams
+	body := fileDetails.Body
+	if c.multipartThreshold > 0 && fileDetails.Size != nil && *fileDetails.Size >= c.multipartThreshold {
+		file, err := spoolBody(body)
+		if err != nil {
+			return "", err
+		}
+		defer func() {
+			file.Close()
+			os.Remove(file.Name())
+		}()
+		body = file
+	}
+
+	res, err := c.client.Upload(ctx, fileDetails.Name, fileDetails.ContentType, body,
+		uploadOptions(fileDetails)...)
+	if err != nil {
+		return "", err
+	}
+
+	return res.ID, nil
+}
+
+// To write all the data of the body to a temp file, the file removed if failed
+func spoolBody(body io.Reader) (*os.File, error) {
+
+	file, err := os.CreateTemp("", "upload-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file to spool upload: %w", err)
+	}
+
+	if _, err := io.Copy(file, body); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return nil, fmt.Errorf("failed to spool upload to temp file: %w", err)
+	}
+
+	return file, nil
+}
+
+func (c *streamClient) UploadWithProgress(ctx context.Context, fileDetails request.FileDetails,
+	progress func(written int64)) (string, error) {
+
+	res, err := c.client.UploadWithProgress(ctx, fileDetails.Name, fileDetails.ContentType, fileDetails.Body,
+		progress, uploadOptions(fileDetails)...)
+	if err != nil {
+		return "", err
+	}
+
+	return res.ID, nil
+}
+
+func (c *streamClient) Download(ctx context.Context, fileID string) (response.FileDetails, io.ReadCloser, error) {
+
+	file, details, err := c.client.Download(ctx, fileID)
 	if err != nil {
-		return "", fmt.Errorf("failed to call upload method for stream client: %w", err)
+		return response.FileDetails{}, nil, err
 	}
 
-	fileRequest := pb.UploadRequest_Info{
+	return toFileDetailsResponse(details), file, nil
+}
+
+func (c *streamClient) GetFile(ctx context.Context, fileID string) (response.FileDetails, error) {
 
-		Info: &pb.FileMetaData{
-			Name:        fileDetails.Name,
-			ContentType: fileDetails.ContentType,
-		},
+	details, err := c.client.GetFile(ctx, fileID)
+	if err != nil {
+		return response.FileDetails{}, err
 	}
 
-	// first send file meta data
-	err = streamSvc.Send(&pb.UploadRequest{
-		File: &fileRequest,
+	return toFileDetailsResponse(details), nil
+}
+
+func (c *streamClient) ListFiles(ctx context.Context, req request.ListFiles) (response.FileList, error) {
+
+	list, err := c.client.ListFiles(ctx, sdk.ListOptions{
+		Cursor:         req.Cursor,
+		Limit:          req.Limit,
+		ContentType:    req.ContentType,
+		NamePrefix:     req.NamePrefix,
+		UploadedAfter:  req.UploadedAfter,
+		UploadedBefore: req.UploadedBefore,
+		SortBy:         req.SortBy,
+		Descending:     req.Descending,
+		Deleted:        req.Deleted,
 	})
 	if err != nil {
-		return "", fmt.Errorf("failed to send file details: %w", err)
+		return response.FileList{}, err
+	}
+
+	fileList := response.FileList{
+		Files:      make([]response.FileDetails, len(list.Files)),
+		NextCursor: list.NextCursor,
+	}
+	for i, file := range list.Files {
+		fileList.Files[i] = toFileDetailsResponse(file)
 	}
 
-	file, err := fileDetails.FileHeader.Open()
+	return fileList, nil
+}
+
+func (c *streamClient) DeleteFile(ctx context.Context, fileID string) (time.Time, error) {
+	return c.client.DeleteFile(ctx, fileID)
+}
+
+func (c *streamClient) RestoreFile(ctx context.Context, fileID string) (response.FileDetails, error) {
+
+	details, err := c.client.RestoreFile(ctx, fileID)
 	if err != nil {
-		return "", fmt.Errorf("failed to open file: %w", err)
+		return response.FileDetails{}, err
 	}
 
-	data := make([]byte, streamSize)
-	for {
-		// read file data
-		_, err := file.Read(data)
-		if err != nil {
-			// if file read completed break
-			if err == io.EOF {
-				log.Println("file read completed and stop streaming..")
-				break
-			}
-			return "", fmt.Errorf("failed to read from file: %w", err)
-		}
-		// send stream data
-		streamData := pb.UploadRequest{
-			File: &pb.UploadRequest_Data{Data: data},
-		}
-		err = streamSvc.Send(&streamData)
-		if err != nil {
-			return "", fmt.Errorf("failed to send stream to server: %w", err)
+	return toFileDetailsResponse(details), nil
+}
+
+func (c *streamClient) CreateUploadSession(ctx context.Context, fileDetails request.FileDetails) (string, error) {
+	return c.client.CreateUploadSession(ctx, fileDetails.Name, fileDetails.ContentType,
+		uploadOptions(fileDetails)...)
+}
+
+func (c *streamClient) GetUploadSession(ctx context.Context, sessionID string) (response.UploadSession, error) {
+
+	session, err := c.client.GetUploadSession(ctx, sessionID)
+	if err != nil {
+		return response.UploadSession{}, err
+	}
+
+	return response.UploadSession(session), nil
+}
+
+func (c *streamClient) UploadSessionPart(ctx context.Context, sessionID string, offset int64,
+	checksum *request.PartChecksum, body io.Reader) (response.UploadSession, error) {
+
+	var partChecksum *sdk.PartChecksum
+	if checksum != nil {
+		partChecksum = &sdk.PartChecksum{
+			Algorithm: checksum.Algorithm,
+			Digest:    checksum.Digest,
 		}
 	}
 
-	// close streaming
-	res, err := streamSvc.CloseAndRecv()
+	session, err := c.client.UploadSessionPart(ctx, sessionID, offset, partChecksum, body)
 	if err != nil {
-		return "", fmt.Errorf("failed to close streaming: %w", err)
+		return response.UploadSession{}, err
 	}
 
-	return res.GetId(), nil
+	return response.UploadSession(session), nil
+}
+
+func (c *streamClient) DeleteUploadSession(ctx context.Context, sessionID string) error {
+	return c.client.DeleteUploadSession(ctx, sessionID)
+}
+
+func toFileDetailsResponse(file sdk.FileDetails) response.FileDetails {
+	return response.FileDetails(file)
 }
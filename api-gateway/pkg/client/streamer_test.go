@@ -0,0 +1,59 @@
+package client
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpoolBody(t *testing.T) {
+
+	testCases := map[string]struct {
+		body          io.Reader
+		expectedData  string
+		expectedError error
+	}{
+		"body_should_spool_to_file": {
+			body:         iotest.HalfReader(strings.NewReader("0123456789")),
+			expectedData: "0123456789",
+		},
+		"failed_body_should_return_error": {
+			body:          io.MultiReader(strings.NewReader("01234"), iotest.ErrReader(io.ErrUnexpectedEOF)),
+			expectedError: io.ErrUnexpectedEOF,
+		},
+	}
+
+	for name, test := range testCases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			file, err := spoolBody(test.body)
+			if test.expectedError != nil {
+				assert.ErrorIs(t, err, test.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				file.Close()
+				os.Remove(file.Name())
+			})
+
+			// the parts of the spooled file read at offsets
+			data := make([]byte, 4)
+			_, err = file.ReadAt(data, 3)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedData[3:7], string(data))
+
+			info, err := file.Stat()
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(test.expectedData)), info.Size())
+		})
+	}
+}
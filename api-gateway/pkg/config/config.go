@@ -1,17 +1,64 @@
 package config
 
 import (
+	"time"
+
 	"github.com/go-playground/validator/v10"
 	"github.com/spf13/viper"
 )
 
 type Config struct {
 	ApiPort           string `mapstructure:"API_PORT"`
+	LogLevel          string `mapstructure:"LOG_LEVEL" validate:"oneof=debug info warn error"`
+	LogFormat         string `mapstructure:"LOG_FORMAT" validate:"oneof=json text"`
 	StreamServiceHost string `mapstructure:"STREAMER_SERVICE_HOST"`
 	StreamServicePort string `mapstructure:"STREAMER_SERVICE_PORT"`
+
+	// connect to the stream service over mutual tls, the client certificate identifies the client trusted to
+	// forward the owner
+	TLSCertFile   string `mapstructure:"TLS_CERT_FILE" validate:"required"`
+	TLSKeyFile    string `mapstructure:"TLS_KEY_FILE" validate:"required"`
+	TLSCAFile     string `mapstructure:"TLS_CA_FILE"`     // ca to verify the stream service, empty for system roots
+	TLSServerName string `mapstructure:"TLS_SERVER_NAME"` // name on the certificate of the stream service if not the host
+
+	UploadTimeout  time.Duration `mapstructure:"UPLOAD_TIMEOUT"`  // max time of an upload, zero for no deadline
+	RequestTimeout time.Duration `mapstructure:"REQUEST_TIMEOUT"` // max time of other requests, zero for no deadline
+
+	// min size of the files to upload as parts over parallel streams, zero to upload all files on a single stream.
+	// the body of a file not less than the threshold spooled to a temp file to read the parts at offsets
+	MultipartThreshold   int64 `mapstructure:"MULTIPART_THRESHOLD"`
+	MultipartPartSize    int64 `mapstructure:"MULTIPART_PART_SIZE" validate:"min=1"`
+	MultipartConcurrency int   `mapstructure:"MULTIPART_CONCURRENCY" validate:"min=1"` // max parts uploading at a time
+
+	// max size of the data on each chunk of upload streams, the initial size if adaptive
+	ChunkSize int `mapstructure:"CHUNK_SIZE" validate:"min=1"`
+	// grow the chunk size on each upload stream while the throughput improves
+	AdaptiveChunkSize bool `mapstructure:"ADAPTIVE_CHUNK_SIZE"`
+
+	// the bearer tokens verified either by the public keys on the jwks file or by the hmac secret
+	AuthJWKSFile   string `mapstructure:"AUTH_JWKS_FILE" validate:"required_without=AuthHMACSecret,excluded_with=AuthHMACSecret"`
+	AuthHMACSecret string `mapstructure:"AUTH_HMAC_SECRET" validate:"required_without=AuthJWKSFile"`
+	AuthIssuer     string `mapstructure:"AUTH_ISSUER"`   // required issuer of the tokens, empty to not check
+	AuthAudience   string `mapstructure:"AUTH_AUDIENCE"` // required audience of the tokens, empty to not check
 }
 
-var envs = []string{"API_PORT", "STREAMER_SERVICE_HOST", "STREAMER_SERVICE_PORT"}
+var envs = []string{"API_PORT", "STREAMER_SERVICE_HOST", "STREAMER_SERVICE_PORT", "UPLOAD_TIMEOUT", "REQUEST_TIMEOUT",
+	"MULTIPART_THRESHOLD", "MULTIPART_PART_SIZE", "MULTIPART_CONCURRENCY", "CHUNK_SIZE", "ADAPTIVE_CHUNK_SIZE",
+	"AUTH_JWKS_FILE", "AUTH_HMAC_SECRET", "AUTH_ISSUER", "AUTH_AUDIENCE",
+	"TLS_CERT_FILE", "TLS_KEY_FILE", "TLS_CA_FILE", "TLS_SERVER_NAME",
+	"LOG_LEVEL", "LOG_FORMAT"}
+
+// default values for optional envs
+var defaults = map[string]interface{}{
+	"LOG_LEVEL":             "info",
+	"LOG_FORMAT":            "json",
+	"UPLOAD_TIMEOUT":        time.Hour,
+	"REQUEST_TIMEOUT":       time.Second * 30,
+	"MULTIPART_THRESHOLD":   64 << 20,
+	"MULTIPART_PART_SIZE":   16 << 20,
+	"MULTIPART_CONCURRENCY": 4,
+	"CHUNK_SIZE":            32 << 10,
+}
 
 func LoadConfig() (Config, error) {
 	var config Config
@@ -20,6 +67,10 @@ func LoadConfig() (Config, error) {
 	viper.SetConfigFile(".env")
 	viper.ReadInConfig()
 
+	for env, value := range defaults {
+		viper.SetDefault(env, value)
+	}
+
 	for _, env := range envs {
 		if err := viper.BindEnv(env); err != nil {
 			return config, err
@@ -6,8 +6,11 @@ package di
 import (
 	"api-gateway/pkg/api"
 	"api-gateway/pkg/api/handler"
+	"api-gateway/pkg/auth"
 	"api-gateway/pkg/client"
 	"api-gateway/pkg/config"
+	"api-gateway/pkg/logger"
+	"api-gateway/pkg/metrics"
 
 	"github.com/google/wire"
 )
@@ -15,8 +18,12 @@ import (
 func InitializeAPI(cfg config.Config) (*api.Server, error) {
 
 	wire.Build(
+		logger.NewLogger,
+		metrics.NewMetrics,
 		client.NewStreamClient,
 		handler.NewStreamHandler,
+		handler.NewTusHandler,
+		auth.NewTokenVerifier,
 		api.NewServerHTTP,
 	)
 
@@ -9,18 +9,31 @@ package di
 import (
 	"api-gateway/pkg/api"
 	"api-gateway/pkg/api/handler"
+	"api-gateway/pkg/auth"
 	"api-gateway/pkg/client"
 	"api-gateway/pkg/config"
+	"api-gateway/pkg/logger"
+	"api-gateway/pkg/metrics"
 )
 
 // Injectors from wire.go:
 
 func InitializeAPI(cfg config.Config) (*api.Server, error) {
-	streamClient, err := client.NewStreamClient(cfg)
+	slogLogger, err := logger.NewLogger(cfg)
 	if err != nil {
 		return nil, err
 	}
-	streamHandler := handler.NewStreamHandler(streamClient)
-	server := api.NewServerHTTP(cfg, streamHandler)
+	streamClient, err := client.NewStreamClient(cfg, slogLogger)
+	if err != nil {
+		return nil, err
+	}
+	metricsMetrics := metrics.NewMetrics()
+	streamHandler := handler.NewStreamHandler(streamClient, slogLogger, metricsMetrics)
+	tusHandler := handler.NewTusHandler(streamClient, slogLogger, metricsMetrics)
+	tokenVerifier, err := auth.NewTokenVerifier(cfg, slogLogger)
+	if err != nil {
+		return nil, err
+	}
+	server := api.NewServerHTTP(cfg, streamHandler, tusHandler, tokenVerifier, metricsMetrics)
 	return server, nil
 }
@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"api-gateway/pkg/config"
+	"context"
+	"log/slog"
+	"os"
+	"stream-sdk/pkg/sdk"
+)
+
+// keys of the attributes logged with the logs
+const (
+	KeyFileID    = "file_id"
+	KeyRequestID = "request_id"
+	KeyOwner     = "owner"
+	KeyError     = "error"
+)
+
+// To create the logger writing to stderr on the level and format of the config,
+// the request id and owner of the context added to the logs written with the context
+func NewLogger(cfg config.Config) (*slog.Logger, error) {
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.LogLevel)); err != nil {
+		return nil, err
+	}
+	opts := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	if cfg.LogFormat == "text" {
+		handler = slog.NewTextHandler(os.Stderr, opts)
+	} else {
+		handler = slog.NewJSONHandler(os.Stderr, opts)
+	}
+
+	return slog.New(contextHandler{Handler: handler}), nil
+}
+
+// handler adding the attributes of the request on the context to the records,
+// the request id and owner set on the context by the middlewares for the calls to stream service
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+
+	if id := sdk.RequestIDFromContext(ctx); id != "" {
+		record.AddAttrs(slog.String(KeyRequestID, id))
+	}
+	if owner := sdk.OwnerFromContext(ctx); owner != "" {
+		record.AddAttrs(slog.String(KeyOwner, owner))
+	}
+
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,133 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"stream-sdk/pkg/sdk"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// reasons of the upload failures
+const (
+	ReasonTimeout     = "timeout"      // the upload deadline exceeded
+	ReasonWriteError  = "write_error"  // the data failed to send to or store by stream service
+	ReasonClientAbort = "client_abort" // the client cancelled the request or failed to send the body
+	ReasonOther       = "other"
+)
+
+// Metrics of the uploads proxied to stream service
+type Metrics struct {
+	registry *prometheus.Registry
+
+	BytesReceived  prometheus.Counter
+	BytesWritten   prometheus.Counter
+	UploadDuration prometheus.Histogram
+	ActiveStreams  prometheus.Gauge
+	UploadFailures *prometheus.CounterVec // by grpc code and reason
+}
+
+func NewMetrics() *Metrics {
+
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		BytesReceived: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "gateway_bytes_received_total",
+			Help: "Bytes of file data received on the upload requests.",
+		}),
+		BytesWritten: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "gateway_bytes_written_total",
+			Help: "Bytes of file data uploaded to stream service.",
+		}),
+		UploadDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "gateway_upload_duration_seconds",
+			Help:    "Duration of the uploads to stream service.",
+			Buckets: prometheus.ExponentialBuckets(0.01, 4, 10), // 10ms to ~45m
+		}),
+		ActiveStreams: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "gateway_active_upload_streams",
+			Help: "Uploads to stream service in progress.",
+		}),
+		UploadFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "gateway_upload_failures_total",
+			Help: "Failed uploads by grpc code and reason.",
+		}, []string{"code", "reason"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.BytesReceived,
+		m.BytesWritten,
+		m.UploadDuration,
+		m.ActiveStreams,
+		m.UploadFailures,
+	)
+
+	return m
+}
+
+// To get the handler serving the metrics on the prometheus text format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// upload observed from the start until ended, the body of the upload read through it to count the bytes received
+type Upload struct {
+	metrics *Metrics
+	body    io.Reader
+	start   time.Time
+	read    int64
+}
+
+// To start observing the upload of the body
+func (m *Metrics) StartUpload(body io.Reader) *Upload {
+
+	m.ActiveStreams.Inc()
+
+	return &Upload{
+		metrics: m,
+		body:    body,
+		start:   time.Now(),
+	}
+}
+
+func (u *Upload) Read(data []byte) (int, error) {
+
+	n, err := u.body.Read(data)
+	u.read += int64(n)
+	u.metrics.BytesReceived.Add(float64(n))
+
+	return n, err
+}
+
+// To end the upload with the error of stream service, the failure classified by the error and the request context
+func (u *Upload) End(ctx context.Context, err error) {
+
+	u.metrics.ActiveStreams.Dec()
+	u.metrics.UploadDuration.Observe(time.Since(u.start).Seconds())
+
+	if err == nil {
+		u.metrics.BytesWritten.Add(float64(u.read))
+		return
+	}
+
+	code := status.Code(err)
+	reason := ReasonOther
+	switch {
+	case errors.Is(ctx.Err(), context.Canceled), code == codes.Canceled:
+		reason = ReasonClientAbort
+	case code == codes.DeadlineExceeded:
+		reason = ReasonTimeout
+	case code == codes.Unavailable, sdk.ErrorReason(err) == sdk.ReasonStorageWrite:
+		reason = ReasonWriteError
+	}
+	u.metrics.UploadFailures.WithLabelValues(code.String(), reason).Inc()
+}
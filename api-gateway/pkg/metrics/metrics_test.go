@@ -0,0 +1,97 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUploadEnd(t *testing.T) {
+
+	storageWriteErr, err := status.New(codes.Internal, "failed to store data").WithDetails(&errdetails.ErrorInfo{
+		Reason: "STORAGE_WRITE",
+		Domain: "stream-service",
+	})
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		cancelled      bool // request context cancelled before the upload ended
+		err            error
+		expectedCode   codes.Code
+		expectedReason string // empty if no failure observed
+	}{
+		"upload_completed_should_count_bytes_written": {},
+		"storage_write_reason_should_observe_write_error": {
+			err:            storageWriteErr.Err(),
+			expectedCode:   codes.Internal,
+			expectedReason: ReasonWriteError,
+		},
+		"unavailable_should_observe_write_error": {
+			err:            status.Error(codes.Unavailable, "connection refused"),
+			expectedCode:   codes.Unavailable,
+			expectedReason: ReasonWriteError,
+		},
+		"internal_without_reason_should_observe_other": {
+			err:            status.Error(codes.Internal, "failed to save file details"),
+			expectedCode:   codes.Internal,
+			expectedReason: ReasonOther,
+		},
+		"deadline_exceeded_should_observe_timeout": {
+			err:            status.Error(codes.DeadlineExceeded, "upload idle timeout"),
+			expectedCode:   codes.DeadlineExceeded,
+			expectedReason: ReasonTimeout,
+		},
+		"request_cancelled_should_observe_client_abort": {
+			cancelled:      true,
+			err:            storageWriteErr.Err(),
+			expectedCode:   codes.Internal,
+			expectedReason: ReasonClientAbort,
+		},
+		"not_status_error_should_observe_other": {
+			err:            errors.New("failed to read body"),
+			expectedCode:   codes.Unknown,
+			expectedReason: ReasonOther,
+		},
+	}
+
+	for name, test := range testCases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if test.cancelled {
+				cancel()
+			}
+
+			m := NewMetrics()
+			upload := m.StartUpload(strings.NewReader("data"))
+			_, err := io.ReadAll(upload)
+			require.NoError(t, err)
+			upload.End(ctx, test.err)
+
+			assert.Equal(t, float64(0), testutil.ToFloat64(m.ActiveStreams))
+			assert.Equal(t, float64(4), testutil.ToFloat64(m.BytesReceived))
+			if test.expectedReason == "" {
+				assert.Equal(t, float64(4), testutil.ToFloat64(m.BytesWritten))
+				assert.Equal(t, 0, testutil.CollectAndCount(m.UploadFailures))
+				return
+			}
+			assert.Equal(t, float64(0), testutil.ToFloat64(m.BytesWritten))
+			assert.Equal(t, 1, testutil.CollectAndCount(m.UploadFailures))
+			assert.Equal(t, float64(1), testutil.ToFloat64(
+				m.UploadFailures.WithLabelValues(test.expectedCode.String(), test.expectedReason)))
+		})
+	}
+}
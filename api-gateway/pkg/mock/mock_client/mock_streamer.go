@@ -0,0 +1,204 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./pkg/client/interfaces/streamer.go
+
+// Package mock_client is a generated GoMock package.
+package mock_client
+
+import (
+	request "api-gateway/pkg/models/request"
+	response "api-gateway/pkg/models/response"
+	context "context"
+	io "io"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockStreamClient is a mock of StreamClient interface.
+type MockStreamClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockStreamClientMockRecorder
+}
+
+// MockStreamClientMockRecorder is the mock recorder for MockStreamClient.
+type MockStreamClientMockRecorder struct {
+	mock *MockStreamClient
+}
+
+// NewMockStreamClient creates a new mock instance.
+func NewMockStreamClient(ctrl *gomock.Controller) *MockStreamClient {
+	mock := &MockStreamClient{ctrl: ctrl}
+	mock.recorder = &MockStreamClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStreamClient) EXPECT() *MockStreamClientMockRecorder {
+	return m.recorder
+}
+
+// CreateUploadSession mocks base method.
+func (m *MockStreamClient) CreateUploadSession(ctx context.Context, file request.FileDetails) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUploadSession", ctx, file)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUploadSession indicates an expected call of CreateUploadSession.
+func (mr *MockStreamClientMockRecorder) CreateUploadSession(ctx, file interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUploadSession", reflect.TypeOf((*MockStreamClient)(nil).CreateUploadSession), ctx, file)
+}
+
+// DeleteFile mocks base method.
+func (m *MockStreamClient) DeleteFile(ctx context.Context, fileID string) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFile", ctx, fileID)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteFile indicates an expected call of DeleteFile.
+func (mr *MockStreamClientMockRecorder) DeleteFile(ctx, fileID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFile", reflect.TypeOf((*MockStreamClient)(nil).DeleteFile), ctx, fileID)
+}
+
+// DeleteUploadSession mocks base method.
+func (m *MockStreamClient) DeleteUploadSession(ctx context.Context, sessionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUploadSession", ctx, sessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUploadSession indicates an expected call of DeleteUploadSession.
+func (mr *MockStreamClientMockRecorder) DeleteUploadSession(ctx, sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUploadSession", reflect.TypeOf((*MockStreamClient)(nil).DeleteUploadSession), ctx, sessionID)
+}
+
+// Download mocks base method.
+func (m *MockStreamClient) Download(ctx context.Context, fileID string) (response.FileDetails, io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Download", ctx, fileID)
+	ret0, _ := ret[0].(response.FileDetails)
+	ret1, _ := ret[1].(io.ReadCloser)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Download indicates an expected call of Download.
+func (mr *MockStreamClientMockRecorder) Download(ctx, fileID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Download", reflect.TypeOf((*MockStreamClient)(nil).Download), ctx, fileID)
+}
+
+// GetFile mocks base method.
+func (m *MockStreamClient) GetFile(ctx context.Context, fileID string) (response.FileDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFile", ctx, fileID)
+	ret0, _ := ret[0].(response.FileDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFile indicates an expected call of GetFile.
+func (mr *MockStreamClientMockRecorder) GetFile(ctx, fileID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFile", reflect.TypeOf((*MockStreamClient)(nil).GetFile), ctx, fileID)
+}
+
+// GetUploadSession mocks base method.
+func (m *MockStreamClient) GetUploadSession(ctx context.Context, sessionID string) (response.UploadSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUploadSession", ctx, sessionID)
+	ret0, _ := ret[0].(response.UploadSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUploadSession indicates an expected call of GetUploadSession.
+func (mr *MockStreamClientMockRecorder) GetUploadSession(ctx, sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUploadSession", reflect.TypeOf((*MockStreamClient)(nil).GetUploadSession), ctx, sessionID)
+}
+
+// ListFiles mocks base method.
+func (m *MockStreamClient) ListFiles(ctx context.Context, req request.ListFiles) (response.FileList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFiles", ctx, req)
+	ret0, _ := ret[0].(response.FileList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFiles indicates an expected call of ListFiles.
+func (mr *MockStreamClientMockRecorder) ListFiles(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFiles", reflect.TypeOf((*MockStreamClient)(nil).ListFiles), ctx, req)
+}
+
+// RestoreFile mocks base method.
+func (m *MockStreamClient) RestoreFile(ctx context.Context, fileID string) (response.FileDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreFile", ctx, fileID)
+	ret0, _ := ret[0].(response.FileDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreFile indicates an expected call of RestoreFile.
+func (mr *MockStreamClientMockRecorder) RestoreFile(ctx, fileID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreFile", reflect.TypeOf((*MockStreamClient)(nil).RestoreFile), ctx, fileID)
+}
+
+// Upload mocks base method.
+func (m *MockStreamClient) Upload(ctx context.Context, file request.FileDetails) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upload", ctx, file)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Upload indicates an expected call of Upload.
+func (mr *MockStreamClientMockRecorder) Upload(ctx, file interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upload", reflect.TypeOf((*MockStreamClient)(nil).Upload), ctx, file)
+}
+
+// UploadSessionPart mocks base method.
+func (m *MockStreamClient) UploadSessionPart(ctx context.Context, sessionID string, offset int64, checksum *request.PartChecksum, body io.Reader) (response.UploadSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadSessionPart", ctx, sessionID, offset, checksum, body)
+	ret0, _ := ret[0].(response.UploadSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadSessionPart indicates an expected call of UploadSessionPart.
+func (mr *MockStreamClientMockRecorder) UploadSessionPart(ctx, sessionID, offset, checksum, body interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadSessionPart", reflect.TypeOf((*MockStreamClient)(nil).UploadSessionPart), ctx, sessionID, offset, checksum, body)
+}
+
+// UploadWithProgress mocks base method.
+func (m *MockStreamClient) UploadWithProgress(ctx context.Context, file request.FileDetails, progress func(int64)) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadWithProgress", ctx, file, progress)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadWithProgress indicates an expected call of UploadWithProgress.
+func (mr *MockStreamClientMockRecorder) UploadWithProgress(ctx, file, progress interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadWithProgress", reflect.TypeOf((*MockStreamClient)(nil).UploadWithProgress), ctx, file, progress)
+}
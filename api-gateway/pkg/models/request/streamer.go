@@ -1,9 +1,38 @@
 package request
 
-import "mime/multipart"
+import (
+	"io"
+	"time"
+)
 
 type FileDetails struct {
-	Name        string `validator:"required,min=3"`
-	ContentType string `validator:"required"`
-	FileHeader  *multipart.FileHeader
+	Name        string    `validator:"required,min=3"`
+	ContentType string    `validator:"required"`
+	Body        io.Reader // file data to upload
+	Size        *int64    // declared size of the file to verify the upload (optional)
+	SHA256      string    // expected sha256 digest as hex to verify the upload (optional)
+}
+
+// checksum of a part of the file uploaded to a session
+type PartChecksum struct {
+	Algorithm string // md5, sha1 or sha256
+	Digest    []byte
+}
+
+// fields to sort the file list
+const (
+	SortByUploadedAt = "uploaded_at"
+	SortByName       = "name"
+)
+
+type ListFiles struct {
+	Cursor         string
+	Limit          int
+	ContentType    string
+	NamePrefix     string
+	UploadedAfter  time.Time
+	UploadedBefore time.Time
+	SortBy         string
+	Descending     bool
+	Deleted        bool // list the deleted files (trash)
 }
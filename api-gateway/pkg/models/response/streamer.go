@@ -0,0 +1,31 @@
+package response
+
+import "time"
+
+type FileDetails struct {
+	ID            string     `json:"id"`
+	Owner         string     `json:"owner"`
+	Name          string     `json:"name"`
+	ContentType   string     `json:"content_type"`
+	UploadedAt    time.Time  `json:"uploaded_at"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty"`
+	Size          int64      `json:"size"`
+	SHA256        string     `json:"sha256"`
+	CRC32C        uint32     `json:"crc32c"`
+	Status        string     `json:"status"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+	FailureReason string     `json:"failure_reason,omitempty"`
+}
+
+type FileList struct {
+	Files      []FileDetails `json:"files"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
+
+type UploadSession struct {
+	ID        string    `json:"id"`
+	Offset    int64     `json:"offset"` // committed data size on server
+	Completed bool      `json:"completed"`
+	Size      *int64    `json:"size,omitempty"`       // declared total size of the file
+	ExpiresAt time.Time `json:"expires_at,omitempty"` // zero if the session not expire
+}
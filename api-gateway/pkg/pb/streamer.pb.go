@@ -1,327 +0,0 @@
-// Code generated by protoc-gen-go. DO NOT EDIT.
-// versions:
-// 	protoc-gen-go v1.28.1
-// 	protoc        v4.24.2
-// source: pkg/proto/streamer.proto
-
-package pb
-
-import (
-	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
-	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
-	reflect "reflect"
-	sync "sync"
-)
-
-const (
-	// Verify that this generated code is sufficiently up-to-date.
-	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
-	// Verify that runtime/protoimpl is sufficiently up-to-date.
-	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
-)
-
-// To upload the file as stream
-type UploadRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	// Types that are assignable to File:
-	//
-	//	*UploadRequest_Info
-	//	*UploadRequest_Data
-	File isUploadRequest_File `protobuf_oneof:"file"`
-}
-
-func (x *UploadRequest) Reset() {
-	*x = UploadRequest{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_proto_streamer_proto_msgTypes[0]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
-}
-
-func (x *UploadRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*UploadRequest) ProtoMessage() {}
-
-func (x *UploadRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_proto_streamer_proto_msgTypes[0]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
-	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use UploadRequest.ProtoReflect.Descriptor instead.
-func (*UploadRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_proto_streamer_proto_rawDescGZIP(), []int{0}
-}
-
-func (m *UploadRequest) GetFile() isUploadRequest_File {
-	if m != nil {
-		return m.File
-	}
-	return nil
-}
-
-func (x *UploadRequest) GetInfo() *FileMetaData {
-	if x, ok := x.GetFile().(*UploadRequest_Info); ok {
-		return x.Info
-	}
-	return nil
-}
-
-func (x *UploadRequest) GetData() []byte {
-	if x, ok := x.GetFile().(*UploadRequest_Data); ok {
-		return x.Data
-	}
-	return nil
-}
-
-type isUploadRequest_File interface {
-	isUploadRequest_File()
-}
-
-type UploadRequest_Info struct {
-	Info *FileMetaData `protobuf:"bytes,1,opt,name=info,proto3,oneof"`
-}
-
-type UploadRequest_Data struct {
-	Data []byte `protobuf:"bytes,2,opt,name=data,proto3,oneof"` // file data as array of bytes
-}
-
-func (*UploadRequest_Info) isUploadRequest_File() {}
-
-func (*UploadRequest_Data) isUploadRequest_File() {}
-
-type FileMetaData struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	Name        string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`               // file name
-	ContentType string `protobuf:"bytes,2,opt,name=contentType,proto3" json:"contentType,omitempty"` // file type
-}
-
-func (x *FileMetaData) Reset() {
-	*x = FileMetaData{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_proto_streamer_proto_msgTypes[1]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
-}
-
-func (x *FileMetaData) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*FileMetaData) ProtoMessage() {}
-
-func (x *FileMetaData) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_proto_streamer_proto_msgTypes[1]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
-	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use FileMetaData.ProtoReflect.Descriptor instead.
-func (*FileMetaData) Descriptor() ([]byte, []int) {
-	return file_pkg_proto_streamer_proto_rawDescGZIP(), []int{1}
-}
-
-func (x *FileMetaData) GetName() string {
-	if x != nil {
-		return x.Name
-	}
-	return ""
-}
-
-func (x *FileMetaData) GetContentType() string {
-	if x != nil {
-		return x.ContentType
-	}
-	return ""
-}
-
-type UploadResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-}
-
-func (x *UploadResponse) Reset() {
-	*x = UploadResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_proto_streamer_proto_msgTypes[2]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
-}
-
-func (x *UploadResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*UploadResponse) ProtoMessage() {}
-
-func (x *UploadResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_proto_streamer_proto_msgTypes[2]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
-	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use UploadResponse.ProtoReflect.Descriptor instead.
-func (*UploadResponse) Descriptor() ([]byte, []int) {
-	return file_pkg_proto_streamer_proto_rawDescGZIP(), []int{2}
-}
-
-func (x *UploadResponse) GetId() string {
-	if x != nil {
-		return x.Id
-	}
-	return ""
-}
-
-var File_pkg_proto_streamer_proto protoreflect.FileDescriptor
-
-var file_pkg_proto_streamer_proto_rawDesc = []byte{
-	0x0a, 0x18, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x74, 0x72, 0x65,
-	0x61, 0x6d, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x22, 0x58, 0x0a, 0x0d, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x12, 0x29, 0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x13, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x4d, 0x65, 0x74,
-	0x61, 0x44, 0x61, 0x74, 0x61, 0x48, 0x00, 0x52, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x12, 0x14, 0x0a,
-	0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x04, 0x64,
-	0x61, 0x74, 0x61, 0x42, 0x06, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x22, 0x44, 0x0a, 0x0c, 0x46,
-	0x69, 0x6c, 0x65, 0x4d, 0x65, 0x74, 0x61, 0x44, 0x61, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x6e,
-	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
-	0x20, 0x0a, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70,
-	0x65, 0x22, 0x20, 0x0a, 0x0e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x02, 0x69, 0x64, 0x32, 0x48, 0x0a, 0x0d, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x53, 0x65, 0x72,
-	0x76, 0x69, 0x63, 0x65, 0x12, 0x37, 0x0a, 0x06, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x14,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x55, 0x70, 0x6c,
-	0x6f, 0x61, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x28, 0x01, 0x42, 0x0a, 0x5a,
-	0x08, 0x2e, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x33,
-}
-
-var (
-	file_pkg_proto_streamer_proto_rawDescOnce sync.Once
-	file_pkg_proto_streamer_proto_rawDescData = file_pkg_proto_streamer_proto_rawDesc
-)
-
-func file_pkg_proto_streamer_proto_rawDescGZIP() []byte {
-	file_pkg_proto_streamer_proto_rawDescOnce.Do(func() {
-		file_pkg_proto_streamer_proto_rawDescData = protoimpl.X.CompressGZIP(file_pkg_proto_streamer_proto_rawDescData)
-	})
-	return file_pkg_proto_streamer_proto_rawDescData
-}
-
-var file_pkg_proto_streamer_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
-var file_pkg_proto_streamer_proto_goTypes = []interface{}{
-	(*UploadRequest)(nil),  // 0: proto.UploadRequest
-	(*FileMetaData)(nil),   // 1: proto.FileMetaData
-	(*UploadResponse)(nil), // 2: proto.UploadResponse
-}
-var file_pkg_proto_streamer_proto_depIdxs = []int32{
-	1, // 0: proto.UploadRequest.info:type_name -> proto.FileMetaData
-	0, // 1: proto.StreamService.Upload:input_type -> proto.UploadRequest
-	2, // 2: proto.StreamService.Upload:output_type -> proto.UploadResponse
-	2, // [2:3] is the sub-list for method output_type
-	1, // [1:2] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
-}
-
-func init() { file_pkg_proto_streamer_proto_init() }
-func file_pkg_proto_streamer_proto_init() {
-	if File_pkg_proto_streamer_proto != nil {
-		return
-	}
-	if !protoimpl.UnsafeEnabled {
-		file_pkg_proto_streamer_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UploadRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_pkg_proto_streamer_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FileMetaData); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_pkg_proto_streamer_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UploadResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-	}
-	file_pkg_proto_streamer_proto_msgTypes[0].OneofWrappers = []interface{}{
-		(*UploadRequest_Info)(nil),
-		(*UploadRequest_Data)(nil),
-	}
-	type x struct{}
-	out := protoimpl.TypeBuilder{
-		File: protoimpl.DescBuilder{
-			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
-			RawDescriptor: file_pkg_proto_streamer_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   3,
-			NumExtensions: 0,
-			NumServices:   1,
-		},
-		GoTypes:           file_pkg_proto_streamer_proto_goTypes,
-		DependencyIndexes: file_pkg_proto_streamer_proto_depIdxs,
-		MessageInfos:      file_pkg_proto_streamer_proto_msgTypes,
-	}.Build()
-	File_pkg_proto_streamer_proto = out.File
-	file_pkg_proto_streamer_proto_rawDesc = nil
-	file_pkg_proto_streamer_proto_goTypes = nil
-	file_pkg_proto_streamer_proto_depIdxs = nil
-}
@@ -1,139 +0,0 @@
-// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
-// versions:
-// - protoc-gen-go-grpc v1.2.0
-// - protoc             v4.24.2
-// source: pkg/proto/streamer.proto
-
-package pb
-
-import (
-	context "context"
-	grpc "google.golang.org/grpc"
-	codes "google.golang.org/grpc/codes"
-	status "google.golang.org/grpc/status"
-)
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-// Requires gRPC-Go v1.32.0 or later.
-const _ = grpc.SupportPackageIsVersion7
-
-// StreamServiceClient is the client API for StreamService service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
-type StreamServiceClient interface {
-	Upload(ctx context.Context, opts ...grpc.CallOption) (StreamService_UploadClient, error)
-}
-
-type streamServiceClient struct {
-	cc grpc.ClientConnInterface
-}
-
-func NewStreamServiceClient(cc grpc.ClientConnInterface) StreamServiceClient {
-	return &streamServiceClient{cc}
-}
-
-func (c *streamServiceClient) Upload(ctx context.Context, opts ...grpc.CallOption) (StreamService_UploadClient, error) {
-	stream, err := c.cc.NewStream(ctx, &StreamService_ServiceDesc.Streams[0], "/proto.StreamService/Upload", opts...)
-	if err != nil {
-		return nil, err
-	}
-	x := &streamServiceUploadClient{stream}
-	return x, nil
-}
-
-type StreamService_UploadClient interface {
-	Send(*UploadRequest) error
-	CloseAndRecv() (*UploadResponse, error)
-	grpc.ClientStream
-}
-
-type streamServiceUploadClient struct {
-	grpc.ClientStream
-}
-
-func (x *streamServiceUploadClient) Send(m *UploadRequest) error {
-	return x.ClientStream.SendMsg(m)
-}
-
-func (x *streamServiceUploadClient) CloseAndRecv() (*UploadResponse, error) {
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
-	}
-	m := new(UploadResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
-	}
-	return m, nil
-}
-
-// StreamServiceServer is the server API for StreamService service.
-// All implementations must embed UnimplementedStreamServiceServer
-// for forward compatibility
-type StreamServiceServer interface {
-	Upload(StreamService_UploadServer) error
-	mustEmbedUnimplementedStreamServiceServer()
-}
-
-// UnimplementedStreamServiceServer must be embedded to have forward compatible implementations.
-type UnimplementedStreamServiceServer struct {
-}
-
-func (UnimplementedStreamServiceServer) Upload(StreamService_UploadServer) error {
-	return status.Errorf(codes.Unimplemented, "method Upload not implemented")
-}
-func (UnimplementedStreamServiceServer) mustEmbedUnimplementedStreamServiceServer() {}
-
-// UnsafeStreamServiceServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to StreamServiceServer will
-// result in compilation errors.
-type UnsafeStreamServiceServer interface {
-	mustEmbedUnimplementedStreamServiceServer()
-}
-
-func RegisterStreamServiceServer(s grpc.ServiceRegistrar, srv StreamServiceServer) {
-	s.RegisterService(&StreamService_ServiceDesc, srv)
-}
-
-func _StreamService_Upload_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(StreamServiceServer).Upload(&streamServiceUploadServer{stream})
-}
-
-type StreamService_UploadServer interface {
-	SendAndClose(*UploadResponse) error
-	Recv() (*UploadRequest, error)
-	grpc.ServerStream
-}
-
-type streamServiceUploadServer struct {
-	grpc.ServerStream
-}
-
-func (x *streamServiceUploadServer) SendAndClose(m *UploadResponse) error {
-	return x.ServerStream.SendMsg(m)
-}
-
-func (x *streamServiceUploadServer) Recv() (*UploadRequest, error) {
-	m := new(UploadRequest)
-	if err := x.ServerStream.RecvMsg(m); err != nil {
-		return nil, err
-	}
-	return m, nil
-}
-
-// StreamService_ServiceDesc is the grpc.ServiceDesc for StreamService service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var StreamService_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "proto.StreamService",
-	HandlerType: (*StreamServiceServer)(nil),
-	Methods:     []grpc.MethodDesc{},
-	Streams: []grpc.StreamDesc{
-		{
-			StreamName:    "Upload",
-			Handler:       _StreamService_Upload_Handler,
-			ClientStreams: true,
-		},
-	},
-	Metadata: "pkg/proto/streamer.proto",
-}
@@ -1,36 +1,26 @@
 package utils
 
 import (
-	"errors"
-	"fmt"
+	"bytes"
 	"io"
-	"mime/multipart"
 	"net/http"
 )
 
-func GetContentType(fileHeader *multipart.FileHeader) (string, error) {
+// first 512 bytes needed for http detectContentType function to detect content type
+const minSizeForDetectContent = 512
 
-	if fileHeader == nil {
-		return "", errors.New("got nil file header")
-	}
-
-	file, err := fileHeader.Open()
-	if err != nil {
-		return "", fmt.Errorf("failed to open file: %w", err)
-	}
-	// first 512 bytes needed for http detectContentType function to detect content type
-	minSizeForDetectContent := 512
+// To detect the content type from the first bytes of the reader without losing them,
+// returns the content type and a reader to read all the data from start
+func DetectContentType(reader io.Reader) (string, io.Reader, error) {
 
 	buffer := make([]byte, minSizeForDetectContent)
-	_, err = io.ReadAtLeast(file, buffer, minSizeForDetectContent)
-	if err != nil {
-		if err == io.ErrUnexpectedEOF {
-			return "", fmt.Errorf("file size is too low to detect content type")
-		}
-		return "", err
+	n, err := io.ReadFull(reader, buffer)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", nil, err
 	}
+	buffer = buffer[:n]
 
 	contentType := http.DetectContentType(buffer)
 
-	return contentType, nil
+	return contentType, io.MultiReader(bytes.NewReader(buffer), reader), nil
 }
@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// To convert the grpc status code of error into http status code
+func GetHTTPStatusCode(err error) int {
+
+	switch status.Code(err) {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.FailedPrecondition:
+		return http.StatusConflict
+	case codes.DataLoss:
+		return http.StatusUnprocessableEntity
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
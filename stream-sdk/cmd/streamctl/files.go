@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"stream-sdk/pkg/sdk"
+	"text/tabwriter"
+	"time"
+)
+
+func download(ctx context.Context, cli *cli, args []string) error {
+
+	flags := flag.NewFlagSet("download", flag.ContinueOnError)
+	output := flags.String("o", "", "path to write the file (default the file name, - for stdout)")
+	if err := parseFlags(flags, args, 1, 1); err != nil {
+		return err
+	}
+
+	reader, details, err := cli.client.Download(ctx, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	path := *output
+	if path == "" {
+		// only the base of the name, so the file not written outside of the current directory
+		path = filepath.Base(details.Name)
+	}
+
+	var writer io.Writer = cli.stdout
+	if path != "-" {
+		file, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		writer = file
+	}
+
+	bar := newProgressBar(cli.progress, details.Size, 1)
+	_, err = io.Copy(writer, io.TeeReader(reader, progressWriter{bar: bar}))
+	bar.finish()
+	if err != nil {
+		// not keep the partially downloaded file
+		if path != "-" {
+			os.Remove(path)
+		}
+		return fmt.Errorf("failed to download file: %w", err)
+	}
+
+	if path == "-" {
+		return nil
+	}
+	if cli.jsonOutput {
+		return cli.printJSON(struct {
+			Path string `json:"path"`
+			sdk.FileDetails
+		}{Path: path, FileDetails: details})
+	}
+	fmt.Fprintf(cli.stdout, "downloaded %s to %s (%s)\n", details.ID, path, formatSize(details.Size))
+
+	return nil
+}
+
+// writer to add the size of the data written to the progress bar
+type progressWriter struct {
+	bar *progressBar
+}
+
+func (w progressWriter) Write(data []byte) (int, error) {
+	w.bar.add(int64(len(data)))
+	return len(data), nil
+}
+
+func list(ctx context.Context, cli *cli, args []string) error {
+
+	flags := flag.NewFlagSet("ls", flag.ContinueOnError)
+	var opts sdk.ListOptions
+	flags.IntVar(&opts.Limit, "limit", 0, "max files on the page (default decided by server)")
+	flags.StringVar(&opts.Cursor, "cursor", "", "next cursor from the previous page")
+	flags.StringVar(&opts.NamePrefix, "prefix", "", "list only the files with the name starting with")
+	flags.StringVar(&opts.ContentType, "content-type", "", "list only the files of the content type")
+	flags.StringVar(&opts.SortBy, "sort", sdk.SortByUploadedAt, "field to sort the files (uploaded_at or name)")
+	flags.BoolVar(&opts.Descending, "desc", false, "sort in descending order")
+	flags.BoolVar(&opts.Deleted, "deleted", false, "list the deleted files (trash)")
+	if err := parseFlags(flags, args, 0, 0); err != nil {
+		return err
+	}
+
+	fileList, err := cli.client.ListFiles(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	if cli.jsonOutput {
+		return cli.printJSON(fileList)
+	}
+
+	writer := tabwriter.NewWriter(cli.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "ID\tNAME\tSIZE\tCONTENT TYPE\tUPLOADED AT")
+	for _, file := range fileList.Files {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", file.ID, file.Name, formatSize(file.Size), file.ContentType,
+			file.UploadedAt.Format(time.RFC3339))
+	}
+	writer.Flush()
+
+	if fileList.NextCursor != "" {
+		fmt.Fprintf(cli.stdout, "\nmore files on the next page, list with -cursor %s\n", fileList.NextCursor)
+	}
+
+	return nil
+}
+
+func stat(ctx context.Context, cli *cli, args []string) error {
+
+	flags := flag.NewFlagSet("stat", flag.ContinueOnError)
+	if err := parseFlags(flags, args, 1, 1); err != nil {
+		return err
+	}
+
+	details, err := cli.client.GetFile(ctx, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	if cli.jsonOutput {
+		return cli.printJSON(details)
+	}
+
+	writer := tabwriter.NewWriter(cli.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(writer, "ID:\t%s\n", details.ID)
+	fmt.Fprintf(writer, "Name:\t%s\n", details.Name)
+	fmt.Fprintf(writer, "Content Type:\t%s\n", details.ContentType)
+	fmt.Fprintf(writer, "Size:\t%s (%d bytes)\n", formatSize(details.Size), details.Size)
+	fmt.Fprintf(writer, "SHA256:\t%s\n", details.SHA256)
+	fmt.Fprintf(writer, "CRC32C:\t%08x\n", details.CRC32C)
+	fmt.Fprintf(writer, "Status:\t%s\n", details.Status)
+	fmt.Fprintf(writer, "Uploaded At:\t%s\n", details.UploadedAt.Format(time.RFC3339))
+	if details.CompletedAt != nil {
+		fmt.Fprintf(writer, "Completed At:\t%s\n", details.CompletedAt.Format(time.RFC3339))
+	}
+	if details.DeletedAt != nil {
+		fmt.Fprintf(writer, "Deleted At:\t%s\n", details.DeletedAt.Format(time.RFC3339))
+	}
+
+	return writer.Flush()
+}
+
+// result of removing a file
+type removeResult struct {
+	ID      string     `json:"id"`
+	PurgeAt *time.Time `json:"purge_at,omitempty"`
+	Error   string     `json:"error,omitempty"`
+}
+
+func remove(ctx context.Context, cli *cli, args []string) error {
+
+	flags := flag.NewFlagSet("rm", flag.ContinueOnError)
+	if err := parseFlags(flags, args, 1, -1); err != nil {
+		return err
+	}
+
+	// remove all the files even if some of them failed
+	var failed int
+	results := make([]removeResult, flags.NArg())
+	for i, id := range flags.Args() {
+		results[i].ID = id
+		purgeAt, err := cli.client.DeleteFile(ctx, id)
+		if err != nil {
+			results[i].Error = err.Error()
+			failed++
+			continue
+		}
+		results[i].PurgeAt = &purgeAt
+	}
+
+	if cli.jsonOutput {
+		if err := cli.printJSON(results); err != nil {
+			return err
+		}
+	} else {
+		for _, result := range results {
+			if result.Error != "" {
+				fmt.Fprintf(cli.stdout, "failed %s: %s\n", result.ID, result.Error)
+				continue
+			}
+			fmt.Fprintf(cli.stdout, "removed %s, purged at %s\n", result.ID, result.PurgeAt.Format(time.RFC3339))
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to remove %d of %d files", failed, len(results))
+	}
+
+	return nil
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"stream-sdk/pkg/config"
+	"stream-sdk/pkg/sdk"
+)
+
+const usage = `usage: streamctl [-json] <command> [flags] [args]
+
+commands:
+  upload [-r] [-concurrency n] [-name name] [-content-type type] <path>
+        upload a file, or the files of a directory recursively with -r
+  download [-o path] <id>
+        download a file to the path (default the file name, - for stdout)
+  ls [-limit n] [-cursor cursor] [-prefix prefix] [-content-type type] [-sort uploaded_at|name] [-desc] [-deleted]
+        list a page of the files
+  stat <id>
+        show the details of a file
+  rm <id>...
+        move the files to trash
+
+flags:
+`
+
+// command line tool to upload, download and manage the files on stream service over gRPC
+func main() {
+
+	log.SetFlags(0)
+	log.SetPrefix("streamctl: ")
+
+	err := run(os.Args[1:])
+	switch {
+	case err == nil:
+	case errors.Is(err, flag.ErrHelp):
+		os.Exit(0)
+	case errors.Is(err, errUsage):
+		log.Print(err)
+		os.Exit(2)
+	default:
+		log.Fatal(err)
+	}
+}
+
+// error of the invalid command or flags, the usage printed before returning it
+var errUsage = errors.New("invalid usage")
+
+// To run the command of the args, the connection closed before returning the error of the command
+func run(args []string) error {
+
+	flags := flag.NewFlagSet("streamctl", flag.ContinueOnError)
+	jsonOutput := flags.Bool("json", false, "print the results as json")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return usageError(err)
+	}
+
+	if flags.NArg() < 1 {
+		flags.Usage()
+		return fmt.Errorf("%w: missing command", errUsage)
+	}
+
+	commands := map[string]func(ctx context.Context, cli *cli, args []string) error{
+		"upload":   upload,
+		"download": download,
+		"ls":       list,
+		"stat":     stat,
+		"rm":       remove,
+	}
+	command, ok := commands[flags.Arg(0)]
+	if !ok {
+		flags.Usage()
+		return fmt.Errorf("%w: unknown command %q", errUsage, flags.Arg(0))
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	opts := []sdk.Option{
+		sdk.WithUploadTimeout(cfg.UploadTimeout),
+		sdk.WithRequestTimeout(cfg.RequestTimeout),
+		sdk.WithMultipart(cfg.MultipartThreshold, cfg.MultipartPartSize, cfg.MultipartConcurrency),
+		sdk.WithChunkSize(cfg.ChunkSize, cfg.AdaptiveChunkSize),
+		sdk.WithTLS(sdk.TLSConfig{
+			CertFile:   cfg.TLSCertFile,
+			KeyFile:    cfg.TLSKeyFile,
+			CAFile:     cfg.TLSCAFile,
+			ServerName: cfg.TLSServerName,
+		}),
+	}
+
+	client, err := sdk.NewClient(fmt.Sprintf("%s:%s", cfg.StreamServiceHost, cfg.StreamServicePort), opts...)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	defer client.Close()
+
+	// cancel the running command on interrupt, so the not completed uploads aborted
+	ctx, stop := signal.NotifyContext(sdk.ContextWithOwner(context.Background(), cfg.Owner), os.Interrupt)
+	defer stop()
+
+	// the progress only rendered if stderr is a terminal
+	var progress io.Writer
+	if info, err := os.Stderr.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+		progress = os.Stderr
+	}
+
+	return command(ctx, &cli{
+		client:        client,
+		uploadRetries: cfg.UploadRetries,
+		jsonOutput:    *jsonOutput,
+		stdout:        os.Stdout,
+		progress:      progress,
+	}, flags.Args()[1:])
+}
+
+// state shared by the commands
+type cli struct {
+	client        *sdk.Client
+	uploadRetries int
+	jsonOutput    bool
+	stdout        io.Writer // output of the results
+	progress      io.Writer // output of the progress bar, nil to not render
+}
+
+// To print the value as indented json on stdout
+func (c *cli) printJSON(value interface{}) error {
+
+	encoder := json.NewEncoder(c.stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Errorf("failed to encode result: %w", err)
+	}
+
+	return nil
+}
+
+// To parse the flags of the command and check the count of the args
+func parseFlags(flags *flag.FlagSet, args []string, minArgs, maxArgs int) error {
+
+	if err := flags.Parse(args); err != nil {
+		return usageError(err)
+	}
+	if flags.NArg() < minArgs || (maxArgs >= 0 && flags.NArg() > maxArgs) {
+		flags.Usage()
+		return fmt.Errorf("%w: %s: invalid count of arguments", errUsage, flags.Name())
+	}
+
+	return nil
+}
+
+// To convert the error of parsing the flags as usage error, the help request kept to exit without error
+func usageError(err error) error {
+	if errors.Is(err, flag.ErrHelp) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", errUsage, err)
+}
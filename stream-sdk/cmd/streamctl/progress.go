@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	progressBarWidth    = 30
+	progressRenderDelay = time.Millisecond * 100 // min time between the renders of the bar
+)
+
+// progress bar of the data transferred rendered on a line of the output
+type progressBar struct {
+	out     io.Writer // nil if not rendering
+	total   int64
+	files   int
+	started time.Time
+
+	mu       sync.Mutex
+	done     int64
+	finished int // files completed or failed
+	failed   int
+	rendered time.Time
+}
+
+// To create the bar rendered on the output, not rendered if the output is nil
+func newProgressBar(out io.Writer, total int64, files int) *progressBar {
+	return &progressBar{
+		out:     out,
+		total:   total,
+		files:   files,
+		started: time.Now(),
+	}
+}
+
+// To add the size of the data transferred
+func (b *progressBar) add(n int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.done += n
+	b.render(false)
+}
+
+// To count a file transferred
+func (b *progressBar) complete() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.finished++
+	b.render(true)
+}
+
+// To count a file failed
+func (b *progressBar) fail() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.finished++
+	b.failed++
+	b.render(true)
+}
+
+// To render the bar at last and move to the next line
+func (b *progressBar) finish() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.out == nil {
+		return
+	}
+	b.render(true)
+	fmt.Fprintln(b.out)
+}
+
+func (b *progressBar) render(force bool) {
+
+	if b.out == nil || (!force && time.Since(b.rendered) < progressRenderDelay) {
+		return
+	}
+	b.rendered = time.Now()
+
+	ratio := 1.0
+	if b.total > 0 {
+		ratio = min(float64(b.done)/float64(b.total), 1)
+	}
+	filled := int(ratio * progressBarWidth)
+	rate := float64(b.done) / time.Since(b.started).Seconds()
+
+	line := fmt.Sprintf("\r[%s%s] %3.0f%% %s/%s %s/s", strings.Repeat("=", filled),
+		strings.Repeat(" ", progressBarWidth-filled), ratio*100, formatSize(b.done), formatSize(b.total),
+		formatSize(int64(rate)))
+	if b.files > 1 {
+		line += fmt.Sprintf(" %d/%d files", b.finished, b.files)
+		if b.failed > 0 {
+			line += fmt.Sprintf(" (%d failed)", b.failed)
+		}
+	}
+
+	// clear the rest of the previous line
+	fmt.Fprint(b.out, line, "\033[K")
+}
+
+// To format the size in bytes with a binary unit
+func formatSize(size int64) string {
+
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%dB", size)
+	}
+
+	value, exp := float64(size)/unit, 0
+	for value >= unit && exp < 4 {
+		value /= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f%ciB", value, "KMGTP"[exp])
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressBar(t *testing.T) {
+
+	testCases := map[string]struct {
+		total         int64
+		files         int
+		transfer      func(bar *progressBar)
+		expectedLine  string // start of the last line rendered
+		expectedFiles string // files count at the end of the last line, empty if not rendered
+	}{
+		"single_file_should_render_without_files_count": {
+			total: 2048,
+			files: 1,
+			transfer: func(bar *progressBar) {
+				bar.add(1024)
+				bar.complete()
+			},
+			expectedLine: "[" + strings.Repeat("=", 15) + strings.Repeat(" ", 15) + "]  50% 1.0KiB/2.0KiB",
+		},
+		"files_should_render_files_count_with_failed": {
+			total: 100,
+			files: 3,
+			transfer: func(bar *progressBar) {
+				bar.add(60)
+				bar.complete()
+				bar.fail()
+				bar.add(40)
+				bar.complete()
+			},
+			expectedLine:  "[" + strings.Repeat("=", 30) + "] 100% 100B/100B",
+			expectedFiles: " 3/3 files (1 failed)",
+		},
+		"more_data_than_total_should_render_as_completed": {
+			total: 10,
+			files: 1,
+			transfer: func(bar *progressBar) {
+				bar.add(20)
+				bar.complete()
+			},
+			expectedLine: "[" + strings.Repeat("=", 30) + "] 100% 20B/10B",
+		},
+	}
+
+	for name, test := range testCases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			var out bytes.Buffer
+			bar := newProgressBar(&out, test.total, test.files)
+			test.transfer(bar)
+			bar.finish()
+
+			// each render starts from the start of the line and clears the rest of the previous line
+			assert.True(t, strings.HasSuffix(out.String(), "\033[K\n"))
+			renders := strings.Split(strings.TrimSuffix(out.String(), "\033[K\n"), "\033[K")
+			last := strings.TrimPrefix(renders[len(renders)-1], "\r")
+			assert.True(t, strings.HasPrefix(last, test.expectedLine), "last render %q", last)
+			if test.expectedFiles == "" {
+				assert.NotContains(t, last, "files")
+			} else {
+				assert.True(t, strings.HasSuffix(last, test.expectedFiles), "last render %q", last)
+			}
+		})
+	}
+}
+
+func TestProgressBarWithoutOutput(t *testing.T) {
+
+	// the bar not rendering should count without output
+	bar := newProgressBar(nil, 10, 2)
+	bar.add(10)
+	bar.complete()
+	bar.fail()
+	bar.finish()
+
+	assert.Equal(t, int64(10), bar.done)
+	assert.Equal(t, 2, bar.finished)
+	assert.Equal(t, 1, bar.failed)
+}
+
+func TestFormatSize(t *testing.T) {
+
+	testCases := map[string]struct {
+		size     int64
+		expected string
+	}{
+		"bytes":     {size: 1023, expected: "1023B"},
+		"kibibytes": {size: 1536, expected: "1.5KiB"},
+		"mebibytes": {size: 5 * 1024 * 1024, expected: "5.0MiB"},
+		"gibibytes": {size: 3 * 1024 * 1024 * 1024, expected: "3.0GiB"},
+	}
+
+	for name, test := range testCases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			assert.Equal(t, test.expected, formatSize(test.size))
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"stream-sdk/pkg/sdk"
+	"sync"
+)
+
+// result of uploading a file
+type uploadResult struct {
+	Path string `json:"path"`
+	Name string `json:"name"`
+	sdk.UploadResult
+	Error string `json:"error,omitempty"`
+}
+
+func upload(ctx context.Context, cli *cli, args []string) error {
+
+	flags := flag.NewFlagSet("upload", flag.ContinueOnError)
+	recursive := flags.Bool("r", false, "upload the files of the directory recursively")
+	concurrency := flags.Int("concurrency", 4, "max files uploading at a time on recursive upload")
+	name := flags.String("name", "",
+		"name of the uploaded file, or the name prefix of the files on recursive upload (default the file or directory name)")
+	contentType := flags.String("content-type", "", "content type of the file (default detected from the file)")
+	if err := parseFlags(flags, args, 1, 1); err != nil {
+		return err
+	}
+	if *concurrency < 1 {
+		return errors.New("upload: concurrency should be positive")
+	}
+
+	root := flags.Arg(0)
+	info, err := os.Stat(root)
+	if err != nil {
+		return err
+	}
+
+	// the files to upload with the names, the directory name (or the name given) kept on the names of its files
+	var files []uploadResult
+	switch {
+	case !info.IsDir():
+		if *name == "" {
+			*name = filepath.Base(root)
+		}
+		files = append(files, uploadResult{Path: root, Name: *name})
+	case !*recursive:
+		return fmt.Errorf("upload: %s is a directory, use -r to upload recursively", root)
+	default:
+		prefix := *name
+		if prefix == "" {
+			prefix = filepath.Base(filepath.Clean(root))
+		}
+		err = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil || !entry.Type().IsRegular() {
+				return err
+			}
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+			files = append(files, uploadResult{
+				Path: path,
+				Name: filepath.ToSlash(filepath.Join(prefix, rel)),
+			})
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to walk directory: %w", err)
+		}
+	}
+
+	var total int64
+	sizes := make([]int64, len(files))
+	for i, file := range files {
+		info, err := os.Stat(file.Path)
+		if err != nil {
+			return err
+		}
+		sizes[i] = info.Size()
+		total += sizes[i]
+	}
+	bar := newProgressBar(cli.progress, total, len(files))
+
+	// upload the files concurrently up to the limit, the other files still uploaded if a file failed
+	var (
+		wg    sync.WaitGroup
+		slots = make(chan struct{}, *concurrency)
+	)
+	for i := range files {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(file *uploadResult, size int64) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			result, err := cli.uploadFile(ctx, file.Path, file.Name, *contentType, size, bar)
+			if err != nil {
+				file.Error = err.Error()
+				bar.fail()
+				return
+			}
+			file.UploadResult = result
+			bar.complete()
+		}(&files[i], sizes[i])
+	}
+	wg.Wait()
+	bar.finish()
+
+	var failed int
+	for _, file := range files {
+		if file.Error != "" {
+			failed++
+		}
+	}
+
+	if cli.jsonOutput {
+		if err := cli.printJSON(files); err != nil {
+			return err
+		}
+	} else {
+		for _, file := range files {
+			if file.Error != "" {
+				fmt.Fprintf(cli.stdout, "failed %s: %s\n", file.Path, file.Error)
+				continue
+			}
+			fmt.Fprintf(cli.stdout, "uploaded %s as %s (%s)\n", file.Path, file.ID, formatSize(file.Size))
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to upload %d of %d files", failed, len(files))
+	}
+
+	return nil
+}
+
+// To upload the file with the progress reported to the bar, the upload resumed on the transient failures
+func (c *cli) uploadFile(ctx context.Context, path, name, contentType string, size int64,
+	bar *progressBar) (sdk.UploadResult, error) {
+
+	file, err := os.Open(path)
+	if err != nil {
+		return sdk.UploadResult{}, err
+	}
+	defer file.Close()
+
+	if contentType == "" {
+		if contentType, err = detectContentType(file); err != nil {
+			return sdk.UploadResult{}, err
+		}
+	}
+
+	// the progress of the file is the total sent, so only the increase added to the bar
+	var sent int64
+	return c.client.Upload(ctx, name, contentType, file,
+		sdk.WithSize(size),
+		sdk.WithRetries(c.uploadRetries),
+		sdk.WithProgress(func(uploaded int64) {
+			bar.add(uploaded - sent)
+			sent = uploaded
+		}),
+	)
+}
+
+// To detect the content type of the file by the extension or the data at the start of the file
+func detectContentType(file *os.File) (string, error) {
+
+	if contentType := mime.TypeByExtension(filepath.Ext(file.Name())); contentType != "" {
+		return contentType, nil
+	}
+
+	// the content type detected with the first 512 bytes at most
+	buffer := make([]byte, 512)
+	n, err := io.ReadFull(file, buffer)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("failed to seek file: %w", err)
+	}
+
+	return http.DetectContentType(buffer[:n]), nil
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"stream-sdk/pkg/pb"
+	"stream-sdk/pkg/sdk"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// stream service storing the uploaded files in memory, the upload of a file named as failing fails
+type fakeStreamServer struct {
+	pb.UnimplementedStreamServiceServer
+	failing string
+
+	mu    sync.Mutex
+	files map[string]string // data of the files by name
+}
+
+func (s *fakeStreamServer) Upload(stream pb.StreamService_UploadServer) error {
+
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	name := req.GetInfo().GetName()
+	if name == s.failing {
+		return status.Error(codes.Internal, "failed to store data")
+	}
+
+	var data []byte
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		data = append(data, req.GetChunk().GetData()...)
+	}
+
+	s.mu.Lock()
+	s.files[name] = string(data)
+	id := fmt.Sprintf("file_%d", len(s.files))
+	s.mu.Unlock()
+
+	digest := sha256.Sum256(data)
+	return stream.SendAndClose(&pb.UploadResponse{Id: id, Size: int64(len(data)),
+		Sha256: hex.EncodeToString(digest[:]), Offset: int64(len(data)), Completed: true})
+}
+
+// To create the cli connected to the server, the results and the progress written to the buffers
+func newTestCLI(t *testing.T, server pb.StreamServiceServer, jsonOutput bool) (*cli, *bytes.Buffer,
+	*bytes.Buffer) {
+
+	listener := bufconn.Listen(1024 * 1024)
+	grpcServer := grpc.NewServer()
+	pb.RegisterStreamServiceServer(grpcServer, server)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	client, err := sdk.NewClient("bufnet", sdk.WithDialOptions(grpc.WithContextDialer(
+		func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		})))
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+
+	var stdout, progress bytes.Buffer
+	return &cli{
+		client:     client,
+		jsonOutput: jsonOutput,
+		stdout:     &stdout,
+		progress:   &progress,
+	}, &stdout, &progress
+}
+
+// To create the files of the paths (relative to the dir) with the data
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for path, data := range files {
+		path = filepath.Join(dir, filepath.FromSlash(path))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(data), 0o644))
+	}
+}
+
+func TestUpload(t *testing.T) {
+
+	testCases := map[string]struct {
+		files         map[string]string // files on the temp dir to upload
+		args          func(dir string) []string
+		failing       string // name of the file failing to upload
+		jsonOutput    bool
+		expectedFiles map[string]string // files stored by the server by name
+		expectedError string
+		// expected output with the temp dir replaced as {dir}, the errors of the failed files only
+		// expected to contain the error of the server
+		expectedOutput []string // start of the lines of the text output sorted
+		expectedJSON   []uploadResult
+	}{
+		"file_should_upload_with_name": {
+			files: map[string]string{"data.txt": "data"},
+			args: func(dir string) []string {
+				return []string{"-name", "renamed.txt", filepath.Join(dir, "data.txt")}
+			},
+			expectedFiles:  map[string]string{"renamed.txt": "data"},
+			expectedOutput: []string{"uploaded {dir}/data.txt as file_1 (4B)"},
+		},
+		"directory_without_recursive_should_return_error": {
+			files:         map[string]string{"docs/a.txt": "a"},
+			args:          func(dir string) []string { return []string{filepath.Join(dir, "docs")} },
+			expectedFiles: map[string]string{},
+			expectedError: "is a directory, use -r to upload recursively",
+		},
+		"recursive_should_upload_files_with_directory_name": {
+			files: map[string]string{
+				"docs/a.txt":        "first",
+				"docs/nested/b.txt": "second",
+				"docs/nested/c.txt": "third",
+			},
+			args: func(dir string) []string {
+				return []string{"-r", "-concurrency", "2", filepath.Join(dir, "docs")}
+			},
+			expectedFiles: map[string]string{
+				"docs/a.txt":        "first",
+				"docs/nested/b.txt": "second",
+				"docs/nested/c.txt": "third",
+			},
+		},
+		"recursive_with_name_should_upload_files_with_name_prefix": {
+			files: map[string]string{
+				"docs/a.txt":        "first",
+				"docs/nested/b.txt": "second",
+			},
+			args: func(dir string) []string {
+				return []string{"-r", "-name", "backup/docs", filepath.Join(dir, "docs")}
+			},
+			expectedFiles: map[string]string{
+				"backup/docs/a.txt":        "first",
+				"backup/docs/nested/b.txt": "second",
+			},
+		},
+		"recursive_with_failed_file_should_upload_others_and_return_error": {
+			files: map[string]string{
+				"docs/a.txt": "first",
+				"docs/b.txt": "second",
+			},
+			args:          func(dir string) []string { return []string{"-r", filepath.Join(dir, "docs")} },
+			failing:       "docs/a.txt",
+			expectedFiles: map[string]string{"docs/b.txt": "second"},
+			expectedError: "failed to upload 1 of 2 files",
+			expectedOutput: []string{
+				"failed {dir}/docs/a.txt: ",
+				"uploaded {dir}/docs/b.txt as file_1 (6B)",
+			},
+		},
+		"json_output_should_print_results": {
+			files: map[string]string{
+				"docs/a.txt": "first",
+				"docs/b.txt": "second",
+			},
+			args: func(dir string) []string {
+				return []string{"-r", "-concurrency", "1", filepath.Join(dir, "docs")}
+			},
+			failing:       "docs/a.txt",
+			jsonOutput:    true,
+			expectedFiles: map[string]string{"docs/b.txt": "second"},
+			expectedError: "failed to upload 1 of 2 files",
+			expectedJSON: []uploadResult{
+				{Path: "{dir}/docs/a.txt", Name: "docs/a.txt", Error: "failed to store data"},
+				{Path: "{dir}/docs/b.txt", Name: "docs/b.txt", UploadResult: sdk.UploadResult{ID: "file_1", Size: 6,
+					SHA256: "16367aacb67a4a017c8da8ab95682ccb390863780f7114dda0a0e0c55644c7c4"}},
+			},
+		},
+	}
+
+	for name, test := range testCases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			dir := t.TempDir()
+			writeFiles(t, dir, test.files)
+
+			server := &fakeStreamServer{failing: test.failing, files: make(map[string]string)}
+			cli, stdout, progress := newTestCLI(t, server, test.jsonOutput)
+
+			err := upload(context.Background(), cli, test.args(dir))
+			if test.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, test.expectedError)
+			}
+			assert.Equal(t, test.expectedFiles, server.files)
+
+			output := strings.ReplaceAll(stdout.String(), filepath.ToSlash(dir), "{dir}")
+			switch {
+			case test.jsonOutput:
+				var results []uploadResult
+				require.NoError(t, json.Unmarshal([]byte(output), &results))
+				require.Len(t, results, len(test.expectedJSON))
+				for i := range results {
+					assert.Contains(t, results[i].Error, test.expectedJSON[i].Error)
+					results[i].Error = test.expectedJSON[i].Error
+				}
+				assert.Equal(t, test.expectedJSON, results)
+			case test.expectedOutput != nil:
+				lines := strings.Split(strings.TrimSpace(output), "\n")
+				sort.Strings(lines)
+				require.Len(t, lines, len(test.expectedOutput))
+				for i := range lines {
+					assert.True(t, strings.HasPrefix(lines[i], test.expectedOutput[i]), "line %q", lines[i])
+					if strings.HasPrefix(lines[i], "failed ") {
+						assert.Contains(t, lines[i], "failed to store data")
+					}
+				}
+			}
+
+			// the progress bar should render the files uploaded
+			if len(test.files) > 1 && len(test.expectedFiles) > 0 {
+				assert.Contains(t, progress.String(), fmt.Sprintf("%d/%d files", len(test.files), len(test.files)))
+			}
+		})
+	}
+}
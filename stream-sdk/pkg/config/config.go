@@ -0,0 +1,80 @@
+package config
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/spf13/viper"
+)
+
+type Config struct {
+	StreamServiceHost string `mapstructure:"STREAMER_SERVICE_HOST" validate:"required"`
+	StreamServicePort string `mapstructure:"STREAMER_SERVICE_PORT" validate:"required"`
+	// owner the files uploaded and managed as
+	Owner string `mapstructure:"OWNER" validate:"required"`
+
+	// connect to the stream service over mutual tls, the client certificate identifies the client trusted to
+	// forward the owner
+	TLSCertFile   string `mapstructure:"TLS_CERT_FILE" validate:"required"`
+	TLSKeyFile    string `mapstructure:"TLS_KEY_FILE" validate:"required"`
+	TLSCAFile     string `mapstructure:"TLS_CA_FILE"`     // ca to verify the stream service, empty for system roots
+	TLSServerName string `mapstructure:"TLS_SERVER_NAME"` // name on the certificate of the stream service if not the host
+
+	UploadTimeout  time.Duration `mapstructure:"UPLOAD_TIMEOUT"`  // max time of an upload, zero for no deadline
+	RequestTimeout time.Duration `mapstructure:"REQUEST_TIMEOUT"` // max time of other requests, zero for no deadline
+	// retries of an upload on the transient failures, resumed from the data committed on server
+	UploadRetries int `mapstructure:"UPLOAD_RETRIES" validate:"min=0"`
+
+	// min size of the files to upload as parts over parallel streams, zero to upload all files on a single stream
+	MultipartThreshold   int64 `mapstructure:"MULTIPART_THRESHOLD"`
+	MultipartPartSize    int64 `mapstructure:"MULTIPART_PART_SIZE" validate:"min=1"`
+	MultipartConcurrency int   `mapstructure:"MULTIPART_CONCURRENCY" validate:"min=1"` // max parts uploading at a time
+
+	// max size of the data on each chunk of upload streams, the initial size if adaptive
+	ChunkSize int `mapstructure:"CHUNK_SIZE" validate:"min=1"`
+	// grow the chunk size on each upload stream while the throughput improves
+	AdaptiveChunkSize bool `mapstructure:"ADAPTIVE_CHUNK_SIZE"`
+}
+
+var envs = []string{"STREAMER_SERVICE_HOST", "STREAMER_SERVICE_PORT", "OWNER", "UPLOAD_TIMEOUT", "REQUEST_TIMEOUT",
+	"UPLOAD_RETRIES", "MULTIPART_THRESHOLD", "MULTIPART_PART_SIZE", "MULTIPART_CONCURRENCY", "CHUNK_SIZE",
+	"ADAPTIVE_CHUNK_SIZE", "TLS_CERT_FILE", "TLS_KEY_FILE", "TLS_CA_FILE", "TLS_SERVER_NAME"}
+
+// default values for optional envs
+var defaults = map[string]interface{}{
+	"UPLOAD_TIMEOUT":        0,
+	"REQUEST_TIMEOUT":       time.Second * 30,
+	"UPLOAD_RETRIES":        3,
+	"MULTIPART_THRESHOLD":   64 << 20,
+	"MULTIPART_PART_SIZE":   16 << 20,
+	"MULTIPART_CONCURRENCY": 4,
+	"CHUNK_SIZE":            1 << 20,
+}
+
+func LoadConfig() (Config, error) {
+	var config Config
+
+	viper.AddConfigPath("./")
+	viper.SetConfigFile(".env")
+	viper.ReadInConfig()
+
+	for env, value := range defaults {
+		viper.SetDefault(env, value)
+	}
+
+	for _, env := range envs {
+		if err := viper.BindEnv(env); err != nil {
+			return config, err
+		}
+	}
+
+	if err := viper.Unmarshal(&config); err != nil {
+		return config, err
+	}
+
+	if err := validator.New().Struct(&config); err != nil {
+		return config, err
+	}
+
+	return config, nil
+}
@@ -0,0 +1,629 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v4.24.2
+// source: pkg/proto/streamer.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// StreamServiceClient is the client API for StreamService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type StreamServiceClient interface {
+	Upload(ctx context.Context, opts ...grpc.CallOption) (StreamService_UploadClient, error)
+	UploadWithProgress(ctx context.Context, opts ...grpc.CallOption) (StreamService_UploadWithProgressClient, error)
+	Download(ctx context.Context, in *DownloadRequest, opts ...grpc.CallOption) (StreamService_DownloadClient, error)
+	GetFile(ctx context.Context, in *GetFileRequest, opts ...grpc.CallOption) (*FileDetails, error)
+	ListFiles(ctx context.Context, in *ListFilesRequest, opts ...grpc.CallOption) (*ListFilesResponse, error)
+	DeleteFile(ctx context.Context, in *DeleteFileRequest, opts ...grpc.CallOption) (*DeleteFileResponse, error)
+	RestoreFile(ctx context.Context, in *RestoreFileRequest, opts ...grpc.CallOption) (*FileDetails, error)
+	CreateUploadSession(ctx context.Context, in *FileMetaData, opts ...grpc.CallOption) (*UploadSession, error)
+	GetUploadSession(ctx context.Context, in *UploadSessionRequest, opts ...grpc.CallOption) (*UploadSession, error)
+	ResumeUpload(ctx context.Context, opts ...grpc.CallOption) (StreamService_ResumeUploadClient, error)
+	DeleteUploadSession(ctx context.Context, in *UploadSessionRequest, opts ...grpc.CallOption) (*DeleteUploadSessionResponse, error)
+	CompleteMultipartUpload(ctx context.Context, in *CompleteMultipartUploadRequest, opts ...grpc.CallOption) (*UploadResponse, error)
+}
+
+type streamServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewStreamServiceClient(cc grpc.ClientConnInterface) StreamServiceClient {
+	return &streamServiceClient{cc}
+}
+
+func (c *streamServiceClient) Upload(ctx context.Context, opts ...grpc.CallOption) (StreamService_UploadClient, error) {
+	stream, err := c.cc.NewStream(ctx, &StreamService_ServiceDesc.Streams[0], "/proto.StreamService/Upload", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &streamServiceUploadClient{stream}
+	return x, nil
+}
+
+type StreamService_UploadClient interface {
+	Send(*UploadRequest) error
+	CloseAndRecv() (*UploadResponse, error)
+	grpc.ClientStream
+}
+
+type streamServiceUploadClient struct {
+	grpc.ClientStream
+}
+
+func (x *streamServiceUploadClient) Send(m *UploadRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *streamServiceUploadClient) CloseAndRecv() (*UploadResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(UploadResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *streamServiceClient) UploadWithProgress(ctx context.Context, opts ...grpc.CallOption) (StreamService_UploadWithProgressClient, error) {
+	stream, err := c.cc.NewStream(ctx, &StreamService_ServiceDesc.Streams[1], "/proto.StreamService/UploadWithProgress", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &streamServiceUploadWithProgressClient{stream}
+	return x, nil
+}
+
+type StreamService_UploadWithProgressClient interface {
+	Send(*UploadRequest) error
+	Recv() (*UploadProgress, error)
+	grpc.ClientStream
+}
+
+type streamServiceUploadWithProgressClient struct {
+	grpc.ClientStream
+}
+
+func (x *streamServiceUploadWithProgressClient) Send(m *UploadRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *streamServiceUploadWithProgressClient) Recv() (*UploadProgress, error) {
+	m := new(UploadProgress)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *streamServiceClient) Download(ctx context.Context, in *DownloadRequest, opts ...grpc.CallOption) (StreamService_DownloadClient, error) {
+	stream, err := c.cc.NewStream(ctx, &StreamService_ServiceDesc.Streams[2], "/proto.StreamService/Download", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &streamServiceDownloadClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type StreamService_DownloadClient interface {
+	Recv() (*DownloadResponse, error)
+	grpc.ClientStream
+}
+
+type streamServiceDownloadClient struct {
+	grpc.ClientStream
+}
+
+func (x *streamServiceDownloadClient) Recv() (*DownloadResponse, error) {
+	m := new(DownloadResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *streamServiceClient) GetFile(ctx context.Context, in *GetFileRequest, opts ...grpc.CallOption) (*FileDetails, error) {
+	out := new(FileDetails)
+	err := c.cc.Invoke(ctx, "/proto.StreamService/GetFile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *streamServiceClient) ListFiles(ctx context.Context, in *ListFilesRequest, opts ...grpc.CallOption) (*ListFilesResponse, error) {
+	out := new(ListFilesResponse)
+	err := c.cc.Invoke(ctx, "/proto.StreamService/ListFiles", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *streamServiceClient) DeleteFile(ctx context.Context, in *DeleteFileRequest, opts ...grpc.CallOption) (*DeleteFileResponse, error) {
+	out := new(DeleteFileResponse)
+	err := c.cc.Invoke(ctx, "/proto.StreamService/DeleteFile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *streamServiceClient) RestoreFile(ctx context.Context, in *RestoreFileRequest, opts ...grpc.CallOption) (*FileDetails, error) {
+	out := new(FileDetails)
+	err := c.cc.Invoke(ctx, "/proto.StreamService/RestoreFile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *streamServiceClient) CreateUploadSession(ctx context.Context, in *FileMetaData, opts ...grpc.CallOption) (*UploadSession, error) {
+	out := new(UploadSession)
+	err := c.cc.Invoke(ctx, "/proto.StreamService/CreateUploadSession", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *streamServiceClient) GetUploadSession(ctx context.Context, in *UploadSessionRequest, opts ...grpc.CallOption) (*UploadSession, error) {
+	out := new(UploadSession)
+	err := c.cc.Invoke(ctx, "/proto.StreamService/GetUploadSession", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *streamServiceClient) ResumeUpload(ctx context.Context, opts ...grpc.CallOption) (StreamService_ResumeUploadClient, error) {
+	stream, err := c.cc.NewStream(ctx, &StreamService_ServiceDesc.Streams[3], "/proto.StreamService/ResumeUpload", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &streamServiceResumeUploadClient{stream}
+	return x, nil
+}
+
+type StreamService_ResumeUploadClient interface {
+	Send(*ResumeUploadRequest) error
+	CloseAndRecv() (*UploadResponse, error)
+	grpc.ClientStream
+}
+
+type streamServiceResumeUploadClient struct {
+	grpc.ClientStream
+}
+
+func (x *streamServiceResumeUploadClient) Send(m *ResumeUploadRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *streamServiceResumeUploadClient) CloseAndRecv() (*UploadResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(UploadResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *streamServiceClient) DeleteUploadSession(ctx context.Context, in *UploadSessionRequest, opts ...grpc.CallOption) (*DeleteUploadSessionResponse, error) {
+	out := new(DeleteUploadSessionResponse)
+	err := c.cc.Invoke(ctx, "/proto.StreamService/DeleteUploadSession", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *streamServiceClient) CompleteMultipartUpload(ctx context.Context, in *CompleteMultipartUploadRequest, opts ...grpc.CallOption) (*UploadResponse, error) {
+	out := new(UploadResponse)
+	err := c.cc.Invoke(ctx, "/proto.StreamService/CompleteMultipartUpload", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// StreamServiceServer is the server API for StreamService service.
+// All implementations must embed UnimplementedStreamServiceServer
+// for forward compatibility
+type StreamServiceServer interface {
+	Upload(StreamService_UploadServer) error
+	UploadWithProgress(StreamService_UploadWithProgressServer) error
+	Download(*DownloadRequest, StreamService_DownloadServer) error
+	GetFile(context.Context, *GetFileRequest) (*FileDetails, error)
+	ListFiles(context.Context, *ListFilesRequest) (*ListFilesResponse, error)
+	DeleteFile(context.Context, *DeleteFileRequest) (*DeleteFileResponse, error)
+	RestoreFile(context.Context, *RestoreFileRequest) (*FileDetails, error)
+	CreateUploadSession(context.Context, *FileMetaData) (*UploadSession, error)
+	GetUploadSession(context.Context, *UploadSessionRequest) (*UploadSession, error)
+	ResumeUpload(StreamService_ResumeUploadServer) error
+	DeleteUploadSession(context.Context, *UploadSessionRequest) (*DeleteUploadSessionResponse, error)
+	CompleteMultipartUpload(context.Context, *CompleteMultipartUploadRequest) (*UploadResponse, error)
+	mustEmbedUnimplementedStreamServiceServer()
+}
+
+// UnimplementedStreamServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedStreamServiceServer struct {
+}
+
+func (UnimplementedStreamServiceServer) Upload(StreamService_UploadServer) error {
+	return status.Errorf(codes.Unimplemented, "method Upload not implemented")
+}
+func (UnimplementedStreamServiceServer) UploadWithProgress(StreamService_UploadWithProgressServer) error {
+	return status.Errorf(codes.Unimplemented, "method UploadWithProgress not implemented")
+}
+func (UnimplementedStreamServiceServer) Download(*DownloadRequest, StreamService_DownloadServer) error {
+	return status.Errorf(codes.Unimplemented, "method Download not implemented")
+}
+func (UnimplementedStreamServiceServer) GetFile(context.Context, *GetFileRequest) (*FileDetails, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFile not implemented")
+}
+func (UnimplementedStreamServiceServer) ListFiles(context.Context, *ListFilesRequest) (*ListFilesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFiles not implemented")
+}
+func (UnimplementedStreamServiceServer) DeleteFile(context.Context, *DeleteFileRequest) (*DeleteFileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteFile not implemented")
+}
+func (UnimplementedStreamServiceServer) RestoreFile(context.Context, *RestoreFileRequest) (*FileDetails, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreFile not implemented")
+}
+func (UnimplementedStreamServiceServer) CreateUploadSession(context.Context, *FileMetaData) (*UploadSession, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUploadSession not implemented")
+}
+func (UnimplementedStreamServiceServer) GetUploadSession(context.Context, *UploadSessionRequest) (*UploadSession, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUploadSession not implemented")
+}
+func (UnimplementedStreamServiceServer) ResumeUpload(StreamService_ResumeUploadServer) error {
+	return status.Errorf(codes.Unimplemented, "method ResumeUpload not implemented")
+}
+func (UnimplementedStreamServiceServer) DeleteUploadSession(context.Context, *UploadSessionRequest) (*DeleteUploadSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteUploadSession not implemented")
+}
+func (UnimplementedStreamServiceServer) CompleteMultipartUpload(context.Context, *CompleteMultipartUploadRequest) (*UploadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompleteMultipartUpload not implemented")
+}
+func (UnimplementedStreamServiceServer) mustEmbedUnimplementedStreamServiceServer() {}
+
+// UnsafeStreamServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to StreamServiceServer will
+// result in compilation errors.
+type UnsafeStreamServiceServer interface {
+	mustEmbedUnimplementedStreamServiceServer()
+}
+
+func RegisterStreamServiceServer(s grpc.ServiceRegistrar, srv StreamServiceServer) {
+	s.RegisterService(&StreamService_ServiceDesc, srv)
+}
+
+func _StreamService_Upload_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(StreamServiceServer).Upload(&streamServiceUploadServer{stream})
+}
+
+type StreamService_UploadServer interface {
+	SendAndClose(*UploadResponse) error
+	Recv() (*UploadRequest, error)
+	grpc.ServerStream
+}
+
+type streamServiceUploadServer struct {
+	grpc.ServerStream
+}
+
+func (x *streamServiceUploadServer) SendAndClose(m *UploadResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *streamServiceUploadServer) Recv() (*UploadRequest, error) {
+	m := new(UploadRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _StreamService_UploadWithProgress_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(StreamServiceServer).UploadWithProgress(&streamServiceUploadWithProgressServer{stream})
+}
+
+type StreamService_UploadWithProgressServer interface {
+	Send(*UploadProgress) error
+	Recv() (*UploadRequest, error)
+	grpc.ServerStream
+}
+
+type streamServiceUploadWithProgressServer struct {
+	grpc.ServerStream
+}
+
+func (x *streamServiceUploadWithProgressServer) Send(m *UploadProgress) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *streamServiceUploadWithProgressServer) Recv() (*UploadRequest, error) {
+	m := new(UploadRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _StreamService_Download_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloadRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StreamServiceServer).Download(m, &streamServiceDownloadServer{stream})
+}
+
+type StreamService_DownloadServer interface {
+	Send(*DownloadResponse) error
+	grpc.ServerStream
+}
+
+type streamServiceDownloadServer struct {
+	grpc.ServerStream
+}
+
+func (x *streamServiceDownloadServer) Send(m *DownloadResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _StreamService_GetFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StreamServiceServer).GetFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.StreamService/GetFile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StreamServiceServer).GetFile(ctx, req.(*GetFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StreamService_ListFiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFilesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StreamServiceServer).ListFiles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.StreamService/ListFiles",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StreamServiceServer).ListFiles(ctx, req.(*ListFilesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StreamService_DeleteFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StreamServiceServer).DeleteFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.StreamService/DeleteFile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StreamServiceServer).DeleteFile(ctx, req.(*DeleteFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StreamService_RestoreFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StreamServiceServer).RestoreFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.StreamService/RestoreFile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StreamServiceServer).RestoreFile(ctx, req.(*RestoreFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StreamService_CreateUploadSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FileMetaData)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StreamServiceServer).CreateUploadSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.StreamService/CreateUploadSession",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StreamServiceServer).CreateUploadSession(ctx, req.(*FileMetaData))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StreamService_GetUploadSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UploadSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StreamServiceServer).GetUploadSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.StreamService/GetUploadSession",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StreamServiceServer).GetUploadSession(ctx, req.(*UploadSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StreamService_ResumeUpload_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(StreamServiceServer).ResumeUpload(&streamServiceResumeUploadServer{stream})
+}
+
+type StreamService_ResumeUploadServer interface {
+	SendAndClose(*UploadResponse) error
+	Recv() (*ResumeUploadRequest, error)
+	grpc.ServerStream
+}
+
+type streamServiceResumeUploadServer struct {
+	grpc.ServerStream
+}
+
+func (x *streamServiceResumeUploadServer) SendAndClose(m *UploadResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *streamServiceResumeUploadServer) Recv() (*ResumeUploadRequest, error) {
+	m := new(ResumeUploadRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _StreamService_DeleteUploadSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UploadSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StreamServiceServer).DeleteUploadSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.StreamService/DeleteUploadSession",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StreamServiceServer).DeleteUploadSession(ctx, req.(*UploadSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StreamService_CompleteMultipartUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompleteMultipartUploadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StreamServiceServer).CompleteMultipartUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.StreamService/CompleteMultipartUpload",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StreamServiceServer).CompleteMultipartUpload(ctx, req.(*CompleteMultipartUploadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// StreamService_ServiceDesc is the grpc.ServiceDesc for StreamService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var StreamService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "proto.StreamService",
+	HandlerType: (*StreamServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetFile",
+			Handler:    _StreamService_GetFile_Handler,
+		},
+		{
+			MethodName: "ListFiles",
+			Handler:    _StreamService_ListFiles_Handler,
+		},
+		{
+			MethodName: "DeleteFile",
+			Handler:    _StreamService_DeleteFile_Handler,
+		},
+		{
+			MethodName: "RestoreFile",
+			Handler:    _StreamService_RestoreFile_Handler,
+		},
+		{
+			MethodName: "CreateUploadSession",
+			Handler:    _StreamService_CreateUploadSession_Handler,
+		},
+		{
+			MethodName: "GetUploadSession",
+			Handler:    _StreamService_GetUploadSession_Handler,
+		},
+		{
+			MethodName: "DeleteUploadSession",
+			Handler:    _StreamService_DeleteUploadSession_Handler,
+		},
+		{
+			MethodName: "CompleteMultipartUpload",
+			Handler:    _StreamService_CompleteMultipartUpload_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Upload",
+			Handler:       _StreamService_Upload_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "UploadWithProgress",
+			Handler:       _StreamService_UploadWithProgress_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Download",
+			Handler:       _StreamService_Download_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ResumeUpload",
+			Handler:       _StreamService_ResumeUpload_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "pkg/proto/streamer.proto",
+}
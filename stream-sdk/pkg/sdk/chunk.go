@@ -0,0 +1,147 @@
+package sdk
+
+import (
+	"fmt"
+	"io"
+	"math/bits"
+	"stream-sdk/pkg/pb"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// max size of the chunk data, gRPC messages are limited to 4MiB by default (less the framing of the message)
+const maxChunkSize = 4<<20 - 1<<10
+
+// count of the chunks sent to measure the throughput of a chunk size on adaptive mode
+const adaptiveWindowChunks = 8
+
+// buffers of the chunk data pooled by the size class (power of two capacity)
+var bufferPools [bits.UintSize]sync.Pool
+
+// To get a buffer of the size from the pool of its size class
+func getBuffer(size int) []byte {
+	class := bits.Len(uint(size - 1))
+	if buffer, ok := bufferPools[class].Get().(*[]byte); ok {
+		return (*buffer)[:size]
+	}
+	return make([]byte, size, 1<<class)
+}
+
+// To put the buffer back to the pool of its size class
+func putBuffer(buffer []byte) {
+	class := bits.Len(uint(cap(buffer) - 1))
+	if cap(buffer) == 0 || cap(buffer) != 1<<class {
+		return
+	}
+	bufferPools[class].Put(&buffer)
+}
+
+// To send the message encoded before sending, since grpc and its stats handlers can use the message
+// after the send returned. so the data of the message not used after returned and its buffer can reuse
+func sendEncoded(stream grpc.ClientStream, msg interface{}) error {
+
+	prepared := &grpc.PreparedMsg{}
+	if err := prepared.Encode(stream, msg); err != nil {
+		return err
+	}
+
+	return stream.SendMsg(prepared)
+}
+
+// To send the data read from the body as chunks starting from the offset until the body completed.
+// the data of the chunk read to a pooled buffer reused for the next chunks, so the send should not use
+// the chunk after returned (see sendEncoded).
+// returns nil if the server closed the stream, the actual error can get on receive
+func (c *Client) sendChunks(body io.Reader, offset int64, send func(*pb.Chunk) error) error {
+
+	sizer := newChunkSizer(c.chunkSize, c.adaptiveChunkSize)
+
+	buffer := getBuffer(sizer.size)
+	defer func() { putBuffer(buffer) }()
+
+	var seq int64
+	for {
+		// grow the buffer if the chunk size grown
+		if size := sizer.size; cap(buffer) < size {
+			putBuffer(buffer)
+			buffer = getBuffer(size)
+		}
+
+		// the chunk is smaller than the size if less data available on the body,
+		// not waiting to fill the chunk to keep sending the data from a slow body
+		n, err := body.Read(buffer[:sizer.size])
+		if n > 0 {
+			// send only the read data as the next chunk
+			if sendErr := send(&pb.Chunk{Seq: seq, Offset: offset, Data: buffer[:n]}); sendErr != nil {
+				if sendErr == io.EOF {
+					return nil
+				}
+				return fmt.Errorf("failed to send stream to server: %w", sendErr)
+			}
+			seq++
+			offset += int64(n)
+			sizer.sent(n)
+		}
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return fmt.Errorf("failed to read from file: %w", err)
+		}
+	}
+}
+
+// To decide the size of the chunks on a stream. the size kept fixed if not adaptive,
+// otherwise the size doubled on each window of chunks while the throughput improves until the max chunk size
+type chunkSizer struct {
+	size     int
+	previous int // size before the last growth
+	adaptive bool
+
+	chunks     int       // chunks sent on the current window
+	bytes      int64     // data sent on the current window
+	start      time.Time // start of the current window
+	throughput float64   // throughput of the previous window (bytes per second)
+}
+
+func newChunkSizer(size int, adaptive bool) *chunkSizer {
+	return &chunkSizer{
+		size:     size,
+		adaptive: adaptive,
+		start:    time.Now(),
+	}
+}
+
+// To record the size of the data sent on a chunk and adapt the chunk size after each window
+func (s *chunkSizer) sent(n int) {
+
+	if !s.adaptive {
+		return
+	}
+
+	s.chunks++
+	s.bytes += int64(n)
+	if s.chunks < adaptiveWindowChunks {
+		return
+	}
+
+	throughput := float64(s.bytes) / time.Since(s.start).Seconds()
+	switch {
+	case s.throughput > 0 && throughput < s.throughput*1.1:
+		// not improved by the last growth, so go back to the previous size and keep it
+		s.size = s.previous
+		s.adaptive = false
+	case s.size >= maxChunkSize:
+		s.adaptive = false
+	default:
+		s.previous = s.size
+		s.size = min(s.size*2, maxChunkSize)
+	}
+
+	s.throughput = throughput
+	s.chunks = 0
+	s.bytes = 0
+	s.start = time.Now()
+}
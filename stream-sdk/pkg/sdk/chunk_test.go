@@ -0,0 +1,111 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"stream-sdk/pkg/pb"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendChunks(t *testing.T) {
+
+	data := strings.Repeat("0123456789abcdef", 64)
+
+	testCases := map[string]struct {
+		chunkSize int
+		adaptive  bool
+		body      io.Reader
+		offset    int64
+	}{
+		"fixed_size_should_send_all_data": {
+			chunkSize: 100,
+			body:      strings.NewReader(data),
+		},
+		"short_reads_should_send_read_data": {
+			chunkSize: 100,
+			body:      iotest.HalfReader(strings.NewReader(data)),
+		},
+		"adaptive_size_should_send_all_data": {
+			chunkSize: 1,
+			adaptive:  true,
+			body:      strings.NewReader(data),
+		},
+		"offset_should_start_from_offset": {
+			chunkSize: 100,
+			body:      strings.NewReader(data),
+			offset:    10,
+		},
+	}
+
+	for name, test := range testCases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			client := &Client{chunkSize: test.chunkSize, adaptiveChunkSize: test.adaptive}
+
+			// the data of the chunks copied on send as encoded, since the buffer reused for the next chunks
+			var chunks []*pb.Chunk
+			err := client.sendChunks(test.body, test.offset, func(chunk *pb.Chunk) error {
+				chunks = append(chunks, &pb.Chunk{Seq: chunk.GetSeq(), Offset: chunk.GetOffset(),
+					Data: bytes.Clone(chunk.GetData())})
+				return nil
+			})
+			assert.NoError(t, err)
+
+			var sent []byte
+			offset := test.offset
+			for i, chunk := range chunks {
+				assert.Equal(t, int64(i), chunk.GetSeq())
+				assert.Equal(t, offset, chunk.GetOffset())
+				offset += int64(len(chunk.GetData()))
+				sent = append(sent, chunk.GetData()...)
+			}
+			assert.Equal(t, data, string(sent))
+		})
+	}
+}
+
+// To compare the strategies of the chunk size on uploading a file on a single stream
+func BenchmarkUploadChunkSize(b *testing.B) {
+
+	data := bytes.Repeat([]byte("0123456789abcdef"), (32<<20)/16)
+	size := int64(len(data))
+
+	benchmarks := []struct {
+		name      string
+		chunkSize int
+		adaptive  bool
+	}{
+		{name: "fixed_500B", chunkSize: 500},
+		{name: "fixed_32KiB", chunkSize: 32 << 10},
+		{name: "fixed_1MiB", chunkSize: 1 << 20},
+		{name: "fixed_max", chunkSize: maxChunkSize},
+		{name: "adaptive_from_32KiB", chunkSize: 32 << 10, adaptive: true},
+	}
+
+	for _, bench := range benchmarks {
+		bench := bench
+		b.Run(bench.name, func(b *testing.B) {
+
+			client := newBenchStreamClient(b, 0, WithChunkSize(bench.chunkSize, bench.adaptive))
+
+			b.SetBytes(size)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, err := client.Upload(context.Background(), "file", "application/octet-stream",
+					io.MultiReader(bytes.NewReader(data)), WithSize(size))
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
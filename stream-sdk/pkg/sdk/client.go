@@ -0,0 +1,277 @@
+// Package sdk is the go client of the stream service to upload, download and manage the files over gRPC.
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"stream-sdk/pkg/pb"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	// returned if the checksum of the data sent or received not matching the checksum computed by the server
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+	// returned if a failed upload can not resume because the body can not seek to the committed offset
+	ErrNotResumable = errors.New("upload not resumable")
+)
+
+const (
+	defaultRequestTimeout       = time.Second * 30
+	defaultMultipartPartSize    = 16 << 20
+	defaultMultipartConcurrency = 4
+	defaultChunkSize            = 32 << 10
+)
+
+type Client struct {
+	conn   *grpc.ClientConn
+	client pb.StreamServiceClient
+
+	dialOptions    []grpc.DialOption
+	logger         *slog.Logger
+	tls            *TLSConfig    // nil to connect insecure
+	uploadTimeout  time.Duration // deadline of the upload streams
+	requestTimeout time.Duration // deadline of the unary requests
+
+	multipartThreshold   int64 // min size of the files to upload as parts, zero to disable
+	multipartPartSize    int64
+	multipartConcurrency int // max parts uploading at a time
+
+	chunkSize         int  // max size of the data on a chunk, the initial size if adaptive
+	adaptiveChunkSize bool // grow the chunk size by the throughput of the stream
+}
+
+// Option to configure the client
+type Option func(*Client)
+
+// To add the dial options of the connection, the connection is insecure unless tls or the transport credentials provided
+func WithDialOptions(opts ...grpc.DialOption) Option {
+	return func(c *Client) {
+		c.dialOptions = append(c.dialOptions, opts...)
+	}
+}
+
+// To set the logger of the failures the client recovers from (default slog.Default)
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// To set the max time of an upload, zero for no deadline (default)
+func WithUploadTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.uploadTimeout = timeout
+	}
+}
+
+// To set the max time of the requests other than upload and download, zero for no deadline (default 30s)
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.requestTimeout = timeout
+	}
+}
+
+// To upload the files of known size not less than the threshold as parts over parallel streams,
+// at most concurrency parts uploading at a time (disabled by default).
+// only the bodies implementing io.ReaderAt (ex: os.File) uploaded as parts, others uploaded on a single stream
+func WithMultipart(threshold, partSize int64, concurrency int) Option {
+	return func(c *Client) {
+		c.multipartThreshold = threshold
+		c.multipartPartSize = partSize
+		c.multipartConcurrency = concurrency
+	}
+}
+
+// To set the max size of the data on each chunk of the upload streams (default 32KiB),
+// the size is the initial size if adaptive and grown on each stream while the throughput improves
+func WithChunkSize(size int, adaptive bool) Option {
+	return func(c *Client) {
+		c.chunkSize = size
+		c.adaptiveChunkSize = adaptive
+	}
+}
+
+// To create the client connected to the stream service on the target address
+func NewClient(target string, opts ...Option) (*Client, error) {
+
+	c := &Client{
+		logger:               slog.Default(),
+		requestTimeout:       defaultRequestTimeout,
+		multipartPartSize:    defaultMultipartPartSize,
+		multipartConcurrency: defaultMultipartConcurrency,
+		chunkSize:            defaultChunkSize,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.chunkSize < 1 || c.chunkSize > maxChunkSize {
+		return nil, fmt.Errorf("chunk size %d should be between 1 and %d", c.chunkSize, maxChunkSize)
+	}
+	if c.multipartPartSize < 1 || c.multipartConcurrency < 1 {
+		return nil, fmt.Errorf("multipart part size %d and concurrency %d should be positive",
+			c.multipartPartSize, c.multipartConcurrency)
+	}
+
+	creds := insecure.NewCredentials()
+	if c.tls != nil {
+		tlsCreds, err := newTLSCredentials(*c.tls, c.logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load tls certificates: %w", err)
+		}
+		creds = tlsCreds
+	}
+
+	// the later options override the default credentials
+	dialOptions := append([]grpc.DialOption{grpc.WithTransportCredentials(creds)}, c.dialOptions...)
+
+	cc, err := grpc.Dial(target, dialOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial to stream service: %w", err)
+	}
+	c.conn = cc
+	c.client = pb.NewStreamServiceClient(cc)
+
+	return c, nil
+}
+
+// To close the connection to the stream service
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// To create a context with the timeout as the deadline of the call, the context not changed for zero timeout
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// To get the details of the uploaded file
+func (c *Client) GetFile(ctx context.Context, fileID string) (FileDetails, error) {
+
+	ctx, cancel := withTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	res, err := c.client.GetFile(ctx, &pb.GetFileRequest{
+		Id: fileID,
+	})
+	if err != nil {
+		return FileDetails{}, fmt.Errorf("failed to get file details: %w", err)
+	}
+
+	return toFileDetails(res), nil
+}
+
+// To list a page of the files, the next page can get with the next cursor of the list
+func (c *Client) ListFiles(ctx context.Context, opts ListOptions) (FileList, error) {
+
+	listReq := &pb.ListFilesRequest{
+		Cursor:      opts.Cursor,
+		Limit:       int32(opts.Limit),
+		ContentType: opts.ContentType,
+		NamePrefix:  opts.NamePrefix,
+		Descending:  opts.Descending,
+		Deleted:     opts.Deleted,
+	}
+
+	switch opts.SortBy {
+	case "", SortByUploadedAt:
+		listReq.SortBy = pb.SortField_SORT_UPLOADED_AT
+	case SortByName:
+		listReq.SortBy = pb.SortField_SORT_NAME
+	default:
+		return FileList{}, status.Errorf(codes.InvalidArgument, "invalid sort field: %s", opts.SortBy)
+	}
+
+	if !opts.UploadedAfter.IsZero() {
+		listReq.UploadedAfter = opts.UploadedAfter.Unix()
+	}
+	if !opts.UploadedBefore.IsZero() {
+		listReq.UploadedBefore = opts.UploadedBefore.Unix()
+	}
+
+	ctx, cancel := withTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	res, err := c.client.ListFiles(ctx, listReq)
+	if err != nil {
+		return FileList{}, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	fileList := FileList{
+		Files:      make([]FileDetails, len(res.GetFiles())),
+		NextCursor: res.GetNextCursor(),
+	}
+	for i, file := range res.GetFiles() {
+		fileList.Files[i] = toFileDetails(file)
+	}
+
+	return fileList, nil
+}
+
+// To move the file to trash, returns the time of the file will be permanently removed
+func (c *Client) DeleteFile(ctx context.Context, fileID string) (time.Time, error) {
+
+	ctx, cancel := withTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	res, err := c.client.DeleteFile(ctx, &pb.DeleteFileRequest{
+		Id: fileID,
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to delete file: %w", err)
+	}
+
+	return time.Unix(res.GetPurgeAt(), 0), nil
+}
+
+// To restore the file from trash
+func (c *Client) RestoreFile(ctx context.Context, fileID string) (FileDetails, error) {
+
+	ctx, cancel := withTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	res, err := c.client.RestoreFile(ctx, &pb.RestoreFileRequest{
+		Id: fileID,
+	})
+	if err != nil {
+		return FileDetails{}, fmt.Errorf("failed to restore file: %w", err)
+	}
+
+	return toFileDetails(res), nil
+}
+
+func toFileDetails(file *pb.FileDetails) FileDetails {
+
+	fileDetails := FileDetails{
+		ID:            file.GetId(),
+		Owner:         file.GetOwner(),
+		Name:          file.GetName(),
+		ContentType:   file.GetContentType(),
+		UploadedAt:    time.Unix(file.GetUploadedAt(), 0),
+		Size:          file.GetSize(),
+		SHA256:        file.GetSha256(),
+		CRC32C:        file.GetCrc32C(),
+		Status:        file.GetStatus(),
+		FailureReason: file.GetFailureReason(),
+	}
+	if file.GetDeletedAt() > 0 {
+		deletedAt := time.Unix(file.GetDeletedAt(), 0)
+		fileDetails.DeletedAt = &deletedAt
+	}
+	if file.GetCompletedAt() > 0 {
+		completedAt := time.Unix(file.GetCompletedAt(), 0)
+		fileDetails.CompletedAt = &completedAt
+	}
+
+	return fileDetails
+}
@@ -0,0 +1,108 @@
+package sdk
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"stream-sdk/pkg/pb"
+)
+
+// To download the file, returns the reader of the file data to close after read and the file details.
+// the data verified with the size and sha256 of the file on the end of the data,
+// the reader returns ErrChecksumMismatch instead of EOF if not matching
+func (c *Client) Download(ctx context.Context, fileID string) (io.ReadCloser, FileDetails, error) {
+
+	// create a context with cancel to close the stream when reader closed
+	ctx, cancel := context.WithCancel(ctx)
+
+	streamSvc, err := c.client.Download(ctx, &pb.DownloadRequest{
+		Id: fileID,
+	})
+	if err != nil {
+		cancel()
+		return nil, FileDetails{}, fmt.Errorf("failed to call download method for stream client: %w", err)
+	}
+
+	// first receive the file details
+	res, err := streamSvc.Recv()
+	if err != nil {
+		cancel()
+		return nil, FileDetails{}, fmt.Errorf("failed to receive file details: %w", err)
+	}
+
+	info := res.GetInfo()
+	if info == nil {
+		cancel()
+		return nil, FileDetails{}, errors.New("file details not received on stream initially")
+	}
+
+	return &downloadReader{
+		stream:   streamSvc,
+		cancel:   cancel,
+		size:     info.GetSize(),
+		sha256:   info.GetSha256(),
+		checksum: sha256.New(),
+	}, toFileDetails(info), nil
+}
+
+// reader to read the file data from download stream
+type downloadReader struct {
+	stream pb.StreamService_DownloadClient
+	cancel context.CancelFunc
+	buffer []byte // remaining data from last received stream
+
+	size     int64  // expected size of the file
+	sha256   string // expected sha256 of the file, not verified if empty
+	read     int64
+	checksum hash.Hash
+	err      error // error of the stream returned on next reads
+}
+
+func (r *downloadReader) Read(data []byte) (int, error) {
+
+	// receive data from stream until get some data or an error(EOF on stream completed)
+	for len(r.buffer) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		res, err := r.stream.Recv()
+		if err == io.EOF {
+			err = r.verify()
+		}
+		if err != nil {
+			r.err = err
+			return 0, err
+		}
+		r.buffer = res.GetData()
+	}
+
+	n := copy(data, r.buffer)
+	r.buffer = r.buffer[n:]
+	r.read += int64(n)
+	r.checksum.Write(data[:n])
+
+	return n, nil
+}
+
+// To verify the data received with the file details on the stream completed, returns EOF if matching
+func (r *downloadReader) verify() error {
+
+	if r.read != r.size {
+		return fmt.Errorf("%w: received %d bytes but file has %d bytes", ErrChecksumMismatch, r.read, r.size)
+	}
+	if digest := hex.EncodeToString(r.checksum.Sum(nil)); r.sha256 != "" && digest != r.sha256 {
+		return fmt.Errorf("%w: received sha256 %s but file has %s", ErrChecksumMismatch, digest, r.sha256)
+	}
+
+	return io.EOF
+}
+
+func (r *downloadReader) Close() error {
+	// cancel the context to close the stream
+	r.cancel()
+	return nil
+}
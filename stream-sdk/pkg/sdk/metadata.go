@@ -0,0 +1,57 @@
+package sdk
+
+import (
+	"context"
+
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// key of the metadata carrying the owner of the calls, the stream service rejects the calls without an owner
+	ownerMetadataKey = "owner"
+	// key of the metadata carrying the id of the request, to correlate the logs of the gateway and stream service
+	requestIDMetadataKey = "x-request-id"
+)
+
+// To make the calls with the context on behalf of the owner, so only the files of the owner visible.
+// the owner should be the authenticated subject of the caller
+func ContextWithOwner(ctx context.Context, owner string) context.Context {
+	return withOutgoingMetadata(ctx, ownerMetadataKey, owner)
+}
+
+// To make the calls with the context as a part of the request of the id, the stream service generates
+// a new id for each call if not given
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return withOutgoingMetadata(ctx, requestIDMetadataKey, id)
+}
+
+// To get the owner the calls with the context made on behalf of, empty if not set
+func OwnerFromContext(ctx context.Context) string {
+	return outgoingMetadata(ctx, ownerMetadataKey)
+}
+
+// To get the id of the request the calls with the context part of, empty if not set
+func RequestIDFromContext(ctx context.Context) string {
+	return outgoingMetadata(ctx, requestIDMetadataKey)
+}
+
+// To get the value of the key on the outgoing metadata, empty if not set
+func outgoingMetadata(ctx context.Context, key string) string {
+
+	md, _ := metadata.FromOutgoingContext(ctx)
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+// To set the value of the key on the outgoing metadata, replacing the value already set
+func withOutgoingMetadata(ctx context.Context, key, value string) context.Context {
+
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	md.Set(key, value)
+
+	return metadata.NewOutgoingContext(ctx, md)
+}
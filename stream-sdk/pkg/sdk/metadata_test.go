@@ -0,0 +1,29 @@
+package sdk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestOutgoingMetadata(t *testing.T) {
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "key", "value")
+	ctx = ContextWithOwner(ctx, "owner_1")
+	ctx = ContextWithRequestID(ctx, "request_1")
+
+	// the owner should be replaced, not sent twice
+	ctx = ContextWithOwner(ctx, "owner_2")
+
+	md, ok := metadata.FromOutgoingContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"owner_2"}, md.Get(ownerMetadataKey))
+	assert.Equal(t, []string{"request_1"}, md.Get(requestIDMetadataKey))
+	assert.Equal(t, []string{"value"}, md.Get("key"), "other metadata should be kept")
+
+	assert.Equal(t, "owner_2", OwnerFromContext(ctx))
+	assert.Equal(t, "request_1", RequestIDFromContext(ctx))
+	assert.Empty(t, OwnerFromContext(context.Background()))
+}
@@ -0,0 +1,176 @@
+package sdk
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"stream-sdk/pkg/pb"
+	"sync"
+)
+
+// To upload the file as parts over parallel streams, then complete the upload to combine the parts on server.
+// the upload aborted to remove the uploaded parts if any of the parts failed
+func (c *Client) uploadMultipart(ctx context.Context, info *pb.FileMetaData, body io.ReaderAt,
+	options uploadOptions) (*pb.UploadResponse, error) {
+
+	uploadID, err := c.createUploadSession(ctx, info)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create multipart upload: %w", err)
+	}
+
+	parts, err := c.uploadParts(ctx, uploadID, body, info.GetSize(), options)
+	if err == nil {
+		var res *pb.UploadResponse
+		res, err = c.client.CompleteMultipartUpload(ctx, &pb.CompleteMultipartUploadRequest{
+			UploadId: uploadID,
+			Parts:    parts,
+		})
+		if err == nil {
+			return res, nil
+		}
+		err = fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+
+	c.abortUploadSession(ctx, uploadID)
+
+	return nil, err
+}
+
+// To upload the body of the size as parts concurrently and return the uploaded parts in order,
+// each part read directly from its offset of the body, so no part kept in memory
+func (c *Client) uploadParts(ctx context.Context, uploadID string, body io.ReaderAt, size int64,
+	options uploadOptions) ([]*pb.MultipartPart, error) {
+
+	// cancel the other parts uploading on the first failure
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	// limit the parts uploading at a time
+	slots := make(chan struct{}, c.multipartConcurrency)
+
+	count := (size + c.multipartPartSize - 1) / c.multipartPartSize
+	parts := make([]*pb.MultipartPart, count)
+
+	for i := int64(0); i < count; i++ {
+
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		offset := i * c.multipartPartSize
+		partSize := min(c.multipartPartSize, size-offset)
+
+		wg.Add(1)
+		go func(index int64) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			// the part read again from the start on each attempt
+			part := func() io.Reader { return io.NewSectionReader(body, offset, partSize) }
+			uploaded, err := c.uploadPart(ctx, uploadID, int32(index+1), partSize, part, options)
+			if err != nil {
+				fail(err)
+				return
+			}
+			parts[index] = uploaded
+		}(i)
+	}
+
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	// the upload context cancelled before all parts started
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return parts, nil
+}
+
+// To upload the part on a stream and return the part with the sha256 of the data sent,
+// so the server can verify the stored part on completing the upload. the part uploaded again on the transient failures
+func (c *Client) uploadPart(ctx context.Context, uploadID string, number int32, size int64,
+	part func() io.Reader, options uploadOptions) (*pb.MultipartPart, error) {
+
+	tracker := &streamTracker{checksum: sha256.New(), progress: options.progress}
+
+	err := retry(ctx, options.retries, func() error {
+
+		streamSvc, err := c.client.Upload(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to call upload method for part %d: %w", number, err)
+		}
+
+		err = streamSvc.Send(&pb.UploadRequest{
+			File: &pb.UploadRequest_Part{
+				Part: &pb.MultipartInfo{
+					UploadId: uploadID,
+					Number:   number,
+					Size:     &size,
+				},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to send part %d details: %w", number, err)
+		}
+
+		err = c.sendChunks(part(), 0, func(chunk *pb.Chunk) error {
+			if err := sendEncoded(streamSvc, &pb.UploadRequest{
+				File: &pb.UploadRequest_Chunk{Chunk: chunk},
+			}); err != nil {
+				return err
+			}
+			tracker.track(chunk)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to upload part %d: %w", number, err)
+		}
+
+		if _, err := streamSvc.CloseAndRecv(); err != nil {
+			return fmt.Errorf("failed to upload part %d: %w", number, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.MultipartPart{
+		Number: number,
+		Size:   size,
+		Sha256: hex.EncodeToString(tracker.checksum.Sum(nil)),
+	}, nil
+}
+
+// To remove the upload session and the data uploaded to it after the upload failed
+func (c *Client) abortUploadSession(ctx context.Context, sessionID string) {
+
+	// using a new context because the upload context can be already cancelled
+	ctx, cancel := withTimeout(context.WithoutCancel(ctx), c.requestTimeout)
+	defer cancel()
+
+	if err := c.DeleteUploadSession(ctx, sessionID); err != nil {
+		c.logger.ErrorContext(ctx, "failed to abort upload", "file_id", sessionID, "error", err)
+	}
+}
@@ -0,0 +1,229 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"stream-sdk/pkg/pb"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/benchmark/latency"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// stream service receiving the uploads in memory, computing the sha256 of the data on each stream
+// and writing the data of each stream sequentially at the storage rate as the stream service does
+type benchStreamServer struct {
+	pb.UnimplementedStreamServiceServer
+	storageRate int64 // write throughput of a single writer (bytes per second), zero for no limit
+
+	mu    sync.Mutex
+	parts map[string]map[int32]string // sha256 of the parts of the uploads by number
+}
+
+func (s *benchStreamServer) Upload(stream pb.StreamService_UploadServer) error {
+
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	checksum := sha256.New()
+	var size, unwritten int64
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		data := req.GetChunk().GetData()
+		checksum.Write(data)
+		size += int64(len(data))
+
+		// write the buffered data to storage
+		if unwritten += int64(len(data)); s.storageRate > 0 && unwritten >= benchStorageBufferSize {
+			time.Sleep(time.Duration(unwritten) * time.Second / time.Duration(s.storageRate))
+			unwritten = 0
+		}
+	}
+	digest := hex.EncodeToString(checksum.Sum(nil))
+
+	id := "file_id"
+	if part := req.GetPart(); part != nil {
+		id = part.GetUploadId()
+		s.mu.Lock()
+		s.parts[id][part.GetNumber()] = digest
+		s.mu.Unlock()
+	}
+
+	return stream.SendAndClose(&pb.UploadResponse{Id: id, Size: size, Sha256: digest})
+}
+
+func (s *benchStreamServer) CreateUploadSession(ctx context.Context, req *pb.FileMetaData) (*pb.UploadSession, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := fmt.Sprintf("upload_%d", len(s.parts))
+	s.parts[id] = make(map[int32]string)
+
+	return &pb.UploadSession{Id: id}, nil
+}
+
+func (s *benchStreamServer) CompleteMultipartUpload(ctx context.Context,
+	req *pb.CompleteMultipartUploadRequest) (*pb.UploadResponse, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, part := range req.GetParts() {
+		if s.parts[req.GetUploadId()][part.GetNumber()] != part.GetSha256() {
+			return nil, status.Errorf(codes.DataLoss, "part %d not matching", part.GetNumber())
+		}
+	}
+
+	return &pb.UploadResponse{Id: req.GetUploadId()}, nil
+}
+
+const (
+	// one way latency of the network between gateway and stream service
+	benchNetworkLatency = time.Millisecond * 5
+
+	// write throughput of a single writer on storage (bytes per second) and size of the data written at a time
+	benchStorageRate       = 16 << 20
+	benchStorageBufferSize = 1 << 20
+)
+
+// To create the client connected to the server over a network with the latency
+func newTestClient(tb testing.TB, server pb.StreamServiceServer, networkLatency time.Duration,
+	opts ...Option) *Client {
+
+	network := latency.Network{Latency: networkLatency}
+	listener := bufconn.Listen(1024 * 1024)
+	grpcServer := grpc.NewServer()
+	pb.RegisterStreamServiceServer(grpcServer, server)
+	go grpcServer.Serve(network.Listener(listener))
+	tb.Cleanup(grpcServer.Stop)
+
+	dialer := network.ContextDialer(func(ctx context.Context, _, _ string) (net.Conn, error) {
+		return listener.DialContext(ctx)
+	})
+	opts = append([]Option{WithDialOptions(grpc.WithContextDialer(func(ctx context.Context,
+		address string) (net.Conn, error) {
+		return dialer(ctx, "bufnet", address)
+	}))}, opts...)
+
+	client, err := NewClient("bufnet", opts...)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { client.Close() })
+
+	return client
+}
+
+// To create the client connected to the bench server over a network with the latency
+func newBenchStreamClient(b *testing.B, storageRate int64, opts ...Option) *Client {
+	return newTestClient(b, &benchStreamServer{
+		storageRate: storageRate,
+		parts:       make(map[string]map[int32]string),
+	}, benchNetworkLatency, opts...)
+}
+
+func TestUploadMultipart(t *testing.T) {
+
+	data := bytes.Repeat([]byte("0123456789abcdef"), 4)
+	size := int64(len(data))
+
+	testCases := map[string]struct {
+		body          io.Reader
+		threshold     int64
+		expectedID    string
+		expectedParts int // parts uploaded, zero if uploaded on a single stream
+	}{
+		"reader_at_body_should_upload_as_parts": {
+			body:          bytes.NewReader(data),
+			threshold:     size,
+			expectedID:    "upload_0",
+			expectedParts: 3,
+		},
+		"reader_at_body_smaller_than_threshold_should_upload_on_single_stream": {
+			body:       bytes.NewReader(data),
+			threshold:  size + 1,
+			expectedID: "file_id",
+		},
+		"not_reader_at_body_should_upload_on_single_stream": {
+			body:       io.MultiReader(bytes.NewReader(data)),
+			threshold:  size,
+			expectedID: "file_id",
+		},
+	}
+
+	for name, test := range testCases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			server := &benchStreamServer{parts: make(map[string]map[int32]string)}
+			client := newTestClient(t, server, 0, WithMultipart(test.threshold, 24, 2))
+
+			res, err := client.Upload(context.Background(), "file", "application/octet-stream", test.body,
+				WithSize(size))
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedID, res.ID)
+
+			var parts int
+			for _, uploadParts := range server.parts {
+				parts += len(uploadParts)
+			}
+			assert.Equal(t, test.expectedParts, parts)
+		})
+	}
+}
+
+// To compare the throughput of uploading a file on a single stream and as parts over parallel streams
+func BenchmarkUpload(b *testing.B) {
+
+	data := bytes.Repeat([]byte("0123456789abcdef"), (32<<20)/16)
+	size := int64(len(data))
+
+	benchmarks := []struct {
+		name      string
+		threshold int64
+	}{
+		{name: "single_stream", threshold: 0},
+		{name: "parallel_streams", threshold: 1},
+	}
+
+	for _, bench := range benchmarks {
+		bench := bench
+		b.Run(bench.name, func(b *testing.B) {
+
+			client := newBenchStreamClient(b, benchStorageRate, WithMultipart(bench.threshold, 4<<20, 4))
+
+			b.SetBytes(size)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				// the parts read at the offsets of the body
+				_, err := client.Upload(context.Background(), "file", "application/octet-stream",
+					bytes.NewReader(data), WithSize(size))
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"stream-sdk/pkg/pb"
+	"time"
+)
+
+// To create an upload session to upload the file as parts on multiple streams, returns the id of the session.
+// the size and sha256 of the upload options declared for the file
+func (c *Client) CreateUploadSession(ctx context.Context, name, contentType string,
+	opts ...UploadOption) (string, error) {
+
+	var options uploadOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return c.createUploadSession(ctx, &pb.FileMetaData{
+		Name:        name,
+		ContentType: contentType,
+		Sha256:      options.sha256,
+		Size:        options.size,
+	})
+}
+
+func (c *Client) createUploadSession(ctx context.Context, info *pb.FileMetaData) (string, error) {
+
+	ctx, cancel := withTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	res, err := c.client.CreateUploadSession(ctx, info)
+	if err != nil {
+		return "", fmt.Errorf("failed to create upload session: %w", err)
+	}
+
+	return res.GetId(), nil
+}
+
+func (c *Client) GetUploadSession(ctx context.Context, sessionID string) (UploadSession, error) {
+
+	ctx, cancel := withTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	res, err := c.client.GetUploadSession(ctx, &pb.UploadSessionRequest{
+		Id: sessionID,
+	})
+	if err != nil {
+		return UploadSession{}, fmt.Errorf("failed to get upload session: %w", err)
+	}
+
+	session := UploadSession{
+		ID:        res.GetId(),
+		Offset:    res.GetOffset(),
+		Completed: res.GetCompleted(),
+		Size:      res.Size,
+	}
+	if res.GetExpiresAt() > 0 {
+		session.ExpiresAt = time.Unix(res.GetExpiresAt(), 0)
+	}
+
+	return session, nil
+}
+
+// To upload the body as a part of the session from the offset, the data discarded if checksum provided and not matching.
+// the session completed when the declared size of the file received
+func (c *Client) UploadSessionPart(ctx context.Context, sessionID string, offset int64, checksum *PartChecksum,
+	body io.Reader) (UploadSession, error) {
+
+	// the stream closed on return and cancelled on the deadline or the request cancelled
+	ctx, cancel := withTimeout(ctx, c.uploadTimeout)
+	defer cancel()
+
+	resumeInfo := &pb.ResumeInfo{
+		SessionId: sessionID,
+		Offset:    offset,
+		Partial:   true,
+	}
+	if checksum != nil {
+		resumeInfo.Checksum = &pb.PartChecksum{
+			Algorithm: checksum.Algorithm,
+			Digest:    checksum.Digest,
+		}
+	}
+
+	res, err := c.resumeUpload(ctx, resumeInfo, body, nil)
+	if err != nil {
+		return UploadSession{}, err
+	}
+
+	return UploadSession{
+		ID:        res.GetId(),
+		Offset:    res.GetOffset(),
+		Completed: res.GetCompleted(),
+	}, nil
+}
+
+// To remove the upload session and the data uploaded to it
+func (c *Client) DeleteUploadSession(ctx context.Context, sessionID string) error {
+
+	ctx, cancel := withTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	_, err := c.client.DeleteUploadSession(ctx, &pb.UploadSessionRequest{
+		Id: sessionID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete upload session: %w", err)
+	}
+
+	return nil
+}
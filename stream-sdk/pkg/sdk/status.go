@@ -0,0 +1,35 @@
+package sdk
+
+import (
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+)
+
+// reasons of the errors of the stream service, set on the error info detail of the status
+// to tell apart the errors of the same code
+const (
+	// the data received not matching the checksum declared
+	ReasonChecksumMismatch = "CHECKSUM_MISMATCH"
+	// the data received not matching the size declared
+	ReasonSizeMismatch = "SIZE_MISMATCH"
+	// the upload resumed from an offset other than the committed offset
+	ReasonOffsetMismatch = "OFFSET_MISMATCH"
+	// the data received failed to store by the stream service
+	ReasonStorageWrite = "STORAGE_WRITE"
+)
+
+// To get the reason of the error returned by the stream service, empty if the error has no reason
+func ErrorReason(err error) string {
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return ""
+	}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info.GetReason()
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,53 @@
+package sdk
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorReason(t *testing.T) {
+
+	st, err := status.New(codes.DataLoss, "size mismatch").WithDetails(&errdetails.ErrorInfo{
+		Reason: ReasonSizeMismatch,
+		Domain: "stream-service",
+	})
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		err            error
+		expectedReason string
+	}{
+		"status_with_reason_should_return_reason": {
+			err:            st.Err(),
+			expectedReason: ReasonSizeMismatch,
+		},
+		"wrapped_status_with_reason_should_return_reason": {
+			err:            fmt.Errorf("failed to upload: %w", st.Err()),
+			expectedReason: ReasonSizeMismatch,
+		},
+		"status_without_reason_should_return_empty": {
+			err: status.Error(codes.DataLoss, "checksum mismatch"),
+		},
+		"not_status_error_should_return_empty": {
+			err: errors.New("connection lost"),
+		},
+		"nil_error_should_return_empty": {},
+	}
+
+	for name, test := range testCases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			assert.Equal(t, test.expectedReason, ErrorReason(test.err))
+		})
+	}
+}
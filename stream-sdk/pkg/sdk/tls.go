@@ -0,0 +1,205 @@
+package sdk
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// files of the certificates to connect to the stream service over tls
+type TLSConfig struct {
+	CertFile   string // client certificate for mutual tls, empty to not authenticate the client
+	KeyFile    string // private key of the client certificate
+	CAFile     string // certificates of the ca to verify the server, empty to use the system roots
+	ServerName string // name to verify the server certificate, empty to use the host of the target
+}
+
+// To connect over tls with the certificates of the files, the files reloaded on the new connections
+// if modified, so the renewed certificates used without recreating the client
+func WithTLS(cfg TLSConfig) Option {
+	return func(c *Client) {
+		c.tls = &cfg
+	}
+}
+
+// transport credentials loading the tls config from the files on handshake if the files modified
+type tlsCredentials struct {
+	cfg    TLSConfig
+	logger *slog.Logger
+
+	mu       sync.Mutex
+	config   *tls.Config
+	modTimes []time.Time
+}
+
+func newTLSCredentials(cfg TLSConfig, logger *slog.Logger) (*tlsCredentials, error) {
+
+	if (cfg.CertFile == "") != (cfg.KeyFile == "") {
+		return nil, errors.New("both client certificate and key required for mutual tls")
+	}
+
+	c := &tlsCredentials{cfg: cfg, logger: logger}
+	if _, err := c.tlsConfig(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// To get the tls config, reloaded if any of the files modified since loaded.
+// the loaded config kept if the reload failed, the files may be partially written on renewal
+func (c *tlsCredentials) tlsConfig() (*tls.Config, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	modTimes, err := fileModTimes(c.cfg.CertFile, c.cfg.KeyFile, c.cfg.CAFile)
+	if err == nil && c.config != nil && equalTimes(modTimes, c.modTimes) {
+		return c.config, nil
+	}
+
+	config, loadErr := c.load()
+	if err == nil {
+		err = loadErr
+	}
+	if err != nil {
+		if c.config == nil {
+			return nil, err
+		}
+		c.logger.Error("failed to reload tls certificates, using the loaded certificates", "error", err)
+		return c.config, nil
+	}
+
+	c.config, c.modTimes = config, modTimes
+
+	return config, nil
+}
+
+func (c *tlsCredentials) load() (*tls.Config, error) {
+
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: c.cfg.ServerName,
+	}
+
+	if c.cfg.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.cfg.CertFile, c.cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	if c.cfg.CAFile != "" {
+		pool, err := loadCertPool(c.cfg.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = pool
+	}
+
+	return config, nil
+}
+
+func (c *tlsCredentials) ClientHandshake(ctx context.Context, authority string,
+	conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+
+	config, err := c.tlsConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return credentials.NewTLS(config).ClientHandshake(ctx, authority, conn)
+}
+
+func (c *tlsCredentials) ServerHandshake(conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return nil, nil, errors.New("server handshake not supported by client credentials")
+}
+
+func (c *tlsCredentials) Info() credentials.ProtocolInfo {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return credentials.ProtocolInfo{
+		SecurityProtocol: "tls",
+		SecurityVersion:  "1.2",
+		ServerName:       c.cfg.ServerName,
+	}
+}
+
+func (c *tlsCredentials) Clone() credentials.TransportCredentials {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return &tlsCredentials{
+		cfg:      c.cfg,
+		config:   c.config,
+		modTimes: c.modTimes,
+	}
+}
+
+func (c *tlsCredentials) OverrideServerName(serverName string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cfg.ServerName = serverName
+	c.config = nil // loaded again with the server name
+
+	return nil
+}
+
+// To load the pem encoded certificates of the file to a pool
+func loadCertPool(path string) (*x509.CertPool, error) {
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ca file: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates found on ca file %s", path)
+	}
+
+	return pool, nil
+}
+
+// To get the modification times of the files, zero time for the empty paths
+func fileModTimes(paths ...string) ([]time.Time, error) {
+
+	modTimes := make([]time.Time, len(paths))
+	for i, path := range paths {
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		modTimes[i] = info.ModTime()
+	}
+
+	return modTimes, nil
+}
+
+func equalTimes(a, b []time.Time) bool {
+
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,216 @@
+package sdk
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"log/slog"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"stream-sdk/pkg/pb"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// self-signed ca to issue the certificates of the tests
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T, name string) *testCA {
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCA{
+		cert: cert,
+		key:  key,
+		pem:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+// To issue a certificate of the name for both server and client auth, returns the pem encoded certificate and key
+func (ca *testCA) issue(t *testing.T, name string) ([]byte, []byte) {
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// To write the file on the dir with the modification time, so the modification detected on rewrite
+func writeTestFile(t *testing.T, dir, name string, data []byte, modTime time.Time) string {
+
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+
+	return path
+}
+
+// To start the server requiring the client certificates issued by the ca, returns the address of the server
+func startTLSServer(t *testing.T, ca *testCA) string {
+
+	certPEM, keyPEM := ca.issue(t, "localhost")
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	})))
+	pb.RegisterStreamServiceServer(server, newMemStreamServer())
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String()
+}
+
+func TestMutualTLS(t *testing.T) {
+
+	ca := newTestCA(t, "test ca")
+	otherCA := newTestCA(t, "other ca")
+	addr := startTLSServer(t, ca)
+
+	dir := t.TempDir()
+	now := time.Now()
+	caFile := writeTestFile(t, dir, "ca.pem", ca.pem, now)
+	otherCAFile := writeTestFile(t, dir, "other_ca.pem", otherCA.pem, now)
+	certPEM, keyPEM := ca.issue(t, "gateway")
+	certFile := writeTestFile(t, dir, "cert.pem", certPEM, now)
+	keyFile := writeTestFile(t, dir, "key.pem", keyPEM, now)
+	otherCertPEM, otherKeyPEM := otherCA.issue(t, "gateway")
+	otherCertFile := writeTestFile(t, dir, "other_cert.pem", otherCertPEM, now)
+	otherKeyFile := writeTestFile(t, dir, "other_key.pem", otherKeyPEM, now)
+
+	testCases := map[string]struct {
+		cfg          TLSConfig
+		expectUpload bool
+	}{
+		"client_certificate_of_the_ca_should_upload": {
+			cfg:          TLSConfig{CertFile: certFile, KeyFile: keyFile, CAFile: caFile, ServerName: "localhost"},
+			expectUpload: true,
+		},
+		"no_client_certificate_should_fail": {
+			cfg: TLSConfig{CAFile: caFile, ServerName: "localhost"},
+		},
+		"client_certificate_of_other_ca_should_fail": {
+			cfg: TLSConfig{CertFile: otherCertFile, KeyFile: otherKeyFile, CAFile: caFile, ServerName: "localhost"},
+		},
+		"server_certificate_of_not_trusted_ca_should_fail": {
+			cfg: TLSConfig{CertFile: certFile, KeyFile: keyFile, CAFile: otherCAFile, ServerName: "localhost"},
+		},
+		"server_name_not_matching_should_fail": {
+			cfg: TLSConfig{CertFile: certFile, KeyFile: keyFile, CAFile: caFile, ServerName: "other"},
+		},
+	}
+
+	for name, test := range testCases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			client, err := NewClient(addr, WithTLS(test.cfg), WithRequestTimeout(time.Second*5))
+			require.NoError(t, err)
+			defer client.Close()
+
+			_, err = client.Upload(context.Background(), "file", "text/plain", strings.NewReader("data"))
+			if test.expectUpload {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestTLSCredentialsReload(t *testing.T) {
+
+	ca := newTestCA(t, "test ca")
+	dir := t.TempDir()
+	loadedAt := time.Now().Add(-time.Minute)
+
+	certPEM, keyPEM := ca.issue(t, "gateway")
+	cfg := TLSConfig{
+		CertFile: writeTestFile(t, dir, "cert.pem", certPEM, loadedAt),
+		KeyFile:  writeTestFile(t, dir, "key.pem", keyPEM, loadedAt),
+		CAFile:   writeTestFile(t, dir, "ca.pem", ca.pem, loadedAt),
+	}
+	creds, err := newTLSCredentials(cfg, slog.Default())
+	require.NoError(t, err)
+
+	loadedCert := func() []byte {
+		config, err := creds.tlsConfig()
+		require.NoError(t, err)
+		return config.Certificates[0].Certificate[0]
+	}
+	initial := loadedCert()
+
+	// the renewed certificate should be used after the files modified
+	renewedAt := loadedAt.Add(time.Second)
+	certPEM, keyPEM = ca.issue(t, "gateway")
+	writeTestFile(t, dir, "cert.pem", certPEM, renewedAt)
+	writeTestFile(t, dir, "key.pem", keyPEM, renewedAt)
+	renewed := loadedCert()
+	assert.NotEqual(t, initial, renewed, "renewed certificate should be loaded")
+
+	// the loaded certificate should be kept if the files are invalid
+	writeTestFile(t, dir, "cert.pem", []byte("partially written"), renewedAt.Add(time.Second))
+	assert.Equal(t, renewed, loadedCert(), "loaded certificate should be kept on failed reload")
+
+	// the invalid files should fail on create
+	_, err = newTLSCredentials(cfg, slog.Default())
+	assert.Error(t, err)
+}
@@ -0,0 +1,502 @@
+package sdk
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"stream-sdk/pkg/pb"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// wait time before the first retry of a failed upload, doubled on each retry until the max
+	retryBackoff    = time.Millisecond * 200
+	maxRetryBackoff = time.Second * 10
+)
+
+// UploadOption to configure an upload
+type UploadOption func(*uploadOptions)
+
+type uploadOptions struct {
+	size     *int64
+	sha256   string
+	progress *progressReporter
+	retries  int
+}
+
+// To declare the size of the file, the server verifies the size of the data received.
+// the files of known size can upload as parts over parallel streams if multipart enabled on the client
+func WithSize(size int64) UploadOption {
+	return func(o *uploadOptions) {
+		o.size = &size
+	}
+}
+
+// To provide the expected sha256 digest of the file as hex, the server verifies the data received
+func WithSHA256(digest string) UploadOption {
+	return func(o *uploadOptions) {
+		o.sha256 = digest
+	}
+}
+
+// To call the progress func with the total size of the data sent on each chunk sent,
+// the data sent again on a retry is not counted again
+func WithProgress(progress func(uploaded int64)) UploadOption {
+	return func(o *uploadOptions) {
+		o.progress = &progressReporter{report: progress}
+	}
+}
+
+// To retry the upload on the transient failures (unavailable, aborted or resource exhausted) up to the retries.
+// the upload resumed from the data committed on server, so the body should be an io.Seeker to resume
+// after some data sent (the parts of a multipart upload uploaded again on the failures)
+func WithRetries(retries int) UploadOption {
+	return func(o *uploadOptions) {
+		o.retries = retries
+	}
+}
+
+// To upload the file data read from the body until EOF, returns the details of the uploaded file.
+// the sha256 of the data sent verified with the checksum computed by the server,
+// the file removed and ErrChecksumMismatch returned if not matching
+func (c *Client) Upload(ctx context.Context, name, contentType string, body io.Reader,
+	opts ...UploadOption) (UploadResult, error) {
+
+	var options uploadOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	info := &pb.FileMetaData{
+		Name:        name,
+		ContentType: contentType,
+		Sha256:      options.sha256,
+		Size:        options.size,
+	}
+
+	// the streams closed on return and cancelled on the deadline or the request cancelled
+	ctx, cancel := withTimeout(ctx, c.uploadTimeout)
+	defer cancel()
+
+	// split the large files across parallel streams, each part verified by the server.
+	// only the body read at offsets split, the parts of other bodies would have to be buffered in memory
+	readerAt, isReaderAt := body.(io.ReaderAt)
+	if isReaderAt && c.multipartThreshold > 0 && options.size != nil && *options.size >= c.multipartThreshold {
+		res, err := c.uploadMultipart(ctx, info, readerAt, options)
+		if err != nil {
+			return UploadResult{}, err
+		}
+		return toUploadResult(res), nil
+	}
+
+	var (
+		tracker = &streamTracker{checksum: sha256.New(), progress: options.progress}
+		res     *pb.UploadResponse
+		err     error
+	)
+	if options.retries > 0 {
+		res, err = c.uploadResumable(ctx, info, body, options.retries, tracker)
+	} else {
+		res, err = c.uploadStream(ctx, info, body, tracker)
+	}
+	if err != nil {
+		return UploadResult{}, err
+	}
+	if err := c.verifyUpload(ctx, res, tracker.checksum); err != nil {
+		return UploadResult{}, err
+	}
+
+	return toUploadResult(res), nil
+}
+
+// To upload the file data read from the body until EOF on a single stream, the progress func called with the
+// total size of the data stored by the server on each ack, rather than the data sent. returns the details of
+// the uploaded file verified as on Upload. the upload neither split to parts nor retried, so the multipart
+// of the client and the progress and retries options not used
+func (c *Client) UploadWithProgress(ctx context.Context, name, contentType string, body io.Reader,
+	progress func(written int64), opts ...UploadOption) (UploadResult, error) {
+
+	var options uploadOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	info := &pb.FileMetaData{
+		Name:        name,
+		ContentType: contentType,
+		Sha256:      options.sha256,
+		Size:        options.size,
+	}
+
+	ctx, cancel := withTimeout(ctx, c.uploadTimeout)
+	defer cancel()
+
+	streamSvc, err := c.client.UploadWithProgress(ctx)
+	if err != nil {
+		return UploadResult{}, fmt.Errorf("failed to call upload with progress method for stream client: %w", err)
+	}
+
+	// the data sent on a separate goroutine to receive the acks while sending,
+	// the stream cancelled if the sending failed
+	tracker := &streamTracker{checksum: sha256.New()}
+	sent := make(chan error, 1)
+	go func() {
+		err := c.sendProgressStream(streamSvc, info, body, tracker)
+		// the error of the sending ready before the stream cancelled by it
+		sent <- err
+		if err != nil {
+			cancel()
+		}
+	}()
+
+	var fileID string
+	for {
+		res, err := streamSvc.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// the stream cancelled or closed by the server, the sending stopped.
+			// the error of the sending returned only if the stream cancelled by the failed sending,
+			// otherwise the sending can fail by the cancel and the status of the server returned
+			cancel()
+			if sendErr := <-sent; sendErr != nil && status.Code(err) == codes.Canceled {
+				return UploadResult{}, sendErr
+			}
+			return UploadResult{}, fmt.Errorf("failed to receive upload progress: %w", err)
+		}
+		if progress != nil {
+			progress(res.GetWritten())
+		}
+		if res.GetCompleted() {
+			fileID = res.GetId()
+		}
+	}
+	if err := <-sent; err != nil {
+		return UploadResult{}, err
+	}
+	if fileID == "" {
+		return UploadResult{}, errors.New("upload stream closed before the upload completed")
+	}
+
+	// the progress carries no checksum, so verified with the details of the stored file
+	file, err := c.GetFile(ctx, fileID)
+	if err != nil {
+		return UploadResult{}, err
+	}
+	res := &pb.UploadResponse{Id: file.ID, Size: file.Size, Sha256: file.SHA256, Crc32C: file.CRC32C,
+		Offset: file.Size, Completed: true}
+	if err := c.verifyUpload(ctx, res, tracker.checksum); err != nil {
+		return UploadResult{}, err
+	}
+
+	return toUploadResult(res), nil
+}
+
+// To send the file details and the data of the body on the upload with progress stream, then close the sending
+func (c *Client) sendProgressStream(streamSvc pb.StreamService_UploadWithProgressClient, info *pb.FileMetaData,
+	body io.Reader, tracker *streamTracker) error {
+
+	err := streamSvc.Send(&pb.UploadRequest{
+		File: &pb.UploadRequest_Info{Info: info},
+	})
+	if err != nil {
+		// the actual error received on the stream if closed by the server
+		if err == io.EOF {
+			return nil
+		}
+		return fmt.Errorf("failed to send file details: %w", err)
+	}
+
+	err = c.sendChunks(body, 0, func(chunk *pb.Chunk) error {
+		if err := sendEncoded(streamSvc, &pb.UploadRequest{
+			File: &pb.UploadRequest_Chunk{Chunk: chunk},
+		}); err != nil {
+			return err
+		}
+		tracker.track(chunk)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := streamSvc.CloseSend(); err != nil {
+		return fmt.Errorf("failed to close sending: %w", err)
+	}
+
+	return nil
+}
+
+// To verify the file stored by the checksum of the data sent, the file removed if not matching
+func (c *Client) verifyUpload(ctx context.Context, res *pb.UploadResponse, checksum hash.Hash) error {
+
+	// the file stored not the same as the data sent, so not keep it
+	digest := hex.EncodeToString(checksum.Sum(nil))
+	if res.GetSha256() != "" && res.GetSha256() != digest {
+		deleteCtx, deleteCancel := withTimeout(context.WithoutCancel(ctx), c.requestTimeout)
+		defer deleteCancel()
+		if _, err := c.DeleteFile(deleteCtx, res.GetId()); err != nil {
+			c.logger.ErrorContext(deleteCtx, "failed to delete file not matching the checksum",
+				"file_id", res.GetId(), "error", err)
+		}
+		return fmt.Errorf("%w: sent sha256 %s but stored %s", ErrChecksumMismatch, digest, res.GetSha256())
+	}
+
+	return nil
+}
+
+// To upload the file on a single stream
+func (c *Client) uploadStream(ctx context.Context, info *pb.FileMetaData, body io.Reader,
+	tracker *streamTracker) (*pb.UploadResponse, error) {
+
+	// get the stream service
+	streamSvc, err := c.client.Upload(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call upload method for stream client: %w", err)
+	}
+
+	// first send file meta data
+	err = streamSvc.Send(&pb.UploadRequest{
+		File: &pb.UploadRequest_Info{Info: info},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to send file details: %w", err)
+	}
+
+	err = c.sendChunks(body, 0, func(chunk *pb.Chunk) error {
+		if err := sendEncoded(streamSvc, &pb.UploadRequest{
+			File: &pb.UploadRequest_Chunk{Chunk: chunk},
+		}); err != nil {
+			return err
+		}
+		tracker.track(chunk)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// close streaming
+	res, err := streamSvc.CloseAndRecv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to close streaming: %w", err)
+	}
+
+	return res, nil
+}
+
+// To upload the file on an upload session, the upload resumed from the offset committed on server
+// on the transient failures. the session removed if the upload not completed
+func (c *Client) uploadResumable(ctx context.Context, info *pb.FileMetaData, body io.Reader, retries int,
+	tracker *streamTracker) (*pb.UploadResponse, error) {
+
+	sessionID, err := c.createUploadSession(ctx, info)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		reader   = &positionReader{reader: body}
+		res      *pb.UploadResponse
+		attempts int
+	)
+	err = retry(ctx, retries, func() error {
+
+		if attempts++; attempts > 1 {
+			session, err := c.GetUploadSession(ctx, sessionID)
+			if err != nil {
+				return err
+			}
+			// the upload completed but the response not received
+			if session.Completed {
+				file, err := c.GetFile(ctx, sessionID)
+				if err != nil {
+					return err
+				}
+				res = &pb.UploadResponse{Id: file.ID, Size: file.Size, Sha256: file.SHA256, Crc32C: file.CRC32C,
+					Offset: file.Size, Completed: true}
+				return nil
+			}
+			if err := reader.seek(session.Offset); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		res, err = c.resumeUpload(ctx, &pb.ResumeInfo{SessionId: sessionID, Offset: reader.position},
+			reader, tracker)
+		return err
+	})
+	if err != nil {
+		c.abortUploadSession(ctx, sessionID)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// To upload the data read from the body on a resume upload stream from the offset of the resume info
+func (c *Client) resumeUpload(ctx context.Context, info *pb.ResumeInfo, body io.Reader,
+	tracker *streamTracker) (*pb.UploadResponse, error) {
+
+	streamSvc, err := c.client.ResumeUpload(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call resume upload method for stream client: %w", err)
+	}
+
+	// first send the resume info
+	err = streamSvc.Send(&pb.ResumeUploadRequest{
+		File: &pb.ResumeUploadRequest_Info{Info: info},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to send resume info: %w", err)
+	}
+
+	// chunk offsets continued from the resume offset
+	err = c.sendChunks(body, info.GetOffset(), func(chunk *pb.Chunk) error {
+		if err := sendEncoded(streamSvc, &pb.ResumeUploadRequest{
+			File: &pb.ResumeUploadRequest_Chunk{Chunk: chunk},
+		}); err != nil {
+			return err
+		}
+		tracker.track(chunk)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := streamSvc.CloseAndRecv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to close streaming: %w", err)
+	}
+
+	return res, nil
+}
+
+// To call the func until succeeded or the retries exhausted, only the transient failures retried after a backoff
+func retry(ctx context.Context, retries int, fn func() error) error {
+
+	backoff := retryBackoff
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= retries || !isRetryable(err) {
+			return err
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return err
+		}
+		backoff = min(backoff*2, maxRetryBackoff)
+	}
+}
+
+// To check the error is a transient failure of the server or the connection
+func isRetryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.Aborted, codes.ResourceExhausted:
+		return true
+	default:
+		return false
+	}
+}
+
+// To report the progress of an upload as the total size of the data sent on all streams of the upload
+type progressReporter struct {
+	report func(uploaded int64)
+
+	mu       sync.Mutex
+	uploaded int64
+}
+
+func (p *progressReporter) add(n int64) {
+
+	if p == nil || p.report == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.uploaded += n
+	p.report(p.uploaded)
+}
+
+// To track the data sent on the streams of a file or part by the offsets of the chunks,
+// the data sent again after a resume counted only once
+type streamTracker struct {
+	sent     int64     // end offset of the data sent
+	checksum hash.Hash // sha256 of the data sent
+	progress *progressReporter
+}
+
+func (t *streamTracker) track(chunk *pb.Chunk) {
+
+	if t == nil {
+		return
+	}
+
+	// the chunks are sent again from an offset not after the data sent
+	data := chunk.GetData()
+	end := chunk.GetOffset() + int64(len(data))
+	if end <= t.sent {
+		return
+	}
+	data = data[int64(len(data))-(end-t.sent):]
+
+	t.checksum.Write(data)
+	t.sent = end
+	t.progress.add(int64(len(data)))
+}
+
+// reader to track the position of the body, so the upload can resume from the offset committed on server
+type positionReader struct {
+	reader   io.Reader
+	position int64
+}
+
+func (r *positionReader) Read(data []byte) (int, error) {
+	n, err := r.reader.Read(data)
+	r.position += int64(n)
+	return n, err
+}
+
+// To move the position of the body to the offset, the body should be a seeker if it is already read after the offset
+func (r *positionReader) seek(offset int64) error {
+
+	if offset == r.position {
+		return nil
+	}
+
+	seeker, ok := r.reader.(io.Seeker)
+	if !ok {
+		return fmt.Errorf("%w: body read %d bytes but the server committed %d bytes and body is not a seeker",
+			ErrNotResumable, r.position, offset)
+	}
+	if _, err := seeker.Seek(offset-r.position, io.SeekCurrent); err != nil {
+		return fmt.Errorf("%w: failed to seek the body to offset %d: %v", ErrNotResumable, offset, err)
+	}
+	r.position = offset
+
+	return nil
+}
+
+func toUploadResult(res *pb.UploadResponse) UploadResult {
+	return UploadResult{
+		ID:     res.GetId(),
+		Size:   res.GetSize(),
+		SHA256: res.GetSha256(),
+		CRC32C: res.GetCrc32C(),
+	}
+}
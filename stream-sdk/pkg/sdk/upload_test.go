@@ -0,0 +1,563 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"stream-sdk/pkg/pb"
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// stream service storing the files and upload sessions in memory,
+// the upload streams can fail after receiving some data to test the retries
+type memStreamServer struct {
+	pb.UnimplementedStreamServiceServer
+	failAfter int64 // size of the data received on a stream to fail with unavailable, zero to not fail
+	failures  int   // count of the streams to fail
+	failErr   error // error to fail the streams with instead of unavailable
+	corrupt   bool  // store the files with a different checksum than the data received
+
+	mu       sync.Mutex
+	files    map[string][]byte
+	sessions map[string][]byte
+	deleted  []string
+}
+
+func newMemStreamServer() *memStreamServer {
+	return &memStreamServer{
+		files:    make(map[string][]byte),
+		sessions: make(map[string][]byte),
+	}
+}
+
+// To receive the chunks of the stream appended to the data, fails the stream after the fail size received
+func (s *memStreamServer) receive(recv func() (*pb.Chunk, error), data *[]byte) error {
+
+	var received int64
+	for {
+		chunk, err := recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		s.mu.Lock()
+		*data = append(*data, chunk.GetData()...)
+		received += int64(len(chunk.GetData()))
+		fail := s.failAfter > 0 && s.failures > 0 && received >= s.failAfter
+		if fail {
+			s.failures--
+		}
+		s.mu.Unlock()
+
+		if fail && s.failErr != nil {
+			return s.failErr
+		}
+		if fail {
+			return status.Error(codes.Unavailable, "connection lost")
+		}
+	}
+}
+
+func (s *memStreamServer) uploadResponse(id string, data []byte) *pb.UploadResponse {
+
+	digest := sha256.Sum256(data)
+	if s.corrupt {
+		digest = sha256.Sum256(append(data, 'x'))
+	}
+
+	return &pb.UploadResponse{Id: id, Size: int64(len(data)), Sha256: hex.EncodeToString(digest[:]),
+		Offset: int64(len(data)), Completed: true}
+}
+
+func (s *memStreamServer) Upload(stream pb.StreamService_UploadServer) error {
+
+	if _, err := stream.Recv(); err != nil {
+		return err
+	}
+
+	var data []byte
+	err := s.receive(func() (*pb.Chunk, error) {
+		req, err := stream.Recv()
+		return req.GetChunk(), err
+	}, &data)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	id := fmt.Sprintf("file_%d", len(s.files))
+	s.files[id] = data
+	s.mu.Unlock()
+
+	return stream.SendAndClose(s.uploadResponse(id, data))
+}
+
+// To store the file as on upload, the progress sent with the size of the data received after each chunk
+func (s *memStreamServer) UploadWithProgress(stream pb.StreamService_UploadWithProgressServer) error {
+
+	if _, err := stream.Recv(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	id := fmt.Sprintf("file_%d", len(s.files))
+	s.mu.Unlock()
+
+	var data []byte
+	err := s.receive(func() (*pb.Chunk, error) {
+		if len(data) > 0 {
+			if err := stream.Send(&pb.UploadProgress{Id: id, Written: int64(len(data))}); err != nil {
+				return nil, err
+			}
+		}
+		req, err := stream.Recv()
+		return req.GetChunk(), err
+	}, &data)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.files[id] = data
+	s.mu.Unlock()
+
+	return stream.Send(&pb.UploadProgress{Id: id, Written: int64(len(data)), Completed: true})
+}
+
+func (s *memStreamServer) GetFile(ctx context.Context, req *pb.GetFileRequest) (*pb.FileDetails, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, ok := s.files[req.GetId()]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "file not found")
+	}
+	res := s.uploadResponse(req.GetId(), data)
+
+	return &pb.FileDetails{Id: res.GetId(), Size: res.GetSize(), Sha256: res.GetSha256()}, nil
+}
+
+func (s *memStreamServer) CreateUploadSession(ctx context.Context, req *pb.FileMetaData) (*pb.UploadSession, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := fmt.Sprintf("session_%d", len(s.sessions))
+	s.sessions[id] = []byte{}
+
+	return &pb.UploadSession{Id: id}, nil
+}
+
+func (s *memStreamServer) GetUploadSession(ctx context.Context, req *pb.UploadSessionRequest) (*pb.UploadSession, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if data, ok := s.files[req.GetId()]; ok {
+		return &pb.UploadSession{Id: req.GetId(), Offset: int64(len(data)), Completed: true}, nil
+	}
+	data, ok := s.sessions[req.GetId()]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "upload session not found")
+	}
+
+	return &pb.UploadSession{Id: req.GetId(), Offset: int64(len(data))}, nil
+}
+
+func (s *memStreamServer) ResumeUpload(stream pb.StreamService_ResumeUploadServer) error {
+
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	info := req.GetInfo()
+
+	s.mu.Lock()
+	data, ok := s.sessions[info.GetSessionId()]
+	s.mu.Unlock()
+	if !ok {
+		return status.Error(codes.NotFound, "upload session not found")
+	}
+	if int64(len(data)) != info.GetOffset() {
+		return status.Errorf(codes.FailedPrecondition, "offset %d not matching committed offset %d",
+			info.GetOffset(), len(data))
+	}
+
+	// the data received before a failure committed to resume
+	err = s.receive(func() (*pb.Chunk, error) {
+		req, err := stream.Recv()
+		return req.GetChunk(), err
+	}, &data)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sessions[info.GetSessionId()] = data
+	if err != nil {
+		return err
+	}
+
+	delete(s.sessions, info.GetSessionId())
+	s.files[info.GetSessionId()] = data
+
+	return stream.SendAndClose(s.uploadResponse(info.GetSessionId(), data))
+}
+
+func (s *memStreamServer) DeleteUploadSession(ctx context.Context,
+	req *pb.UploadSessionRequest) (*pb.DeleteUploadSessionResponse, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sessions, req.GetId())
+	s.deleted = append(s.deleted, req.GetId())
+
+	return &pb.DeleteUploadSessionResponse{}, nil
+}
+
+func (s *memStreamServer) DeleteFile(ctx context.Context, req *pb.DeleteFileRequest) (*pb.DeleteFileResponse, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.files, req.GetId())
+	s.deleted = append(s.deleted, req.GetId())
+
+	return &pb.DeleteFileResponse{}, nil
+}
+
+func (s *memStreamServer) Download(req *pb.DownloadRequest, stream pb.StreamService_DownloadServer) error {
+
+	s.mu.Lock()
+	data, ok := s.files[req.GetId()]
+	s.mu.Unlock()
+	if !ok {
+		return status.Error(codes.NotFound, "file not found")
+	}
+
+	res := s.uploadResponse(req.GetId(), data)
+	err := stream.Send(&pb.DownloadResponse{
+		File: &pb.DownloadResponse_Info{
+			Info: &pb.FileDetails{Id: res.GetId(), Size: res.GetSize(), Sha256: res.GetSha256()},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	for len(data) > 0 {
+		n := min(len(data), 3)
+		if err := stream.Send(&pb.DownloadResponse{File: &pb.DownloadResponse_Data{Data: data[:n]}}); err != nil {
+			return err
+		}
+		data = data[n:]
+	}
+
+	return nil
+}
+
+// reader hiding the seeker of the reader
+type nonSeekableReader struct {
+	reader io.Reader
+}
+
+func (r nonSeekableReader) Read(data []byte) (int, error) {
+	return r.reader.Read(data)
+}
+
+func TestUpload(t *testing.T) {
+
+	data := "0123456789abcdefghij"
+	digest := sha256.Sum256([]byte(data))
+
+	testCases := map[string]struct {
+		server           func() *memStreamServer
+		body             io.Reader
+		opts             []UploadOption
+		expectedResult   UploadResult
+		expectedError    error
+		expectedCode     codes.Code
+		expectedFiles    map[string]string
+		expectedDeleted  []string
+		expectedProgress int64
+	}{
+		"successful_upload_should_return_the_file": {
+			server: newMemStreamServer,
+			body:   strings.NewReader(data),
+			expectedResult: UploadResult{ID: "file_0", Size: int64(len(data)),
+				SHA256: hex.EncodeToString(digest[:])},
+			expectedFiles:    map[string]string{"file_0": data},
+			expectedProgress: int64(len(data)),
+		},
+		"stored_checksum_not_matching_should_remove_the_file": {
+			server: func() *memStreamServer {
+				server := newMemStreamServer()
+				server.corrupt = true
+				return server
+			},
+			body:             strings.NewReader(data),
+			expectedError:    ErrChecksumMismatch,
+			expectedFiles:    map[string]string{},
+			expectedDeleted:  []string{"file_0"},
+			expectedProgress: int64(len(data)),
+		},
+		"transient_failure_without_retries_should_return_error": {
+			server: func() *memStreamServer {
+				server := newMemStreamServer()
+				server.failAfter, server.failures = 8, 1
+				return server
+			},
+			body:          strings.NewReader(data),
+			expectedCode:  codes.Unavailable,
+			expectedFiles: map[string]string{},
+		},
+		"transient_failures_should_resume_from_the_committed_offset": {
+			server: func() *memStreamServer {
+				server := newMemStreamServer()
+				server.failAfter, server.failures = 8, 2
+				return server
+			},
+			body: strings.NewReader(data),
+			opts: []UploadOption{WithRetries(2)},
+			expectedResult: UploadResult{ID: "session_0", Size: int64(len(data)),
+				SHA256: hex.EncodeToString(digest[:])},
+			expectedFiles:    map[string]string{"session_0": data},
+			expectedProgress: int64(len(data)),
+		},
+		"retries_exhausted_should_abort_the_upload": {
+			server: func() *memStreamServer {
+				server := newMemStreamServer()
+				server.failAfter, server.failures = 8, 2
+				return server
+			},
+			body:            strings.NewReader(data),
+			opts:            []UploadOption{WithRetries(1)},
+			expectedCode:    codes.Unavailable,
+			expectedFiles:   map[string]string{},
+			expectedDeleted: []string{"session_0"},
+		},
+		"not_seekable_body_should_not_resume": {
+			server: func() *memStreamServer {
+				server := newMemStreamServer()
+				server.failAfter, server.failures = 8, 1
+				return server
+			},
+			body:            nonSeekableReader{reader: strings.NewReader(data)},
+			opts:            []UploadOption{WithRetries(2)},
+			expectedError:   ErrNotResumable,
+			expectedFiles:   map[string]string{},
+			expectedDeleted: []string{"session_0"},
+		},
+	}
+
+	for name, test := range testCases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			server := test.server()
+			client := newTestClient(t, server, 0, WithChunkSize(4, false))
+
+			var progress int64
+			opts := append(test.opts, WithProgress(func(uploaded int64) {
+				assert.Greater(t, uploaded, progress)
+				progress = uploaded
+			}))
+
+			result, err := client.Upload(context.Background(), "file", "text/plain", test.body, opts...)
+			switch {
+			case test.expectedError != nil:
+				assert.ErrorIs(t, err, test.expectedError)
+			case test.expectedCode != codes.OK:
+				assert.Equal(t, test.expectedCode, status.Code(err))
+			default:
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.expectedResult, result)
+
+			files := make(map[string]string)
+			for id, data := range server.files {
+				files[id] = string(data)
+			}
+			assert.Equal(t, test.expectedFiles, files)
+			assert.Equal(t, test.expectedDeleted, server.deleted)
+			if test.expectedProgress > 0 {
+				assert.Equal(t, test.expectedProgress, progress)
+			}
+		})
+	}
+}
+
+func TestUploadWithProgress(t *testing.T) {
+
+	data := "0123456789abcdefghij"
+	digest := sha256.Sum256([]byte(data))
+
+	checksumMismatch, err := status.New(codes.DataLoss, "checksum mismatch").WithDetails(&errdetails.ErrorInfo{
+		Reason: ReasonChecksumMismatch,
+		Domain: "stream-service",
+	})
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		server           func() *memStreamServer
+		body             io.Reader
+		expectedResult   UploadResult
+		expectedError    error
+		expectedCode     codes.Code
+		expectedReason   string
+		expectedFiles    map[string]string
+		expectedDeleted  []string
+		expectedProgress []int64
+	}{
+		"successful_upload_should_report_the_data_stored": {
+			server: newMemStreamServer,
+			body:   strings.NewReader(data),
+			expectedResult: UploadResult{ID: "file_0", Size: int64(len(data)),
+				SHA256: hex.EncodeToString(digest[:])},
+			expectedFiles:    map[string]string{"file_0": data},
+			expectedProgress: []int64{4, 8, 12, 16, 20, 20},
+		},
+		"stored_checksum_not_matching_should_remove_the_file": {
+			server: func() *memStreamServer {
+				server := newMemStreamServer()
+				server.corrupt = true
+				return server
+			},
+			body:             strings.NewReader(data),
+			expectedError:    ErrChecksumMismatch,
+			expectedFiles:    map[string]string{},
+			expectedDeleted:  []string{"file_0"},
+			expectedProgress: []int64{4, 8, 12, 16, 20, 20},
+		},
+		"server_failure_should_return_error": {
+			server: func() *memStreamServer {
+				server := newMemStreamServer()
+				server.failAfter, server.failures = 8, 1
+				return server
+			},
+			body:             strings.NewReader(data),
+			expectedCode:     codes.Unavailable,
+			expectedFiles:    map[string]string{},
+			expectedProgress: []int64{4},
+		},
+		"server_status_while_sending_should_return_the_status": {
+			server: func() *memStreamServer {
+				server := newMemStreamServer()
+				server.failAfter, server.failures = 8, 1
+				server.failErr = checksumMismatch.Err()
+				return server
+			},
+			// the data still sending when the server closed the stream
+			body:           bytes.NewReader(make([]byte, 1<<20)),
+			expectedCode:   codes.DataLoss,
+			expectedReason: ReasonChecksumMismatch,
+			expectedFiles:  map[string]string{},
+		},
+		"body_failure_should_cancel_the_upload": {
+			server: newMemStreamServer,
+			body: io.MultiReader(strings.NewReader(data[:8]),
+				iotest.ErrReader(errors.New("disk error"))),
+			expectedError: errors.New("failed to read from file: disk error"),
+			expectedFiles: map[string]string{},
+		},
+	}
+
+	for name, test := range testCases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			server := test.server()
+			client := newTestClient(t, server, 0, WithChunkSize(4, false))
+
+			var progress []int64
+			result, err := client.UploadWithProgress(context.Background(), "file", "text/plain", test.body,
+				func(written int64) {
+					progress = append(progress, written)
+				})
+			switch {
+			case test.expectedError != nil && errors.Is(test.expectedError, ErrChecksumMismatch):
+				assert.ErrorIs(t, err, test.expectedError)
+			case test.expectedError != nil:
+				assert.EqualError(t, err, test.expectedError.Error())
+			case test.expectedCode != codes.OK:
+				assert.Equal(t, test.expectedCode, status.Code(err))
+				assert.Equal(t, test.expectedReason, ErrorReason(err))
+			default:
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.expectedResult, result)
+
+			files := make(map[string]string)
+			for id, data := range server.files {
+				files[id] = string(data)
+			}
+			assert.Equal(t, test.expectedFiles, files)
+			assert.Equal(t, test.expectedDeleted, server.deleted)
+			if test.expectedProgress != nil {
+				assert.Equal(t, test.expectedProgress, progress)
+			}
+		})
+	}
+}
+
+func TestDownload(t *testing.T) {
+
+	testCases := map[string]struct {
+		corrupt       bool
+		expectedError error
+	}{
+		"matching_data_should_read_until_eof": {},
+		"data_not_matching_checksum_should_return_error": {
+			corrupt:       true,
+			expectedError: ErrChecksumMismatch,
+		},
+	}
+
+	for name, test := range testCases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			server := newMemStreamServer()
+			server.files["file_0"] = []byte("0123456789")
+			server.corrupt = test.corrupt
+			client := newTestClient(t, server, 0)
+
+			reader, details, err := client.Download(context.Background(), "file_0")
+			assert.NoError(t, err)
+			defer reader.Close()
+			assert.Equal(t, "file_0", details.ID)
+
+			var buffer bytes.Buffer
+			_, err = io.Copy(&buffer, reader)
+			if test.expectedError == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, test.expectedError)
+			}
+			assert.Equal(t, "0123456789", buffer.String())
+		})
+	}
+}
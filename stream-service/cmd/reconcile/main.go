@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"stream-service/pkg/config"
+	"stream-service/pkg/di"
+	"text/tabwriter"
+)
+
+// admin command to reconcile the storage and database once
+func main() {
+
+	repair := flag.Bool("repair", false, "repair the mismatches, only reported by default")
+	jsonOutput := flag.Bool("json", false, "print the report as json")
+	flag.Parse()
+
+	cfg, err := config.LoadOfflineConfig()
+	if err != nil {
+		log.Fatalf("failed to load config: %v", err)
+	}
+
+	reconciler, err := di.InitializeReconciler(cfg)
+	if err != nil {
+		log.Fatalf("failed to initialize reconciler: %v", err)
+	}
+
+	report, err := reconciler.Run(context.Background(), *repair)
+	if err != nil {
+		log.Fatalf("failed to reconcile: %v", err)
+	}
+
+	if *jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			log.Fatalf("failed to encode report: %v", err)
+		}
+		return
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "KIND\tFILE ID\tDETAIL\tREPAIRED")
+	for _, mismatch := range report.Mismatches {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%t\n", mismatch.Kind, mismatch.FileID, mismatch.Detail, mismatch.Repaired)
+	}
+	writer.Flush()
+
+	fmt.Printf("\nchecked %d files and %d objects, found %d mismatches\n",
+		report.CheckedFiles, report.CheckedObjects, len(report.Mismatches))
+	if !*repair && len(report.Mismatches) > 0 {
+		fmt.Println("nothing repaired, run with -repair to repair the mismatches")
+	}
+}
@@ -0,0 +1,111 @@
+package interceptor
+
+import (
+	"context"
+	"log/slog"
+	"stream-service/pkg/logger"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// To log the unary calls with the method, peer, bytes received, duration and status code
+func UnaryAccessLog(log *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+
+		start := time.Now()
+		entry := &accessEntry{}
+		res, err := handler(context.WithValue(ctx, accessEntryKey{}, entry), req)
+
+		var received int64
+		if msg, ok := req.(proto.Message); ok {
+			received = int64(proto.Size(msg))
+		}
+		logAccess(ctx, log, info.FullMethod, received, start, entry, err)
+
+		return res, err
+	}
+}
+
+// To log the stream calls with the method, peer, bytes received, duration and status code
+func StreamAccessLog(log *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+
+		start := time.Now()
+		entry := &accessEntry{}
+		counter := &countingStream{ServerStream: &serverStream{
+			ServerStream: stream,
+			ctx:          context.WithValue(stream.Context(), accessEntryKey{}, entry),
+		}}
+		err := handler(srv, counter)
+
+		logAccess(stream.Context(), log, info.FullMethod, counter.received.Load(), start, entry, err)
+
+		return err
+	}
+}
+
+// details of the call recorded by the next interceptors for the access log
+type accessEntry struct {
+	owner string // owner authenticated, empty if the call not authenticated
+}
+
+type accessEntryKey struct{}
+
+// To record the authenticated owner of the call for the access log, if the call logged
+func recordAccessOwner(ctx context.Context, owner string) {
+	if entry, ok := ctx.Value(accessEntryKey{}).(*accessEntry); ok {
+		entry.owner = owner
+	}
+}
+
+func logAccess(ctx context.Context, log *slog.Logger, method string, received int64, start time.Time,
+	entry *accessEntry, err error) {
+
+	peerAddr := "unknown"
+	if p, ok := peer.FromContext(ctx); ok {
+		peerAddr = p.Addr.String()
+	}
+	code := status.Code(err)
+
+	attrs := []any{
+		slog.String("method", method),
+		slog.String("peer", peerAddr),
+		slog.Int64("bytes_received", received),
+		slog.Duration("duration", time.Since(start)),
+		slog.String("code", code.String()),
+	}
+	// the owner authenticated after the access log started, so only the owner recorded by the auth logged
+	if entry.owner != "" {
+		attrs = append(attrs, slog.String(logger.KeyOwner, entry.owner))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String(logger.KeyError, err.Error()))
+	}
+
+	log.InfoContext(ctx, "grpc call", attrs...)
+}
+
+// server stream counting the size of the messages received
+type countingStream struct {
+	grpc.ServerStream
+	received atomic.Int64
+}
+
+func (s *countingStream) RecvMsg(m interface{}) error {
+
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if msg, ok := m.(proto.Message); ok {
+		s.received.Add(int64(proto.Size(msg)))
+	}
+
+	return nil
+}
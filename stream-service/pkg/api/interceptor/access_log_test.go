@@ -0,0 +1,111 @@
+package interceptor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"stream-service/pkg/auth"
+	"stream-service/pkg/logger"
+	"stream-service/pkg/pb"
+	"stream-service/pkg/requestid"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestStreamAccessLog(t *testing.T) {
+
+	var buffer bytes.Buffer
+	log := logger.New(slog.NewJSONHandler(&buffer, nil))
+
+	chunks := []proto.Message{
+		&pb.Chunk{Seq: 0, Data: []byte("0123456789")},
+		&pb.Chunk{Seq: 1, Offset: 10, Data: []byte("abcdef")},
+	}
+	var expectedSize int64
+	for _, chunk := range chunks {
+		expectedSize += int64(proto.Size(chunk))
+	}
+
+	stream := &testServerStream{
+		ctx: metadata.NewIncomingContext(requestid.ContextWithRequestID(context.Background(), "request-1"),
+			metadata.Pairs(auth.OwnerMetadataKey, "owner")),
+		messages: chunks,
+	}
+	info := &grpc.StreamServerInfo{FullMethod: "/StreamService/Upload"}
+	// the owner authenticated by the next interceptor should be logged
+	err := StreamAccessLog(log)(nil, stream, info, func(srv interface{}, stream grpc.ServerStream) error {
+		return StreamAuth(srv, stream, info, func(srv interface{}, stream grpc.ServerStream) error {
+			for {
+				if err := stream.RecvMsg(&pb.Chunk{}); err == io.EOF {
+					return status.Error(codes.DataLoss, "checksum mismatch")
+				}
+			}
+		})
+	})
+	assert.Equal(t, codes.DataLoss, status.Code(err), "error of the handler should be returned")
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &entry))
+	assert.Equal(t, "/StreamService/Upload", entry["method"])
+	assert.Equal(t, "request-1", entry["request_id"])
+	assert.Equal(t, "owner", entry["owner"])
+	assert.Equal(t, float64(expectedSize), entry["bytes_received"])
+	assert.Equal(t, codes.DataLoss.String(), entry["code"])
+	assert.Contains(t, entry, "duration")
+	assert.Contains(t, entry, "peer")
+}
+
+func TestUnaryAccessLogOwner(t *testing.T) {
+
+	testCases := map[string]struct {
+		owner         string // owner on the metadata
+		expectedCode  codes.Code
+		expectedOwner interface{} // nil if not logged
+	}{
+		"authenticated_call_should_log_owner": {
+			owner:         "owner",
+			expectedCode:  codes.OK,
+			expectedOwner: "owner",
+		},
+		"rejected_call_should_not_log_owner_of_metadata": {
+			owner:        strings.Repeat("o", maxOwnerLength+1),
+			expectedCode: codes.Unauthenticated,
+		},
+	}
+
+	for name, test := range testCases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			var buffer bytes.Buffer
+			log := logger.New(slog.NewJSONHandler(&buffer, nil))
+
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(auth.OwnerMetadataKey, test.owner))
+			info := &grpc.UnaryServerInfo{FullMethod: "/StreamService/GetFile"}
+			_, err := UnaryAccessLog(log)(ctx, &pb.GetFileRequest{Id: "file_id"}, info,
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					return UnaryAuth(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+						return &pb.FileDetails{}, nil
+					})
+				})
+			assert.Equal(t, test.expectedCode, status.Code(err))
+
+			var entry map[string]interface{}
+			require.NoError(t, json.Unmarshal(buffer.Bytes(), &entry))
+			assert.Equal(t, test.expectedCode.String(), entry["code"])
+			assert.Equal(t, test.expectedOwner, entry["owner"])
+		})
+	}
+}
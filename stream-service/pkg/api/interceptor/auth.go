@@ -0,0 +1,66 @@
+package interceptor
+
+import (
+	"context"
+	"stream-service/pkg/auth"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const maxOwnerLength = 255
+
+// To authenticate the unary calls by the owner on the metadata and add the owner to the call context
+func UnaryAuth(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+
+	owner, err := ownerFromMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	recordAccessOwner(ctx, owner)
+
+	return handler(auth.ContextWithOwner(ctx, owner), req)
+}
+
+// To authenticate the stream calls by the owner on the metadata and add the owner to the stream context
+func StreamAuth(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+
+	owner, err := ownerFromMetadata(stream.Context())
+	if err != nil {
+		return err
+	}
+	recordAccessOwner(stream.Context(), owner)
+
+	return handler(srv, &serverStream{
+		ServerStream: stream,
+		ctx:          auth.ContextWithOwner(stream.Context(), owner),
+	})
+}
+
+// To get the owner authenticated by the gateway from the incoming metadata
+func ownerFromMetadata(ctx context.Context) (string, error) {
+
+	owners := metadata.ValueFromIncomingContext(ctx, auth.OwnerMetadataKey)
+	if len(owners) != 1 || owners[0] == "" {
+		return "", status.Error(codes.Unauthenticated, "missing owner of the request")
+	}
+	if len(owners[0]) > maxOwnerLength {
+		return "", status.Error(codes.Unauthenticated, "invalid owner of the request")
+	}
+
+	return owners[0], nil
+}
+
+// server stream with the context replaced
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,68 @@
+package interceptor
+
+import (
+	"context"
+	"stream-service/pkg/auth"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryAuth(t *testing.T) {
+
+	testCases := map[string]struct {
+		md            metadata.MD
+		expectedOwner string
+		expectedCode  codes.Code
+	}{
+		"no_metadata_should_return_unauthenticated": {
+			md:           nil,
+			expectedCode: codes.Unauthenticated,
+		},
+		"empty_owner_should_return_unauthenticated": {
+			md:           metadata.Pairs(auth.OwnerMetadataKey, ""),
+			expectedCode: codes.Unauthenticated,
+		},
+		"multiple_owners_should_return_unauthenticated": {
+			md:           metadata.Pairs(auth.OwnerMetadataKey, "owner_1", auth.OwnerMetadataKey, "owner_2"),
+			expectedCode: codes.Unauthenticated,
+		},
+		"too_long_owner_should_return_unauthenticated": {
+			md:           metadata.Pairs(auth.OwnerMetadataKey, strings.Repeat("a", maxOwnerLength+1)),
+			expectedCode: codes.Unauthenticated,
+		},
+		"owner_should_be_added_to_context": {
+			md:            metadata.Pairs(auth.OwnerMetadataKey, "owner"),
+			expectedOwner: "owner",
+			expectedCode:  codes.OK,
+		},
+	}
+
+	for name, test := range testCases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			ctx := context.Background()
+			if test.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, test.md)
+			}
+
+			var owner string
+			_, err := UnaryAuth(ctx, nil, &grpc.UnaryServerInfo{},
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					owner = auth.OwnerFromContext(ctx)
+					return nil, nil
+				})
+
+			assert.Equal(t, test.expectedCode, status.Code(err))
+			assert.Equal(t, test.expectedOwner, owner)
+		})
+	}
+}
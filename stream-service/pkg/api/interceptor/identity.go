@@ -0,0 +1,89 @@
+package interceptor
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// To allow only the unary calls of the clients with a certificate of the allowed identities,
+// any client with a verified certificate allowed if no identities given
+func UnaryClientIdentity(allowed []string) grpc.UnaryServerInterceptor {
+
+	identities := toSet(allowed)
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+
+		if err := checkClientIdentity(ctx, identities); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// To allow only the stream calls of the clients with a certificate of the allowed identities,
+// any client with a verified certificate allowed if no identities given
+func StreamClientIdentity(allowed []string) grpc.StreamServerInterceptor {
+
+	identities := toSet(allowed)
+
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+
+		if err := checkClientIdentity(stream.Context(), identities); err != nil {
+			return err
+		}
+
+		return handler(srv, stream)
+	}
+}
+
+// To check the verified client certificate of the connection has any of the allowed identities,
+// the identities of the certificate are the uris, dns names and common name
+func checkClientIdentity(ctx context.Context, allowed map[string]bool) error {
+
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "no peer of the request")
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return status.Error(codes.Unauthenticated, "no verified client certificate")
+	}
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	cert := tlsInfo.State.VerifiedChains[0][0]
+	for _, uri := range cert.URIs {
+		if allowed[uri.String()] {
+			return nil
+		}
+	}
+	for _, name := range cert.DNSNames {
+		if allowed[name] {
+			return nil
+		}
+	}
+	if allowed[cert.Subject.CommonName] {
+		return nil
+	}
+
+	return status.Errorf(codes.PermissionDenied, "client %q not allowed", cert.Subject.CommonName)
+}
+
+func toSet(values []string) map[string]bool {
+
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+
+	return set
+}
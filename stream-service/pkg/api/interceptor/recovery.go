@@ -0,0 +1,50 @@
+package interceptor
+
+import (
+	"context"
+	"log/slog"
+	"runtime/debug"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// To recover the panic of the unary calls as an internal error, so a call can't crash the service
+func UnaryRecovery(log *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (res interface{}, err error) {
+
+		defer func() {
+			if r := recover(); r != nil {
+				err = recoveredError(ctx, log, info.FullMethod, r)
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// To recover the panic of the stream calls as an internal error, so a call can't crash the service
+func StreamRecovery(log *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) (err error) {
+
+		defer func() {
+			if r := recover(); r != nil {
+				err = recoveredError(stream.Context(), log, info.FullMethod, r)
+			}
+		}()
+
+		return handler(srv, stream)
+	}
+}
+
+// To log the recovered panic with the stack and return the error of the call, the panic not exposed to client
+func recoveredError(ctx context.Context, log *slog.Logger, method string, r interface{}) error {
+
+	log.ErrorContext(ctx, "recovered panic", "method", method, "panic", r,
+		"stack", string(debug.Stack()))
+
+	return status.Error(codes.Internal, "internal error")
+}
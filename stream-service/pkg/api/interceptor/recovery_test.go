@@ -0,0 +1,90 @@
+package interceptor
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// logger to discard the logs of the tests
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// server stream of the tests with the context and the messages to receive
+type testServerStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	messages []proto.Message
+}
+
+func (s *testServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *testServerStream) RecvMsg(m interface{}) error {
+
+	if len(s.messages) == 0 {
+		return io.EOF
+	}
+	msg := m.(proto.Message)
+	proto.Reset(msg)
+	proto.Merge(msg, s.messages[0])
+	s.messages = s.messages[1:]
+
+	return nil
+}
+
+func TestRecovery(t *testing.T) {
+
+	testCases := map[string]struct {
+		handlerErr   error
+		panicValue   interface{}
+		expectedCode codes.Code
+	}{
+		"panic_should_return_internal_error": {
+			panicValue:   "nil pointer",
+			expectedCode: codes.Internal,
+		},
+		"error_should_be_returned_as_it_is": {
+			handlerErr:   status.Error(codes.NotFound, "not found"),
+			expectedCode: codes.NotFound,
+		},
+		"no_panic_should_return_ok": {
+			expectedCode: codes.OK,
+		},
+	}
+
+	for name, test := range testCases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			call := func() error {
+				if test.panicValue != nil {
+					panic(test.panicValue)
+				}
+				return test.handlerErr
+			}
+
+			_, err := UnaryRecovery(discardLogger)(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/unary"},
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					return nil, call()
+				})
+			assert.Equal(t, test.expectedCode, status.Code(err), "unary call")
+
+			err = StreamRecovery(discardLogger)(nil, &testServerStream{ctx: context.Background()},
+				&grpc.StreamServerInfo{FullMethod: "/stream"},
+				func(srv interface{}, stream grpc.ServerStream) error {
+					return call()
+				})
+			assert.Equal(t, test.expectedCode, status.Code(err), "stream call")
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package interceptor
+
+import (
+	"context"
+	"stream-service/pkg/requestid"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// To add the request id of the metadata to the context of the unary calls, a new id generated if not valid
+func UnaryRequestID(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+
+	return handler(withRequestID(ctx), req)
+}
+
+// To add the request id of the metadata to the context of the stream calls, a new id generated if not valid
+func StreamRequestID(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+
+	return handler(srv, &serverStream{
+		ServerStream: stream,
+		ctx:          withRequestID(stream.Context()),
+	})
+}
+
+// To add the request id to the context and the response header, so the client can correlate the logs
+func withRequestID(ctx context.Context) context.Context {
+
+	id := requestid.New()
+	if ids := metadata.ValueFromIncomingContext(ctx, requestid.MetadataKey); len(ids) == 1 && requestid.Valid(ids[0]) {
+		id = ids[0]
+	}
+	// the header not sent only if the call already completed
+	grpc.SetHeader(ctx, metadata.Pairs(requestid.MetadataKey, id))
+
+	return requestid.ContextWithRequestID(ctx, id)
+}
@@ -0,0 +1,65 @@
+package interceptor
+
+import (
+	"context"
+	"stream-service/pkg/requestid"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestRequestID(t *testing.T) {
+
+	testCases := map[string]struct {
+		md         metadata.MD
+		expectedID string // empty if expecting a generated id
+	}{
+		"request_id_of_metadata_should_be_used": {
+			md:         metadata.Pairs(requestid.MetadataKey, "request-1"),
+			expectedID: "request-1",
+		},
+		"no_request_id_should_generate_id": {
+			md: metadata.MD{},
+		},
+		"too_long_request_id_should_generate_id": {
+			md: metadata.Pairs(requestid.MetadataKey, strings.Repeat("a", 129)),
+		},
+		"request_id_with_spaces_should_generate_id": {
+			md: metadata.Pairs(requestid.MetadataKey, "request 1"),
+		},
+	}
+
+	for name, test := range testCases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			ctx := metadata.NewIncomingContext(context.Background(), test.md)
+
+			var unaryID, streamID string
+			UnaryRequestID(ctx, nil, &grpc.UnaryServerInfo{},
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					unaryID = requestid.FromContext(ctx)
+					return nil, nil
+				})
+			StreamRequestID(nil, &testServerStream{ctx: ctx}, &grpc.StreamServerInfo{},
+				func(srv interface{}, stream grpc.ServerStream) error {
+					streamID = requestid.FromContext(stream.Context())
+					return nil
+				})
+
+			if test.expectedID != "" {
+				assert.Equal(t, test.expectedID, unaryID)
+				assert.Equal(t, test.expectedID, streamID)
+				return
+			}
+			assert.True(t, requestid.Valid(unaryID), "should generate a valid id")
+			assert.True(t, requestid.Valid(streamID), "should generate a valid id")
+			assert.NotEqual(t, unaryID, streamID, "should generate a new id for each call")
+		})
+	}
+}
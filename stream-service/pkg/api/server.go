@@ -1,22 +1,42 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net"
+	"net/http"
+	"stream-service/pkg/api/interceptor"
 	"stream-service/pkg/config"
+	"stream-service/pkg/job"
+	"stream-service/pkg/logger"
+	"stream-service/pkg/metrics"
 	"stream-service/pkg/pb"
+	"time"
 
 	"google.golang.org/grpc"
 )
 
 type Server struct {
-	lis  net.Listener
-	gsr  *grpc.Server
-	port string
+	lis        net.Listener
+	gsr        *grpc.Server
+	port       string
+	metricsLis net.Listener
+	metricsSrv *http.Server
+	purger     *job.Purger
+	recovery   *job.Recovery
+	reconciler *job.Reconciler
+	logger     *slog.Logger
 }
 
-func NewServerGRPC(cfg config.Config, srv pb.StreamServiceServer) (*Server, error) {
+func NewServerGRPC(cfg config.Config, srv pb.StreamServiceServer, purger *job.Purger, recovery *job.Recovery,
+	reconciler *job.Reconciler, logger *slog.Logger, metrics *metrics.Metrics) (*Server, error) {
+
+	opts, err := newServerOptions(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
 
 	addr := fmt.Sprintf("%s:%s", cfg.StreamServiceHost, cfg.StreamServicePort)
 
@@ -25,18 +45,83 @@ func NewServerGRPC(cfg config.Config, srv pb.StreamServiceServer) (*Server, erro
 		return nil, fmt.Errorf("failed to listen: %v", err)
 	}
 
-	gsr := grpc.NewServer()
+	// the metrics served on a separate listener, so not exposed with the grpc port
+	metricsLis, err := net.Listen("tcp", fmt.Sprintf("%s:%s", cfg.StreamServiceHost, cfg.MetricsPort))
+	if err != nil {
+		lis.Close()
+		return nil, fmt.Errorf("failed to listen for metrics: %v", err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+
+	gsr := grpc.NewServer(opts...)
 
 	pb.RegisterStreamServiceServer(gsr, srv)
 
 	return &Server{
-		lis:  lis,
-		gsr:  gsr,
-		port: cfg.StreamServicePort,
+		lis:        lis,
+		gsr:        gsr,
+		port:       cfg.StreamServicePort,
+		metricsLis: metricsLis,
+		metricsSrv: &http.Server{Handler: mux, ReadHeaderTimeout: time.Second * 10},
+		purger:     purger,
+		recovery:   recovery,
+		reconciler: reconciler,
+		logger:     logger,
 	}, err
 }
 
+// To create the options of the server with the credentials and the interceptors of the config
+func newServerOptions(cfg config.Config, logger *slog.Logger) ([]grpc.ServerOption, error) {
+
+	// interceptors run in order, the request id first to be on the access log, the access log before the auth
+	// to log the rejected calls too (the owner recorded once authenticated),
+	// and the panics recovered inside the access log to be logged as internal errors
+	unary := []grpc.UnaryServerInterceptor{
+		interceptor.UnaryRequestID,
+		interceptor.UnaryAccessLog(logger),
+		interceptor.UnaryRecovery(logger),
+	}
+	stream := []grpc.StreamServerInterceptor{
+		interceptor.StreamRequestID,
+		interceptor.StreamAccessLog(logger),
+		interceptor.StreamRecovery(logger),
+	}
+
+	// the owner on the metadata can be set by any client, so never trusted without a verified client certificate
+	if cfg.TLSClientCAFile == "" {
+		return nil, errors.New("client ca required to verify the clients forwarding the owner")
+	}
+	creds, err := newServerCredentials(cfg, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tls certificates: %w", err)
+	}
+
+	// only the clients of the allowed identities trusted to forward the owner,
+	// every call authenticated by the owner forwarded by the gateway
+	unary = append(unary, interceptor.UnaryClientIdentity(cfg.TLSAllowedClients), interceptor.UnaryAuth)
+	stream = append(stream, interceptor.StreamClientIdentity(cfg.TLSAllowedClients), interceptor.StreamAuth)
+
+	return []grpc.ServerOption{
+		grpc.Creds(creds), grpc.ChainUnaryInterceptor(unary...), grpc.ChainStreamInterceptor(stream...),
+	}, nil
+}
+
 func (c *Server) Start() error {
-	log.Println("Stream service listening on port: ", c.port)
+
+	// recover the interrupted uploads before accepting the new uploads
+	c.recovery.Run(context.Background())
+
+	// run the background jobs
+	go c.purger.Start(context.Background())
+	go c.reconciler.Start(context.Background())
+
+	go func() {
+		if err := c.metricsSrv.Serve(c.metricsLis); err != nil {
+			c.logger.Error("failed to serve metrics", logger.KeyError, err)
+		}
+	}()
+
+	c.logger.Info("stream service listening", "port", c.port)
 	return c.gsr.Serve(c.lis)
 }
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"stream-service/pkg/pb"
+	"sync"
+)
+
+var (
+	errChunkMissing  = errors.New("expected file chunk on stream")
+	errChunkSequence = errors.New("chunk sequence not matching")
+	errChunkOffset   = errors.New("chunk offset not matching")
+	errChunkSize     = errors.New("chunk exceeds the declared file size")
+)
+
+// To validate the framing of the chunks received on an upload stream.
+// chunks should arrive in sequence without any gap or duplicate and within the declared size
+type chunkFramer struct {
+	seq    int64  // expected sequence of the next chunk
+	offset int64  // expected offset of the next chunk
+	size   *int64 // declared total size of the file (nil if not declared)
+}
+
+func newChunkFramer(offset int64, size *int64) *chunkFramer {
+	return &chunkFramer{
+		offset: offset,
+		size:   size,
+	}
+}
+
+// To validate the chunk with the expected position and return the chunk data
+func (f *chunkFramer) next(chunk *pb.Chunk) ([]byte, error) {
+
+	if chunk == nil {
+		return nil, errChunkMissing
+	}
+	if chunk.GetSeq() != f.seq {
+		return nil, fmt.Errorf("%w: expected %d but got %d", errChunkSequence, f.seq, chunk.GetSeq())
+	}
+	if chunk.GetOffset() != f.offset {
+		return nil, fmt.Errorf("%w: expected %d but got %d", errChunkOffset, f.offset, chunk.GetOffset())
+	}
+
+	data := chunk.GetData()
+	if f.size != nil && f.offset+int64(len(data)) > *f.size {
+		return nil, fmt.Errorf("%w: %d bytes", errChunkSize, *f.size)
+	}
+
+	f.seq++
+	f.offset += int64(len(data))
+
+	return data, nil
+}
+
+// reader to read the data of the validated chunks received on an upload stream.
+// the usecase reads it on a separate goroutine, which can be still blocked on receive after the upload
+// ended by idle timeout or cancel, so the error guarded to get the client error meanwhile
+type chunkReader struct {
+	recv func() ([]byte, error) // receive the data of next chunk
+	data []byte                 // data received and not read
+
+	mu  sync.Mutex
+	err error // error from stream or framing, returned on all the reads after
+}
+
+func newChunkReader(recv func() ([]byte, error)) *chunkReader {
+	return &chunkReader{
+		recv: recv,
+	}
+}
+
+func (r *chunkReader) Read(data []byte) (int, error) {
+
+	// receive until a chunk with data, the client can send empty chunks
+	for len(r.data) == 0 {
+		if err := r.streamErr(); err != nil {
+			return 0, err
+		}
+		data, err := r.recv()
+		r.mu.Lock()
+		r.data, r.err = data, err
+		r.mu.Unlock()
+	}
+
+	n := copy(data, r.data)
+	r.data = r.data[n:]
+
+	return n, nil
+}
+
+func (r *chunkReader) streamErr() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// To get the error caused by the client, nil if the stream not ended or completed with EOF
+func (r *chunkReader) clientErr() error {
+	if err := r.streamErr(); err != io.EOF {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"stream-service/pkg/metrics"
+	"stream-service/pkg/pb"
+	"stream-service/pkg/usecase"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// To observe the data of the chunk received on an upload stream
+func (s *StreamService) observeChunk(chunk *pb.Chunk) {
+
+	size := float64(len(chunk.GetData()))
+	s.metrics.BytesReceived.Add(size)
+	s.metrics.ChunkSize.Observe(size)
+}
+
+// To observe the upload stream ended with the error, the failure classified by the cause if known,
+// otherwise by the status code of the error
+func (s *StreamService) observeUpload(start time.Time, err, cause error) {
+
+	s.metrics.ActiveStreams.Dec()
+	s.metrics.UploadDuration.Observe(time.Since(start).Seconds())
+
+	if err == nil {
+		return
+	}
+	if cause == nil {
+		cause = err
+	}
+	s.metrics.UploadFailures.WithLabelValues(status.Code(err).String(), uploadFailureReason(cause)).Inc()
+}
+
+// To get the cause of the failed upload, the error caused by client takes precedence
+func uploadFailureCause(reader *chunkReader, err error) error {
+	if clientErr := reader.clientErr(); clientErr != nil {
+		return clientErr
+	}
+	return err
+}
+
+// To get the reason of the upload failed by the error
+func uploadFailureReason(err error) string {
+
+	switch {
+	case errors.Is(err, usecase.ErrUploadTimeout),
+		errors.Is(err, context.DeadlineExceeded),
+		status.Code(err) == codes.DeadlineExceeded:
+		return metrics.ReasonTimeout
+	case errors.Is(err, usecase.ErrStorageWrite):
+		return metrics.ReasonWriteError
+	case errors.Is(err, context.Canceled),
+		status.Code(err) == codes.Canceled:
+		return metrics.ReasonClientAbort
+	default:
+		return metrics.ReasonOther
+	}
+}
@@ -2,12 +2,19 @@ package service
 
 import (
 	"context"
+	"errors"
 	"io"
-	"log"
+	"log/slog"
+	"stream-service/pkg/logger"
+	"stream-service/pkg/metrics"
 	"stream-service/pkg/models/request"
+	"stream-service/pkg/models/response"
 	"stream-service/pkg/pb"
+	"stream-service/pkg/usecase"
 	"stream-service/pkg/usecase/interfaces"
+	"time"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -15,20 +22,93 @@ import (
 type StreamService struct {
 	pb.UnimplementedStreamServiceServer
 	usecase interfaces.StreamUseCase
+	logger  *slog.Logger
+	metrics *metrics.Metrics
 }
 
-func NewStreamService(usecase interfaces.StreamUseCase) pb.StreamServiceServer {
+// size of each data chunk sending on download stream
+var downloadChunkSize = 1024 * 32
+
+func NewStreamService(usecase interfaces.StreamUseCase, logger *slog.Logger,
+	metrics *metrics.Metrics) pb.StreamServiceServer {
 	return &StreamService{
 		usecase: usecase,
+		logger:  logger,
+		metrics: metrics,
+	}
+}
+
+func (s *StreamService) Upload(stream pb.StreamService_UploadServer) (err error) {
+
+	// the stream observed when ended, the failure classified by the cause if set
+	start := time.Now()
+	s.metrics.ActiveStreams.Inc()
+	var cause error
+	defer func() { s.observeUpload(start, err, cause) }()
+
+	// first take the file detail from the stream
+	streamFile, err := stream.Recv()
+	if err != nil {
+		s.logger.WarnContext(stream.Context(), "failed to get file detail from stream", logger.KeyError, err)
+		return status.Errorf(codes.InvalidArgument, "failed to receive file detail from stream: %v", err)
+	}
+
+	// the stream carries a part of a multipart upload instead of a file
+	if partInfo := streamFile.GetPart(); partInfo != nil {
+		return s.uploadMultipartPart(stream, partInfo, &cause)
+	}
+
+	fileInfo := streamFile.GetInfo()
+	if fileInfo == nil {
+		return status.Errorf(codes.InvalidArgument, "provide file info on stream initially")
+	}
+
+	fileDetails := request.FileDetails{
+		Name:        fileInfo.GetName(),
+		ContentType: fileInfo.GetContentType(),
+		Checksum:    toRequestChecksum(fileInfo),
+	}
+
+	// the stream context cancelled when the client disconnected or the deadline exceeded
+	ctx := stream.Context()
+
+	// first upload the file details
+	fileID, err := s.usecase.UploadFileDetails(ctx, fileDetails)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
 	}
+
+	// read the validated data from stream chunks and upload it as the file
+	framer := newChunkFramer(0, fileDetails.Checksum.Size)
+	reader := newChunkReader(func() ([]byte, error) {
+		streamFile, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		s.observeChunk(streamFile.GetChunk())
+		return framer.next(streamFile.GetChunk())
+	})
+	err = s.usecase.UploadFileAsStream(ctx, fileID, fileDetails.Checksum, reader, nil)
+	if err != nil {
+		cause = uploadFailureCause(reader, err)
+		return s.getUploadStatusError(ctx, fileID, reader, err)
+	}
+
+	return s.sendUploadResponse(ctx, fileID, stream.SendAndClose)
 }
 
-func (s *StreamService) Upload(stream pb.StreamService_UploadServer) error {
+func (s *StreamService) UploadWithProgress(stream pb.StreamService_UploadWithProgressServer) (err error) {
+
+	// the stream observed when ended, the failure classified by the cause if set
+	start := time.Now()
+	s.metrics.ActiveStreams.Inc()
+	var cause error
+	defer func() { s.observeUpload(start, err, cause) }()
 
 	// first take the file detail from the stream
 	streamFile, err := stream.Recv()
 	if err != nil {
-		log.Println("failed to get file detail from stream")
+		s.logger.WarnContext(stream.Context(), "failed to get file detail from stream", logger.KeyError, err)
 		return status.Errorf(codes.InvalidArgument, "failed to receive file detail from stream: %v", err)
 	}
 
@@ -40,11 +120,11 @@ func (s *StreamService) Upload(stream pb.StreamService_UploadServer) error {
 	fileDetails := request.FileDetails{
 		Name:        fileInfo.GetName(),
 		ContentType: fileInfo.GetContentType(),
+		Checksum:    toRequestChecksum(fileInfo),
 	}
 
-	// create a context with cancel to send signal of closing to usecase
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	// the stream context cancelled when the client disconnected or the deadline exceeded
+	ctx := stream.Context()
 
 	// first upload the file details
 	fileID, err := s.usecase.UploadFileDetails(ctx, fileDetails)
@@ -52,38 +132,458 @@ func (s *StreamService) Upload(stream pb.StreamService_UploadServer) error {
 		return status.Error(codes.Internal, err.Error())
 	}
 
-	// run the usecase concurrently to read and upload file
-	dataChan, errChan := make(chan []byte), make(chan error)
+	// read the validated data from stream chunks and upload it as the file,
+	// the progress sent to client on each ack from usecase
+	framer := newChunkFramer(0, fileDetails.Checksum.Size)
+	reader := newChunkReader(func() ([]byte, error) {
+		streamFile, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		s.observeChunk(streamFile.GetChunk())
+		return framer.next(streamFile.GetChunk())
+	})
+	var sendErr error
+	err = s.usecase.UploadFileAsStream(ctx, fileID, fileDetails.Checksum, reader, func(progress response.UploadProgress) {
+		if sendErr != nil {
+			return
+		}
+		sendErr = stream.Send(&pb.UploadProgress{
+			Id:        fileID,
+			Written:   progress.Written,
+			Completed: progress.Completed,
+		})
+	})
+	if err != nil {
+		cause = uploadFailureCause(reader, err)
+		return s.getUploadStatusError(ctx, fileID, reader, err)
+	}
+	if sendErr != nil {
+		return status.Errorf(codes.Internal, "failed to send progress: %v", sendErr)
+	}
+
+	return nil
+}
+
+func (s *StreamService) Download(req *pb.DownloadRequest, stream pb.StreamService_DownloadServer) error {
+
+	ctx := stream.Context()
+
+	fileDetails, file, err := s.usecase.DownloadFile(ctx, req.GetId())
+	if err != nil {
+		return getStatusError(err)
+	}
+	defer file.Close()
 
-	go s.usecase.UploadFileAsStream(ctx, fileID, dataChan, errChan)
+	// first send the file details
+	err = stream.Send(&pb.DownloadResponse{
+		File: &pb.DownloadResponse_Info{
+			Info: toPbFileDetails(fileDetails),
+		},
+	})
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to send file details: %v", err)
+	}
 
-	// receive stream data
+	// then send the file data as chunks
+	buffer := make([]byte, downloadChunkSize)
 	for {
-		select {
-		// always check error on usecase
-		case err = <-errChan:
-			return status.Errorf(codes.Internal, "failed to store data: %v", err)
-		default:
-			// receive data from stream
-			streamFile, err = stream.Recv()
-			if err != nil {
-				if err == io.EOF {
-
-					log.Println("stream completed")
-					// send EOF to notify stop waiting for data and send file id
-					errChan <- io.EOF
-					res := pb.UploadResponse{
-						Id: fileID,
-					}
-					return stream.SendAndClose(&res)
-				}
-				// if error not EOF then return from stream
-				return status.Errorf(codes.InvalidArgument, "failed to get stream file from client: %v", err)
+		n, err := file.Read(buffer)
+		if n > 0 {
+			sendErr := stream.Send(&pb.DownloadResponse{
+				File: &pb.DownloadResponse_Data{Data: buffer[:n]},
+			})
+			if sendErr != nil {
+				return status.Errorf(codes.Internal, "failed to send stream data: %v", sendErr)
+			}
+		}
+		if err != nil {
+			if err == io.EOF {
+				s.logger.DebugContext(ctx, "download stream completed", logger.KeyFileID, req.GetId())
+				return nil
+			}
+			return status.Errorf(codes.Internal, "failed to read data from file: %v", err)
+		}
+	}
+}
+
+func (s *StreamService) GetFile(ctx context.Context, req *pb.GetFileRequest) (*pb.FileDetails, error) {
+
+	fileDetails, err := s.usecase.GetFileDetails(ctx, req.GetId())
+	if err != nil {
+		return nil, getStatusError(err)
+	}
+
+	return toPbFileDetails(fileDetails), nil
+}
+
+func (s *StreamService) ListFiles(ctx context.Context, req *pb.ListFilesRequest) (*pb.ListFilesResponse, error) {
+
+	listReq := request.ListFiles{
+		Cursor:      req.GetCursor(),
+		Limit:       int(req.GetLimit()),
+		ContentType: req.GetContentType(),
+		NamePrefix:  req.GetNamePrefix(),
+		Descending:  req.GetDescending(),
+		Deleted:     req.GetDeleted(),
+	}
+
+	switch req.GetSortBy() {
+	case pb.SortField_SORT_UPLOADED_AT:
+		listReq.SortBy = request.SortByUploadedAt
+	case pb.SortField_SORT_NAME:
+		listReq.SortBy = request.SortByName
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid sort field: %v", req.GetSortBy())
+	}
+
+	if req.GetUploadedAfter() > 0 {
+		listReq.UploadedAfter = time.Unix(req.GetUploadedAfter(), 0)
+	}
+	if req.GetUploadedBefore() > 0 {
+		listReq.UploadedBefore = time.Unix(req.GetUploadedBefore(), 0)
+	}
+
+	fileList, err := s.usecase.ListFiles(ctx, listReq)
+	if err != nil {
+		return nil, getStatusError(err)
+	}
+
+	res := &pb.ListFilesResponse{
+		Files:      make([]*pb.FileDetails, len(fileList.Files)),
+		NextCursor: fileList.NextCursor,
+	}
+	for i := range fileList.Files {
+		res.Files[i] = toPbFileDetails(fileList.Files[i])
+	}
+
+	return res, nil
+}
+
+func (s *StreamService) DeleteFile(ctx context.Context, req *pb.DeleteFileRequest) (*pb.DeleteFileResponse, error) {
+
+	purgeAt, err := s.usecase.DeleteFile(ctx, req.GetId())
+	if err != nil {
+		return nil, getStatusError(err)
+	}
+
+	return &pb.DeleteFileResponse{
+		PurgeAt: purgeAt.Unix(),
+	}, nil
+}
+
+func (s *StreamService) RestoreFile(ctx context.Context, req *pb.RestoreFileRequest) (*pb.FileDetails, error) {
+
+	fileDetails, err := s.usecase.RestoreFile(ctx, req.GetId())
+	if err != nil {
+		return nil, getStatusError(err)
+	}
+
+	return toPbFileDetails(fileDetails), nil
+}
+
+func (s *StreamService) CreateUploadSession(ctx context.Context, req *pb.FileMetaData) (*pb.UploadSession, error) {
+
+	fileDetails := request.FileDetails{
+		Name:        req.GetName(),
+		ContentType: req.GetContentType(),
+		Checksum:    toRequestChecksum(req),
+	}
+
+	sessionID, err := s.usecase.CreateUploadSession(ctx, fileDetails)
+	if err != nil {
+		return nil, getStatusError(err)
+	}
+
+	return &pb.UploadSession{
+		Id: sessionID,
+	}, nil
+}
+
+func (s *StreamService) GetUploadSession(ctx context.Context, req *pb.UploadSessionRequest) (*pb.UploadSession, error) {
+
+	session, err := s.usecase.GetUploadSession(ctx, req.GetId())
+	if err != nil {
+		return nil, getStatusError(err)
+	}
+
+	res := &pb.UploadSession{
+		Id:        session.ID,
+		Offset:    session.Offset,
+		Completed: session.Completed,
+		Size:      session.Size,
+	}
+	if !session.ExpiresAt.IsZero() {
+		res.ExpiresAt = session.ExpiresAt.Unix()
+	}
+
+	return res, nil
+}
+
+func (s *StreamService) ResumeUpload(stream pb.StreamService_ResumeUploadServer) (err error) {
+
+	// the stream observed when ended, the failure classified by the cause if set
+	start := time.Now()
+	s.metrics.ActiveStreams.Inc()
+	var cause error
+	defer func() { s.observeUpload(start, err, cause) }()
+
+	// first take the resume info from the stream
+	streamFile, err := stream.Recv()
+	if err != nil {
+		s.logger.WarnContext(stream.Context(), "failed to get resume info from stream", logger.KeyError, err)
+		return status.Errorf(codes.InvalidArgument, "failed to receive resume info from stream: %v", err)
+	}
+
+	resumeInfo := streamFile.GetInfo()
+	if resumeInfo == nil {
+		return status.Errorf(codes.InvalidArgument, "provide resume info on stream initially")
+	}
+
+	// the stream context cancelled when the client disconnected or the deadline exceeded
+	ctx := stream.Context()
+
+	// check the upload can resume from the given offset
+	session, err := s.usecase.CheckUploadSessionOffset(ctx, resumeInfo.GetSessionId(), resumeInfo.GetOffset())
+	if err != nil {
+		return getStatusError(err)
+	}
+
+	// read the validated data from stream chunks and upload it after the offset, the data exceeding the
+	// declared size rejected while streaming, so only the stream failed and the committed data kept
+	framer := newChunkFramer(resumeInfo.GetOffset(), session.Size)
+	reader := newChunkReader(func() ([]byte, error) {
+		streamFile, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		s.observeChunk(streamFile.GetChunk())
+		return framer.next(streamFile.GetChunk())
+	})
+
+	// a partial stream completes the upload only if the declared size received
+	if resumeInfo.GetPartial() {
+		var checksum *request.PartChecksum
+		if pbChecksum := resumeInfo.GetChecksum(); pbChecksum != nil {
+			checksum = &request.PartChecksum{
+				Algorithm: pbChecksum.GetAlgorithm(),
+				Digest:    pbChecksum.GetDigest(),
 			}
-			// if no error to get stream
-			// send data through data chan
-			dataChan <- streamFile.GetData()
 		}
+		session, err := s.usecase.UploadSessionPartAsStream(ctx, resumeInfo.GetSessionId(), resumeInfo.GetOffset(),
+			checksum, reader)
+		if err != nil {
+			cause = uploadFailureCause(reader, err)
+			return s.getUploadStatusError(ctx, resumeInfo.GetSessionId(), reader, err)
+		}
+		if !session.Completed {
+			return stream.SendAndClose(&pb.UploadResponse{
+				Id:     session.ID,
+				Offset: session.Offset,
+			})
+		}
+		return s.sendUploadResponse(ctx, resumeInfo.GetSessionId(), stream.SendAndClose)
+	}
+
+	err = s.usecase.UploadSessionAsStream(ctx, resumeInfo.GetSessionId(), resumeInfo.GetOffset(), reader)
+	if err != nil {
+		cause = uploadFailureCause(reader, err)
+		return s.getUploadStatusError(ctx, resumeInfo.GetSessionId(), reader, err)
+	}
+
+	return s.sendUploadResponse(ctx, resumeInfo.GetSessionId(), stream.SendAndClose)
+}
+
+func (s *StreamService) DeleteUploadSession(ctx context.Context,
+	req *pb.UploadSessionRequest) (*pb.DeleteUploadSessionResponse, error) {
+
+	if err := s.usecase.DeleteUploadSession(ctx, req.GetId()); err != nil {
+		return nil, getStatusError(err)
+	}
+
+	return &pb.DeleteUploadSessionResponse{}, nil
+}
+
+// To upload the data from stream chunks as the part of a multipart upload,
+// the cause of the failure set to observe the stream
+func (s *StreamService) uploadMultipartPart(stream pb.StreamService_UploadServer, partInfo *pb.MultipartInfo,
+	cause *error) error {
+
+	// the stream context cancelled when the client disconnected or the deadline exceeded
+	ctx := stream.Context()
+
+	framer := newChunkFramer(0, partInfo.Size)
+	reader := newChunkReader(func() ([]byte, error) {
+		streamFile, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		s.observeChunk(streamFile.GetChunk())
+		return framer.next(streamFile.GetChunk())
+	})
+	part, err := s.usecase.UploadMultipartPartAsStream(ctx, partInfo.GetUploadId(), int(partInfo.GetNumber()),
+		partInfo.Size, reader)
+	if err != nil {
+		*cause = uploadFailureCause(reader, err)
+		return s.getUploadStatusError(ctx, partInfo.GetUploadId(), reader, err)
+	}
+
+	return stream.SendAndClose(&pb.UploadResponse{
+		Id:     partInfo.GetUploadId(),
+		Size:   part.Size,
+		Sha256: part.SHA256,
+		Crc32C: part.CRC32C,
+	})
+}
+
+func (s *StreamService) CompleteMultipartUpload(ctx context.Context,
+	req *pb.CompleteMultipartUploadRequest) (*pb.UploadResponse, error) {
+
+	parts := make([]request.MultipartPart, len(req.GetParts()))
+	for i, part := range req.GetParts() {
+		parts[i] = request.MultipartPart{
+			Number: int(part.GetNumber()),
+			Size:   part.GetSize(),
+			SHA256: part.GetSha256(),
+		}
+	}
+
+	err := s.usecase.CompleteMultipartUpload(ctx, req.GetUploadId(), parts)
+	if err != nil {
+		return nil, getStatusError(err)
+	}
+
+	var res *pb.UploadResponse
+	err = s.sendUploadResponse(ctx, req.GetUploadId(), func(uploadRes *pb.UploadResponse) error {
+		res = uploadRes
+		return nil
+	})
+
+	return res, err
+}
+
+// To convert the error of an upload into grpc status error, the error caused by client takes precedence
+func (s *StreamService) getUploadStatusError(ctx context.Context, fileID string, reader *chunkReader, err error) error {
+
+	if clientErr := reader.clientErr(); clientErr != nil {
+		s.logger.WarnContext(ctx, "failed to get stream file from client", logger.KeyFileID, fileID,
+			logger.KeyError, clientErr)
+		return status.Errorf(codes.InvalidArgument, "failed to get stream file from client: %v", clientErr)
+	}
+
+	return getStatusError(err)
+}
+
+// To send the upload response with the size and checksums of the uploaded file
+func (s *StreamService) sendUploadResponse(ctx context.Context, fileID string,
+	sendAndClose func(*pb.UploadResponse) error) error {
+
+	fileDetails, err := s.usecase.GetFileDetails(ctx, fileID)
+	if err != nil {
+		return getStatusError(err)
+	}
+
+	return sendAndClose(&pb.UploadResponse{
+		Id:        fileID,
+		Size:      fileDetails.Size,
+		Sha256:    fileDetails.SHA256,
+		Crc32C:    fileDetails.CRC32C,
+		Offset:    fileDetails.Size,
+		Completed: true,
+	})
+}
+
+func toRequestChecksum(metaData *pb.FileMetaData) request.Checksum {
+
+	return request.Checksum{
+		Size:   metaData.Size,
+		SHA256: metaData.GetSha256(),
+		CRC32C: metaData.Crc32C,
+	}
+}
+
+func toPbFileDetails(fileDetails response.FileDetails) *pb.FileDetails {
+
+	res := &pb.FileDetails{
+		Id:            fileDetails.ID,
+		Owner:         fileDetails.Owner,
+		Name:          fileDetails.Name,
+		ContentType:   fileDetails.ContentType,
+		UploadedAt:    fileDetails.UploadedAt.Unix(),
+		Size:          fileDetails.Size,
+		Sha256:        fileDetails.SHA256,
+		Crc32C:        fileDetails.CRC32C,
+		Status:        fileDetails.Status,
+		FailureReason: fileDetails.FailureReason,
+	}
+	if !fileDetails.DeletedAt.IsZero() {
+		res.DeletedAt = fileDetails.DeletedAt.Unix()
+	}
+	if !fileDetails.CompletedAt.IsZero() {
+		res.CompletedAt = fileDetails.CompletedAt.Unix()
+	}
 
+	return res
+}
+
+// reasons of the errors set on the error info detail of the status, so the clients can tell apart
+// the errors of the same code
+const (
+	errorDomain            = "stream-service"
+	reasonChecksumMismatch = "CHECKSUM_MISMATCH"
+	reasonSizeMismatch     = "SIZE_MISMATCH"
+	reasonOffsetMismatch   = "OFFSET_MISMATCH"
+	reasonStorageWrite     = "STORAGE_WRITE"
+)
+
+// To create the status error of the code with the reason as the error info detail
+func statusWithReason(code codes.Code, reason string, err error) error {
+
+	st, detailErr := status.New(code, err.Error()).WithDetails(&errdetails.ErrorInfo{
+		Reason: reason,
+		Domain: errorDomain,
+	})
+	if detailErr != nil {
+		return status.Error(code, err.Error())
+	}
+
+	return st.Err()
+}
+
+// To convert the error from usecase into grpc status error
+func getStatusError(err error) error {
+
+	switch {
+	case errors.Is(err, usecase.ErrInvalidFileID),
+		errors.Is(err, usecase.ErrInvalidUploadSessionID),
+		errors.Is(err, usecase.ErrInvalidCursor),
+		errors.Is(err, usecase.ErrInvalidSortField),
+		errors.Is(err, usecase.ErrInvalidChecksumAlgorithm),
+		errors.Is(err, usecase.ErrInvalidPartNumber),
+		errors.Is(err, usecase.ErrInvalidMultipartParts):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, usecase.ErrFileNotFound),
+		errors.Is(err, usecase.ErrUploadSessionNotFound),
+		errors.Is(err, usecase.ErrUploadSessionExpired):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, usecase.ErrUploadOffsetMismatch):
+		return statusWithReason(codes.FailedPrecondition, reasonOffsetMismatch, err)
+	case errors.Is(err, usecase.ErrUploadSessionCompleted),
+		errors.Is(err, usecase.ErrFileNotDeleted),
+		errors.Is(err, usecase.ErrMultipartPartNotFound):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, usecase.ErrChecksumMismatch):
+		return statusWithReason(codes.DataLoss, reasonChecksumMismatch, err)
+	case errors.Is(err, usecase.ErrSizeMismatch):
+		return statusWithReason(codes.DataLoss, reasonSizeMismatch, err)
+	case errors.Is(err, usecase.ErrUploadTimeout),
+		errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, usecase.ErrStorageWrite):
+		return statusWithReason(codes.Internal, reasonStorageWrite, err)
+	default:
+		return status.Error(codes.Internal, err.Error())
 	}
 }
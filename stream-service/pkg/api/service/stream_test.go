@@ -1,25 +1,41 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"stream-service/pkg/metrics"
 	"stream-service/pkg/mock/mock_service"
 	"stream-service/pkg/mock/mock_usecase"
 	"stream-service/pkg/models/request"
+	"stream-service/pkg/models/response"
 	"stream-service/pkg/pb"
+	"stream-service/pkg/usecase"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+// logger to discard the logs of the tests
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
 func TestUpload(t *testing.T) {
 
 	testCases := map[string]struct {
 		buildStub func(mockStream *mock_service.MockStreamService_UploadServer,
 			mockUsecase *mock_usecase.MockStreamUseCase)
 		expectedStatusCode codes.Code
+		expectedReason     string  // reason of the failure observed, other if not set
+		expectedReceived   float64 // bytes of the chunks observed
 	}{
 		"error_on_receive_stream_should_return_invalid_argument_code": {
 
@@ -62,6 +78,269 @@ func TestUpload(t *testing.T) {
 			},
 			expectedStatusCode: codes.Internal,
 		},
+		"duplicate_chunk_should_return_invalid_argument_code": {
+			buildStub: func(mockStream *mock_service.MockStreamService_UploadServer,
+				mockUsecase *mock_usecase.MockStreamUseCase) {
+
+				chunk := &pb.UploadRequest{
+					File: &pb.UploadRequest_Chunk{Chunk: &pb.Chunk{Seq: 0, Offset: 0, Data: []byte("data")}},
+				}
+				// send file details and then the same chunk twice
+				gomock.InOrder(
+					mockStream.EXPECT().Recv().Times(1).
+						Return(&pb.UploadRequest{
+							File: &pb.UploadRequest_Info{
+								Info: &pb.FileMetaData{Name: "fileName", ContentType: "content-type"},
+							},
+						}, nil),
+					mockStream.EXPECT().Recv().Times(2).Return(chunk, nil),
+				)
+
+				mockUsecase.EXPECT().UploadFileDetails(gomock.Any(), gomock.Any()).Times(1).
+					Return("file_id", nil)
+
+				// usecase read the first chunk data and return the error of duplicate chunk
+				mockUsecase.EXPECT().UploadFileAsStream(gomock.Any(), "file_id",
+					gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(ctx context.Context, id string, expected request.Checksum, stream io.Reader,
+						progress func(response.UploadProgress)) error {
+						_, err := io.ReadAll(stream)
+						return err
+					})
+			},
+			expectedStatusCode: codes.InvalidArgument,
+			expectedReceived:   8,
+		},
+		"chunk_exceeds_declared_size_should_return_invalid_argument_code": {
+			buildStub: func(mockStream *mock_service.MockStreamService_UploadServer,
+				mockUsecase *mock_usecase.MockStreamUseCase) {
+
+				size := int64(2)
+				gomock.InOrder(
+					mockStream.EXPECT().Recv().Times(1).
+						Return(&pb.UploadRequest{
+							File: &pb.UploadRequest_Info{
+								Info: &pb.FileMetaData{Name: "fileName", ContentType: "content-type", Size: &size},
+							},
+						}, nil),
+					mockStream.EXPECT().Recv().Times(1).
+						Return(&pb.UploadRequest{
+							File: &pb.UploadRequest_Chunk{Chunk: &pb.Chunk{Seq: 0, Offset: 0, Data: []byte("data")}},
+						}, nil),
+				)
+
+				mockUsecase.EXPECT().UploadFileDetails(gomock.Any(), gomock.Any()).Times(1).
+					Return("file_id", nil)
+
+				mockUsecase.EXPECT().UploadFileAsStream(gomock.Any(), "file_id",
+					request.Checksum{Size: &size}, gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(ctx context.Context, id string, expected request.Checksum, stream io.Reader,
+						progress func(response.UploadProgress)) error {
+						_, err := io.ReadAll(stream)
+						return err
+					})
+			},
+			expectedStatusCode: codes.InvalidArgument,
+			expectedReceived:   4,
+		},
+		"checksum_mismatch_should_return_data_loss_code": {
+			buildStub: func(mockStream *mock_service.MockStreamService_UploadServer,
+				mockUsecase *mock_usecase.MockStreamUseCase) {
+
+				sha256 := "expected_sha256"
+				// send file details with expected checksum and then EOF
+				gomock.InOrder(
+					mockStream.EXPECT().Recv().Times(1).
+						Return(&pb.UploadRequest{
+							File: &pb.UploadRequest_Info{
+								Info: &pb.FileMetaData{Name: "fileName", ContentType: "content-type", Sha256: sha256},
+							},
+						}, nil),
+					mockStream.EXPECT().Recv().Times(1).Return(nil, io.EOF),
+				)
+
+				mockUsecase.EXPECT().UploadFileDetails(gomock.Any(), gomock.Any()).Times(1).
+					Return("file_id", nil)
+
+				// usecase read until EOF then return the checksum mismatch as result
+				mockUsecase.EXPECT().UploadFileAsStream(gomock.Any(), "file_id",
+					request.Checksum{SHA256: sha256}, gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(ctx context.Context, id string, expected request.Checksum, stream io.Reader,
+						progress func(response.UploadProgress)) error {
+						io.ReadAll(stream)
+						return usecase.ErrChecksumMismatch
+					})
+			},
+			expectedStatusCode: codes.DataLoss,
+		},
+		"idle_timeout_should_return_deadline_exceeded_code": {
+			buildStub: func(mockStream *mock_service.MockStreamService_UploadServer,
+				mockUsecase *mock_usecase.MockStreamUseCase) {
+
+				mockStream.EXPECT().Recv().Times(1).
+					Return(&pb.UploadRequest{
+						File: &pb.UploadRequest_Info{
+							Info: &pb.FileMetaData{Name: "fileName", ContentType: "content-type"},
+						},
+					}, nil)
+
+				mockUsecase.EXPECT().UploadFileDetails(gomock.Any(), gomock.Any()).Times(1).
+					Return("file_id", nil)
+
+				// usecase not received any data within the idle timeout
+				mockUsecase.EXPECT().UploadFileAsStream(gomock.Any(), "file_id",
+					gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					Return(usecase.ErrUploadTimeout)
+			},
+			expectedStatusCode: codes.DeadlineExceeded,
+			expectedReason:     metrics.ReasonTimeout,
+		},
+		"cancelled_stream_should_return_canceled_code": {
+			buildStub: func(mockStream *mock_service.MockStreamService_UploadServer,
+				mockUsecase *mock_usecase.MockStreamUseCase) {
+
+				mockStream.EXPECT().Recv().Times(1).
+					Return(&pb.UploadRequest{
+						File: &pb.UploadRequest_Info{
+							Info: &pb.FileMetaData{Name: "fileName", ContentType: "content-type"},
+						},
+					}, nil)
+
+				mockUsecase.EXPECT().UploadFileDetails(gomock.Any(), gomock.Any()).Times(1).
+					Return("file_id", nil)
+
+				// stream context cancelled while the usecase waiting for data
+				mockUsecase.EXPECT().UploadFileAsStream(gomock.Any(), "file_id",
+					gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					Return(context.Canceled)
+			},
+			expectedStatusCode: codes.Canceled,
+			expectedReason:     metrics.ReasonClientAbort,
+		},
+		"storage_failure_should_return_internal_code": {
+			buildStub: func(mockStream *mock_service.MockStreamService_UploadServer,
+				mockUsecase *mock_usecase.MockStreamUseCase) {
+
+				gomock.InOrder(
+					mockStream.EXPECT().Recv().Times(1).
+						Return(&pb.UploadRequest{
+							File: &pb.UploadRequest_Info{
+								Info: &pb.FileMetaData{Name: "fileName", ContentType: "content-type"},
+							},
+						}, nil),
+					mockStream.EXPECT().Recv().Times(1).
+						Return(&pb.UploadRequest{
+							File: &pb.UploadRequest_Chunk{Chunk: &pb.Chunk{Seq: 0, Offset: 0, Data: []byte("data")}},
+						}, nil),
+				)
+
+				mockUsecase.EXPECT().UploadFileDetails(gomock.Any(), gomock.Any()).Times(1).
+					Return("file_id", nil)
+
+				// usecase read a chunk and failed to store it
+				mockUsecase.EXPECT().UploadFileAsStream(gomock.Any(), "file_id",
+					gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(ctx context.Context, id string, expected request.Checksum, stream io.Reader,
+						progress func(response.UploadProgress)) error {
+						stream.Read(make([]byte, 4))
+						return fmt.Errorf("%w: disk full", usecase.ErrStorageWrite)
+					})
+			},
+			expectedStatusCode: codes.Internal,
+			expectedReason:     metrics.ReasonWriteError,
+			expectedReceived:   4,
+		},
+		"successful_upload_should_send_response_with_checksum": {
+			buildStub: func(mockStream *mock_service.MockStreamService_UploadServer,
+				mockUsecase *mock_usecase.MockStreamUseCase) {
+
+				gomock.InOrder(
+					mockStream.EXPECT().Recv().Times(1).
+						Return(&pb.UploadRequest{
+							File: &pb.UploadRequest_Info{
+								Info: &pb.FileMetaData{Name: "fileName", ContentType: "content-type"},
+							},
+						}, nil),
+					mockStream.EXPECT().Recv().Times(1).Return(nil, io.EOF),
+				)
+
+				mockUsecase.EXPECT().UploadFileDetails(gomock.Any(), gomock.Any()).Times(1).
+					Return("file_id", nil)
+
+				mockUsecase.EXPECT().UploadFileAsStream(gomock.Any(), "file_id",
+					gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(ctx context.Context, id string, expected request.Checksum, stream io.Reader,
+						progress func(response.UploadProgress)) error {
+						_, err := io.ReadAll(stream)
+						return err
+					})
+
+				mockUsecase.EXPECT().GetFileDetails(gomock.Any(), "file_id").Times(1).
+					Return(response.FileDetails{ID: "file_id", Size: 4, SHA256: "sha256", CRC32C: 10}, nil)
+
+				mockStream.EXPECT().SendAndClose(&pb.UploadResponse{
+					Id: "file_id", Size: 4, Sha256: "sha256", Crc32C: 10, Offset: 4, Completed: true,
+				}).Times(1).Return(nil)
+			},
+			expectedStatusCode: codes.OK,
+		},
+		"successful_multipart_part_upload_should_send_response_with_part_checksum": {
+			buildStub: func(mockStream *mock_service.MockStreamService_UploadServer,
+				mockUsecase *mock_usecase.MockStreamUseCase) {
+
+				size := int64(4)
+				gomock.InOrder(
+					mockStream.EXPECT().Recv().Times(1).
+						Return(&pb.UploadRequest{
+							File: &pb.UploadRequest_Part{
+								Part: &pb.MultipartInfo{UploadId: "upload_id", Number: 2, Size: &size},
+							},
+						}, nil),
+					mockStream.EXPECT().Recv().Times(1).
+						Return(&pb.UploadRequest{
+							File: &pb.UploadRequest_Chunk{Chunk: &pb.Chunk{Seq: 0, Offset: 0, Data: []byte("data")}},
+						}, nil),
+					mockStream.EXPECT().Recv().Times(1).Return(nil, io.EOF),
+				)
+
+				// expecting the part uploaded without creating a file
+				mockUsecase.EXPECT().UploadMultipartPartAsStream(gomock.Any(), "upload_id", 2, &size, gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, uploadID string, number int, size *int64,
+						stream io.Reader) (response.MultipartPart, error) {
+						data, err := io.ReadAll(stream)
+						if err != nil || string(data) != "data" {
+							return response.MultipartPart{}, errors.New("unexpected stream data")
+						}
+						return response.MultipartPart{Number: 2, Size: 4, SHA256: "sha256", CRC32C: 10}, nil
+					})
+
+				mockStream.EXPECT().SendAndClose(&pb.UploadResponse{
+					Id: "upload_id", Size: 4, Sha256: "sha256", Crc32C: 10,
+				}).Times(1).Return(nil)
+			},
+			expectedStatusCode: codes.OK,
+			expectedReceived:   4,
+		},
+		"multipart_part_upload_with_invalid_number_should_return_invalid_argument_code": {
+			buildStub: func(mockStream *mock_service.MockStreamService_UploadServer,
+				mockUsecase *mock_usecase.MockStreamUseCase) {
+
+				gomock.InOrder(
+					mockStream.EXPECT().Recv().Times(1).
+						Return(&pb.UploadRequest{
+							File: &pb.UploadRequest_Part{
+								Part: &pb.MultipartInfo{UploadId: "upload_id", Number: 0},
+							},
+						}, nil),
+					mockStream.EXPECT().Recv().AnyTimes().Return(nil, io.EOF),
+				)
+
+				mockUsecase.EXPECT().UploadMultipartPartAsStream(gomock.Any(), "upload_id", 0, nil, gomock.Any()).
+					Times(1).Return(response.MultipartPart{}, usecase.ErrInvalidPartNumber)
+			},
+			expectedStatusCode: codes.InvalidArgument,
+		},
 	}
 
 	for name, test := range testCases {
@@ -76,7 +355,11 @@ func TestUpload(t *testing.T) {
 			uploadStreamServer := mock_service.NewMockStreamService_UploadServer(ctl)
 			mockUsecase := mock_usecase.NewMockStreamUseCase(ctl)
 
-			streamSrv := NewStreamService(mockUsecase)
+			uploadMetrics := metrics.NewMetrics()
+			streamSrv := NewStreamService(mockUsecase, discardLogger, uploadMetrics)
+
+			// the stream context used for the upload
+			uploadStreamServer.EXPECT().Context().AnyTimes().Return(context.Background())
 
 			// call build stub with upload stream server and mock usecase
 			test.buildStub(uploadStreamServer, mockUsecase)
@@ -86,7 +369,702 @@ func TestUpload(t *testing.T) {
 			// covert the error into grpc code and check
 			actualCode := status.Code(err)
 			assert.Equal(t, test.expectedStatusCode, actualCode)
+
+			assertUploadObserved(t, uploadMetrics, actualCode, test.expectedReason, test.expectedReceived)
+		})
+	}
+
+}
+
+// To check the upload stream observed as ended with the bytes received, and the failure by the code and
+// reason (other if not set)
+func assertUploadObserved(t *testing.T, uploadMetrics *metrics.Metrics, code codes.Code, reason string,
+	received float64) {
+
+	assert.Equal(t, float64(0), testutil.ToFloat64(uploadMetrics.ActiveStreams))
+	assert.Equal(t, received, testutil.ToFloat64(uploadMetrics.BytesReceived))
+	if code == codes.OK {
+		assert.Equal(t, 0, testutil.CollectAndCount(uploadMetrics.UploadFailures))
+		return
+	}
+	if reason == "" {
+		reason = metrics.ReasonOther
+	}
+	assert.Equal(t, float64(1), testutil.ToFloat64(uploadMetrics.UploadFailures.WithLabelValues(code.String(), reason)))
+}
+
+// the usecase abandons the reader blocked on receive when the idle timeout fires, the receive returned
+// meanwhile should not race with getting the client error for the status of the upload (run with -race)
+func TestUploadIdleTimeoutWhileReceiving(t *testing.T) {
+
+	ctl := gomock.NewController(t)
+	uploadStreamServer := mock_service.NewMockStreamService_UploadServer(ctl)
+	mockUsecase := mock_usecase.NewMockStreamUseCase(ctl)
+
+	release := make(chan struct{})
+	uploadStreamServer.EXPECT().Context().AnyTimes().Return(context.Background())
+	gomock.InOrder(
+		uploadStreamServer.EXPECT().Recv().Times(1).
+			Return(&pb.UploadRequest{
+				File: &pb.UploadRequest_Info{
+					Info: &pb.FileMetaData{Name: "fileName", ContentType: "content-type"},
+				},
+			}, nil),
+		// the receive blocked until the upload ending, then the client gone
+		uploadStreamServer.EXPECT().Recv().Times(1).
+			DoAndReturn(func() (*pb.UploadRequest, error) {
+				<-release
+				return nil, status.Error(codes.Canceled, "context canceled")
+			}),
+	)
+
+	mockUsecase.EXPECT().UploadFileDetails(gomock.Any(), gomock.Any()).Times(1).
+		Return("file_id", nil)
+
+	var reading sync.WaitGroup
+	mockUsecase.EXPECT().UploadFileAsStream(gomock.Any(), "file_id",
+		gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(ctx context.Context, id string, expected request.Checksum, stream io.Reader,
+			progress func(response.UploadProgress)) error {
+			// read on a separate goroutine as the idle timeout reader does, abandoned on the timeout
+			reading.Add(1)
+			go func() {
+				defer reading.Done()
+				io.ReadAll(stream)
+			}()
+			close(release)
+			return usecase.ErrUploadTimeout
+		})
+
+	streamSrv := NewStreamService(mockUsecase, discardLogger, metrics.NewMetrics())
+	err := streamSrv.Upload(uploadStreamServer)
+	reading.Wait()
+
+	// the client error can be received before or after the upload ended
+	assert.Contains(t, []codes.Code{codes.DeadlineExceeded, codes.InvalidArgument}, status.Code(err))
+}
+
+func TestCompleteMultipartUpload(t *testing.T) {
+
+	testCases := map[string]struct {
+		buildStub          func(mockUsecase *mock_usecase.MockStreamUseCase)
+		expectedResponse   *pb.UploadResponse
+		expectedStatusCode codes.Code
+	}{
+		"part_not_found_should_return_failed_precondition_code": {
+			buildStub: func(mockUsecase *mock_usecase.MockStreamUseCase) {
+				mockUsecase.EXPECT().CompleteMultipartUpload(gomock.Any(), "upload_id", gomock.Any()).Times(1).
+					Return(usecase.ErrMultipartPartNotFound)
+			},
+			expectedStatusCode: codes.FailedPrecondition,
+		},
+		"part_checksum_mismatch_should_return_data_loss_code": {
+			buildStub: func(mockUsecase *mock_usecase.MockStreamUseCase) {
+				mockUsecase.EXPECT().CompleteMultipartUpload(gomock.Any(), "upload_id", gomock.Any()).Times(1).
+					Return(usecase.ErrChecksumMismatch)
+			},
+			expectedStatusCode: codes.DataLoss,
+		},
+		"successful_complete_should_return_file_checksum": {
+			buildStub: func(mockUsecase *mock_usecase.MockStreamUseCase) {
+				mockUsecase.EXPECT().CompleteMultipartUpload(gomock.Any(), "upload_id", []request.MultipartPart{
+					{Number: 1, Size: 4, SHA256: "part1"},
+					{Number: 2, Size: 2, SHA256: "part2"},
+				}).Times(1).Return(nil)
+
+				mockUsecase.EXPECT().GetFileDetails(gomock.Any(), "upload_id").Times(1).
+					Return(response.FileDetails{ID: "upload_id", Size: 6, SHA256: "sha256", CRC32C: 10}, nil)
+			},
+			expectedResponse: &pb.UploadResponse{
+				Id: "upload_id", Size: 6, Sha256: "sha256", Crc32C: 10, Offset: 6, Completed: true,
+			},
+			expectedStatusCode: codes.OK,
+		},
+	}
+
+	for name, test := range testCases {
+
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			ctl := gomock.NewController(t)
+			mockUsecase := mock_usecase.NewMockStreamUseCase(ctl)
+			streamSrv := NewStreamService(mockUsecase, discardLogger, metrics.NewMetrics())
+
+			test.buildStub(mockUsecase)
+
+			res, err := streamSrv.CompleteMultipartUpload(context.Background(), &pb.CompleteMultipartUploadRequest{
+				UploadId: "upload_id",
+				Parts: []*pb.MultipartPart{
+					{Number: 1, Size: 4, Sha256: "part1"},
+					{Number: 2, Size: 2, Sha256: "part2"},
+				},
+			})
+			assert.Equal(t, test.expectedStatusCode, status.Code(err))
+			assert.Equal(t, test.expectedResponse, res)
+		})
+	}
+}
+
+func TestUploadWithProgress(t *testing.T) {
+
+	testCases := map[string]struct {
+		buildStub func(mockStream *mock_service.MockStreamService_UploadWithProgressServer,
+			mockUsecase *mock_usecase.MockStreamUseCase)
+		expectedStatusCode codes.Code
+		expectedReason     string  // reason of the failure observed, other if not set
+		expectedReceived   float64 // bytes of the chunks observed
+	}{
+		"error_on_receive_stream_should_return_invalid_argument_code": {
+			buildStub: func(mockStream *mock_service.MockStreamService_UploadWithProgressServer,
+				mockUsecase *mock_usecase.MockStreamUseCase) {
+				mockStream.EXPECT().Recv().Times(1).
+					Return(nil, errors.New("error_on_receive_stream"))
+			},
+			expectedStatusCode: codes.InvalidArgument,
+		},
+		"empty_file_info_should_return_invalid_argument_code": {
+			buildStub: func(mockStream *mock_service.MockStreamService_UploadWithProgressServer,
+				mockUsecase *mock_usecase.MockStreamUseCase) {
+				mockStream.EXPECT().Recv().Times(1).
+					Return(&pb.UploadRequest{}, nil)
+			},
+			expectedStatusCode: codes.InvalidArgument,
+		},
+		"successful_upload_should_send_completed_progress": {
+			buildStub: func(mockStream *mock_service.MockStreamService_UploadWithProgressServer,
+				mockUsecase *mock_usecase.MockStreamUseCase) {
+
+				// send file details, one data and then EOF
+				gomock.InOrder(
+					mockStream.EXPECT().Recv().Times(1).
+						Return(&pb.UploadRequest{
+							File: &pb.UploadRequest_Info{
+								Info: &pb.FileMetaData{Name: "fileName", ContentType: "content-type"},
+							},
+						}, nil),
+					mockStream.EXPECT().Recv().Times(1).
+						Return(&pb.UploadRequest{
+							File: &pb.UploadRequest_Chunk{Chunk: &pb.Chunk{Seq: 0, Offset: 0, Data: []byte("data")}},
+						}, nil),
+					mockStream.EXPECT().Recv().Times(1).Return(nil, io.EOF),
+				)
+
+				mockUsecase.EXPECT().UploadFileDetails(gomock.Any(), gomock.Any()).Times(1).
+					Return("file_id", nil)
+
+				// usecase read the data until EOF then report the completed progress
+				mockUsecase.EXPECT().UploadFileAsStream(gomock.Any(), "file_id",
+					gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(ctx context.Context, id string, expected request.Checksum, stream io.Reader,
+						progress func(response.UploadProgress)) error {
+						data, err := io.ReadAll(stream)
+						if err != nil {
+							return err
+						}
+						progress(response.UploadProgress{Written: int64(len(data)), Completed: true})
+						return nil
+					})
+
+				mockStream.EXPECT().Send(&pb.UploadProgress{Id: "file_id", Written: 4, Completed: true}).
+					Times(1).Return(nil)
+			},
+			expectedStatusCode: codes.OK,
+			expectedReceived:   4,
+		},
+		"idle_timeout_should_return_deadline_exceeded_code": {
+			buildStub: func(mockStream *mock_service.MockStreamService_UploadWithProgressServer,
+				mockUsecase *mock_usecase.MockStreamUseCase) {
+
+				gomock.InOrder(
+					mockStream.EXPECT().Recv().Times(1).
+						Return(&pb.UploadRequest{
+							File: &pb.UploadRequest_Info{
+								Info: &pb.FileMetaData{Name: "fileName", ContentType: "content-type"},
+							},
+						}, nil),
+					mockStream.EXPECT().Recv().Times(1).
+						Return(&pb.UploadRequest{
+							File: &pb.UploadRequest_Chunk{Chunk: &pb.Chunk{Seq: 0, Offset: 0, Data: []byte("data")}},
+						}, nil),
+				)
+
+				mockUsecase.EXPECT().UploadFileDetails(gomock.Any(), gomock.Any()).Times(1).
+					Return("file_id", nil)
+
+				// usecase read the first chunk then no more data within the idle timeout
+				mockUsecase.EXPECT().UploadFileAsStream(gomock.Any(), "file_id",
+					gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(ctx context.Context, id string, expected request.Checksum, stream io.Reader,
+						progress func(response.UploadProgress)) error {
+						if _, err := stream.Read(make([]byte, 4)); err != nil {
+							return err
+						}
+						return usecase.ErrUploadTimeout
+					})
+			},
+			expectedStatusCode: codes.DeadlineExceeded,
+			expectedReason:     metrics.ReasonTimeout,
+			expectedReceived:   4,
+		},
+	}
+
+	for name, test := range testCases {
+
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			ctl := gomock.NewController(t)
+			uploadStreamServer := mock_service.NewMockStreamService_UploadWithProgressServer(ctl)
+			mockUsecase := mock_usecase.NewMockStreamUseCase(ctl)
+
+			uploadMetrics := metrics.NewMetrics()
+			streamSrv := NewStreamService(mockUsecase, discardLogger, uploadMetrics)
+
+			uploadStreamServer.EXPECT().Context().AnyTimes().Return(context.Background())
+			test.buildStub(uploadStreamServer, mockUsecase)
+
+			err := streamSrv.UploadWithProgress(uploadStreamServer)
+			actualCode := status.Code(err)
+			assert.Equal(t, test.expectedStatusCode, actualCode)
+
+			assertUploadObserved(t, uploadMetrics, actualCode, test.expectedReason, test.expectedReceived)
+		})
+	}
+}
+
+func TestDownload(t *testing.T) {
+
+	testCases := map[string]struct {
+		input     *pb.DownloadRequest
+		buildStub func(mockStream *mock_service.MockStreamService_DownloadServer,
+			mockUsecase *mock_usecase.MockStreamUseCase)
+		expectedStatusCode codes.Code
+	}{
+		"invalid_file_id_should_return_invalid_argument_code": {
+			input: &pb.DownloadRequest{Id: "invalid_id"},
+			buildStub: func(mockStream *mock_service.MockStreamService_DownloadServer,
+				mockUsecase *mock_usecase.MockStreamUseCase) {
+
+				mockStream.EXPECT().Context().Return(context.Background())
+				mockUsecase.EXPECT().DownloadFile(gomock.Any(), "invalid_id").Times(1).
+					Return(response.FileDetails{}, nil, usecase.ErrInvalidFileID)
+			},
+			expectedStatusCode: codes.InvalidArgument,
+		},
+		"file_not_exist_should_return_not_found_code": {
+			input: &pb.DownloadRequest{Id: "file_id"},
+			buildStub: func(mockStream *mock_service.MockStreamService_DownloadServer,
+				mockUsecase *mock_usecase.MockStreamUseCase) {
+
+				mockStream.EXPECT().Context().Return(context.Background())
+				mockUsecase.EXPECT().DownloadFile(gomock.Any(), "file_id").Times(1).
+					Return(response.FileDetails{}, nil, usecase.ErrFileNotFound)
+			},
+			expectedStatusCode: codes.NotFound,
+		},
+		"error_on_usecase_should_return_internal_error_code": {
+			input: &pb.DownloadRequest{Id: "file_id"},
+			buildStub: func(mockStream *mock_service.MockStreamService_DownloadServer,
+				mockUsecase *mock_usecase.MockStreamUseCase) {
+
+				mockStream.EXPECT().Context().Return(context.Background())
+				mockUsecase.EXPECT().DownloadFile(gomock.Any(), "file_id").Times(1).
+					Return(response.FileDetails{}, nil, errors.New("db error"))
+			},
+			expectedStatusCode: codes.Internal,
+		},
+		"successful_download_should_send_file_details_and_data": {
+			input: &pb.DownloadRequest{Id: "file_id"},
+			buildStub: func(mockStream *mock_service.MockStreamService_DownloadServer,
+				mockUsecase *mock_usecase.MockStreamUseCase) {
+
+				mockStream.EXPECT().Context().Return(context.Background())
+				mockUsecase.EXPECT().DownloadFile(gomock.Any(), "file_id").Times(1).
+					Return(response.FileDetails{
+						ID:          "file_id",
+						Name:        "fileName",
+						ContentType: "content-type",
+					}, io.NopCloser(strings.NewReader("file data")), nil)
+
+				// expecting file details first and then the file data
+				gomock.InOrder(
+					mockStream.EXPECT().Send(gomock.Any()).Times(1).
+						DoAndReturn(func(res *pb.DownloadResponse) error {
+							assert.Equal(t, "fileName", res.GetInfo().GetName())
+							return nil
+						}),
+					mockStream.EXPECT().Send(gomock.Any()).Times(1).
+						DoAndReturn(func(res *pb.DownloadResponse) error {
+							assert.Equal(t, []byte("file data"), res.GetData())
+							return nil
+						}),
+				)
+			},
+			expectedStatusCode: codes.OK,
+		},
+	}
+
+	for name, test := range testCases {
+
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			ctl := gomock.NewController(t)
+			// create mock download stream server(grpc) and usecase
+			downloadStreamServer := mock_service.NewMockStreamService_DownloadServer(ctl)
+			mockUsecase := mock_usecase.NewMockStreamUseCase(ctl)
+
+			streamSrv := NewStreamService(mockUsecase, discardLogger, metrics.NewMetrics())
+
+			test.buildStub(downloadStreamServer, mockUsecase)
+
+			err := streamSrv.Download(test.input, downloadStreamServer)
+			actualCode := status.Code(err)
+			assert.Equal(t, test.expectedStatusCode, actualCode)
+		})
+	}
+}
+
+func TestResumeUpload(t *testing.T) {
+
+	testCases := map[string]struct {
+		buildStub func(mockStream *mock_service.MockStreamService_ResumeUploadServer,
+			mockUsecase *mock_usecase.MockStreamUseCase)
+		expectedStatusCode codes.Code
+		expectedReason     string  // reason of the failure observed, other if not set
+		expectedReceived   float64 // bytes of the chunks observed
+	}{
+		"error_on_receive_stream_should_return_invalid_argument_code": {
+			buildStub: func(mockStream *mock_service.MockStreamService_ResumeUploadServer,
+				mockUsecase *mock_usecase.MockStreamUseCase) {
+				mockStream.EXPECT().Recv().Times(1).
+					Return(nil, errors.New("error_on_receive_stream"))
+			},
+			expectedStatusCode: codes.InvalidArgument,
+		},
+		"empty_resume_info_should_return_invalid_argument_code": {
+			buildStub: func(mockStream *mock_service.MockStreamService_ResumeUploadServer,
+				mockUsecase *mock_usecase.MockStreamUseCase) {
+				mockStream.EXPECT().Recv().Times(1).
+					Return(&pb.ResumeUploadRequest{}, nil)
+			},
+			expectedStatusCode: codes.InvalidArgument,
+		},
+		"session_not_exist_should_return_not_found_code": {
+			buildStub: func(mockStream *mock_service.MockStreamService_ResumeUploadServer,
+				mockUsecase *mock_usecase.MockStreamUseCase) {
+				mockStream.EXPECT().Recv().Times(1).
+					Return(&pb.ResumeUploadRequest{
+						File: &pb.ResumeUploadRequest_Info{
+							Info: &pb.ResumeInfo{SessionId: "session_id", Offset: 0},
+						},
+					}, nil)
+
+				mockUsecase.EXPECT().CheckUploadSessionOffset(gomock.Any(), "session_id", int64(0)).Times(1).
+					Return(response.UploadSession{}, usecase.ErrUploadSessionNotFound)
+			},
+			expectedStatusCode: codes.NotFound,
+		},
+		"offset_mismatch_should_return_failed_precondition_code": {
+			buildStub: func(mockStream *mock_service.MockStreamService_ResumeUploadServer,
+				mockUsecase *mock_usecase.MockStreamUseCase) {
+				mockStream.EXPECT().Recv().Times(1).
+					Return(&pb.ResumeUploadRequest{
+						File: &pb.ResumeUploadRequest_Info{
+							Info: &pb.ResumeInfo{SessionId: "session_id", Offset: 100},
+						},
+					}, nil)
+
+				mockUsecase.EXPECT().CheckUploadSessionOffset(gomock.Any(), "session_id", int64(100)).Times(1).
+					Return(response.UploadSession{}, usecase.ErrUploadOffsetMismatch)
+			},
+			expectedStatusCode: codes.FailedPrecondition,
+		},
+		"invalid_chunk_offset_should_return_invalid_argument_code": {
+			buildStub: func(mockStream *mock_service.MockStreamService_ResumeUploadServer,
+				mockUsecase *mock_usecase.MockStreamUseCase) {
+
+				// chunk sent from the start of file instead of the resume offset
+				gomock.InOrder(
+					mockStream.EXPECT().Recv().Times(1).
+						Return(&pb.ResumeUploadRequest{
+							File: &pb.ResumeUploadRequest_Info{
+								Info: &pb.ResumeInfo{SessionId: "session_id", Offset: 100},
+							},
+						}, nil),
+					mockStream.EXPECT().Recv().Times(1).
+						Return(&pb.ResumeUploadRequest{
+							File: &pb.ResumeUploadRequest_Chunk{Chunk: &pb.Chunk{Seq: 0, Offset: 0, Data: []byte("data")}},
+						}, nil),
+				)
+
+				mockUsecase.EXPECT().CheckUploadSessionOffset(gomock.Any(), "session_id", int64(100)).Times(1).
+					Return(response.UploadSession{ID: "session_id", Offset: 100}, nil)
+
+				// usecase keep the data received before the error and return it
+				mockUsecase.EXPECT().UploadSessionAsStream(gomock.Any(), "session_id", int64(100), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, sessionID string, offset int64, stream io.Reader) error {
+						_, err := io.ReadAll(stream)
+						return err
+					})
+			},
+			expectedStatusCode: codes.InvalidArgument,
+			expectedReceived:   4,
+		},
+		"successful_resume_should_send_response_with_checksum": {
+			buildStub: func(mockStream *mock_service.MockStreamService_ResumeUploadServer,
+				mockUsecase *mock_usecase.MockStreamUseCase) {
+
+				gomock.InOrder(
+					mockStream.EXPECT().Recv().Times(1).
+						Return(&pb.ResumeUploadRequest{
+							File: &pb.ResumeUploadRequest_Info{
+								Info: &pb.ResumeInfo{SessionId: "session_id", Offset: 100},
+							},
+						}, nil),
+					mockStream.EXPECT().Recv().Times(1).
+						Return(&pb.ResumeUploadRequest{
+							File: &pb.ResumeUploadRequest_Chunk{Chunk: &pb.Chunk{Seq: 0, Offset: 100, Data: []byte("data")}},
+						}, nil),
+					mockStream.EXPECT().Recv().Times(1).Return(nil, io.EOF),
+				)
+
+				mockUsecase.EXPECT().CheckUploadSessionOffset(gomock.Any(), "session_id", int64(100)).Times(1).
+					Return(response.UploadSession{ID: "session_id", Offset: 100}, nil)
+
+				mockUsecase.EXPECT().UploadSessionAsStream(gomock.Any(), "session_id", int64(100), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, sessionID string, offset int64, stream io.Reader) error {
+						data, err := io.ReadAll(stream)
+						if err != nil || string(data) != "data" {
+							return errors.New("unexpected stream data")
+						}
+						return nil
+					})
+
+				mockUsecase.EXPECT().GetFileDetails(gomock.Any(), "session_id").Times(1).
+					Return(response.FileDetails{ID: "session_id", Size: 104, SHA256: "sha256", CRC32C: 10}, nil)
+
+				mockStream.EXPECT().SendAndClose(&pb.UploadResponse{
+					Id: "session_id", Size: 104, Sha256: "sha256", Crc32C: 10, Offset: 104, Completed: true,
+				}).Times(1).Return(nil)
+			},
+			expectedStatusCode: codes.OK,
+			expectedReceived:   4,
+		},
+		"partial_upload_should_send_response_with_offset": {
+			buildStub: func(mockStream *mock_service.MockStreamService_ResumeUploadServer,
+				mockUsecase *mock_usecase.MockStreamUseCase) {
+
+				gomock.InOrder(
+					mockStream.EXPECT().Recv().Times(1).
+						Return(&pb.ResumeUploadRequest{
+							File: &pb.ResumeUploadRequest_Info{
+								Info: &pb.ResumeInfo{SessionId: "session_id", Offset: 100, Partial: true,
+									Checksum: &pb.PartChecksum{Algorithm: "sha1", Digest: []byte("digest")}},
+							},
+						}, nil),
+					mockStream.EXPECT().Recv().Times(1).
+						Return(&pb.ResumeUploadRequest{
+							File: &pb.ResumeUploadRequest_Chunk{Chunk: &pb.Chunk{Seq: 0, Offset: 100, Data: []byte("data")}},
+						}, nil),
+					mockStream.EXPECT().Recv().Times(1).Return(nil, io.EOF),
+				)
+
+				mockUsecase.EXPECT().CheckUploadSessionOffset(gomock.Any(), "session_id", int64(100)).Times(1).
+					Return(response.UploadSession{ID: "session_id", Offset: 100}, nil)
+
+				mockUsecase.EXPECT().UploadSessionPartAsStream(gomock.Any(), "session_id", int64(100),
+					&request.PartChecksum{Algorithm: "sha1", Digest: []byte("digest")}, gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, sessionID string, offset int64,
+						checksum *request.PartChecksum, stream io.Reader) (response.UploadSession, error) {
+						data, err := io.ReadAll(stream)
+						if err != nil || string(data) != "data" {
+							return response.UploadSession{}, errors.New("unexpected stream data")
+						}
+						return response.UploadSession{ID: sessionID, Offset: 104}, nil
+					})
+
+				mockStream.EXPECT().SendAndClose(&pb.UploadResponse{Id: "session_id", Offset: 104}).
+					Times(1).Return(nil)
+			},
+			expectedStatusCode: codes.OK,
+			expectedReceived:   4,
+		},
+		"data_exceeding_session_size_should_return_invalid_argument_code": {
+			buildStub: func(mockStream *mock_service.MockStreamService_ResumeUploadServer,
+				mockUsecase *mock_usecase.MockStreamUseCase) {
+
+				// the second chunk exceeds the declared size of the session
+				gomock.InOrder(
+					mockStream.EXPECT().Recv().Times(1).
+						Return(&pb.ResumeUploadRequest{
+							File: &pb.ResumeUploadRequest_Info{
+								Info: &pb.ResumeInfo{SessionId: "session_id", Offset: 100, Partial: true},
+							},
+						}, nil),
+					mockStream.EXPECT().Recv().Times(1).
+						Return(&pb.ResumeUploadRequest{
+							File: &pb.ResumeUploadRequest_Chunk{Chunk: &pb.Chunk{Seq: 0, Offset: 100, Data: []byte("data")}},
+						}, nil),
+					mockStream.EXPECT().Recv().Times(1).
+						Return(&pb.ResumeUploadRequest{
+							File: &pb.ResumeUploadRequest_Chunk{Chunk: &pb.Chunk{Seq: 1, Offset: 104, Data: []byte("more")}},
+						}, nil),
+				)
+
+				size := int64(106)
+				mockUsecase.EXPECT().CheckUploadSessionOffset(gomock.Any(), "session_id", int64(100)).Times(1).
+					Return(response.UploadSession{ID: "session_id", Offset: 100, Size: &size}, nil)
+
+				// usecase keep the data within the size received before the error
+				mockUsecase.EXPECT().UploadSessionPartAsStream(gomock.Any(), "session_id", int64(100),
+					nil, gomock.Any()).Times(1).
+					DoAndReturn(func(ctx context.Context, sessionID string, offset int64,
+						checksum *request.PartChecksum, stream io.Reader) (response.UploadSession, error) {
+						data, err := io.ReadAll(stream)
+						if string(data) != "data" {
+							return response.UploadSession{}, errors.New("unexpected stream data")
+						}
+						return response.UploadSession{}, err
+					})
+			},
+			expectedStatusCode: codes.InvalidArgument,
+			expectedReceived:   8,
+		},
+		"storage_failure_should_return_internal_code": {
+			buildStub: func(mockStream *mock_service.MockStreamService_ResumeUploadServer,
+				mockUsecase *mock_usecase.MockStreamUseCase) {
+
+				gomock.InOrder(
+					mockStream.EXPECT().Recv().Times(1).
+						Return(&pb.ResumeUploadRequest{
+							File: &pb.ResumeUploadRequest_Info{
+								Info: &pb.ResumeInfo{SessionId: "session_id", Offset: 100},
+							},
+						}, nil),
+					mockStream.EXPECT().Recv().AnyTimes().Return(nil, io.EOF),
+				)
+
+				mockUsecase.EXPECT().CheckUploadSessionOffset(gomock.Any(), "session_id", int64(100)).Times(1).
+					Return(response.UploadSession{ID: "session_id", Offset: 100}, nil)
+
+				mockUsecase.EXPECT().UploadSessionAsStream(gomock.Any(), "session_id", int64(100), gomock.Any()).
+					Times(1).Return(fmt.Errorf("%w: disk full", usecase.ErrStorageWrite))
+			},
+			expectedStatusCode: codes.Internal,
+			expectedReason:     metrics.ReasonWriteError,
+		},
+		"partial_upload_with_checksum_mismatch_should_return_data_loss": {
+			buildStub: func(mockStream *mock_service.MockStreamService_ResumeUploadServer,
+				mockUsecase *mock_usecase.MockStreamUseCase) {
+
+				gomock.InOrder(
+					mockStream.EXPECT().Recv().Times(1).
+						Return(&pb.ResumeUploadRequest{
+							File: &pb.ResumeUploadRequest_Info{
+								Info: &pb.ResumeInfo{SessionId: "session_id", Offset: 100, Partial: true},
+							},
+						}, nil),
+					mockStream.EXPECT().Recv().AnyTimes().Return(nil, io.EOF),
+				)
+
+				mockUsecase.EXPECT().CheckUploadSessionOffset(gomock.Any(), "session_id", int64(100)).Times(1).
+					Return(response.UploadSession{ID: "session_id", Offset: 100}, nil)
+
+				mockUsecase.EXPECT().UploadSessionPartAsStream(gomock.Any(), "session_id", int64(100),
+					nil, gomock.Any()).Times(1).Return(response.UploadSession{}, usecase.ErrChecksumMismatch)
+			},
+			expectedStatusCode: codes.DataLoss,
+		},
+	}
+
+	for name, test := range testCases {
+
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			ctl := gomock.NewController(t)
+			resumeStreamServer := mock_service.NewMockStreamService_ResumeUploadServer(ctl)
+			mockUsecase := mock_usecase.NewMockStreamUseCase(ctl)
+
+			uploadMetrics := metrics.NewMetrics()
+			streamSrv := NewStreamService(mockUsecase, discardLogger, uploadMetrics)
+
+			resumeStreamServer.EXPECT().Context().AnyTimes().Return(context.Background())
+			test.buildStub(resumeStreamServer, mockUsecase)
+
+			err := streamSrv.ResumeUpload(resumeStreamServer)
+			actualCode := status.Code(err)
+			assert.Equal(t, test.expectedStatusCode, actualCode)
+
+			assertUploadObserved(t, uploadMetrics, actualCode, test.expectedReason, test.expectedReceived)
 		})
 	}
+}
+
+func TestGetStatusErrorReason(t *testing.T) {
+
+	testCases := map[string]struct {
+		err            error
+		expectedCode   codes.Code
+		expectedReason string
+	}{
+		"checksum_mismatch_should_return_reason": {
+			err:            fmt.Errorf("%w: sha256 not matching", usecase.ErrChecksumMismatch),
+			expectedCode:   codes.DataLoss,
+			expectedReason: reasonChecksumMismatch,
+		},
+		"size_mismatch_should_return_reason": {
+			err:            fmt.Errorf("%w: expected 10 bytes", usecase.ErrSizeMismatch),
+			expectedCode:   codes.DataLoss,
+			expectedReason: reasonSizeMismatch,
+		},
+		"offset_mismatch_should_return_reason": {
+			err:            fmt.Errorf("%w: committed offset is 10", usecase.ErrUploadOffsetMismatch),
+			expectedCode:   codes.FailedPrecondition,
+			expectedReason: reasonOffsetMismatch,
+		},
+		"storage_write_should_return_reason": {
+			err:            fmt.Errorf("%w: disk full", usecase.ErrStorageWrite),
+			expectedCode:   codes.Internal,
+			expectedReason: reasonStorageWrite,
+		},
+		"session_completed_should_not_return_reason": {
+			err:          usecase.ErrUploadSessionCompleted,
+			expectedCode: codes.FailedPrecondition,
+		},
+	}
+
+	for name, test := range testCases {
+		test := test
+		t.Run(name, func(t *testing.T) {
 
+			t.Parallel()
+
+			st := status.Convert(getStatusError(test.err))
+			assert.Equal(t, test.expectedCode, st.Code())
+			assert.Equal(t, test.err.Error(), st.Message())
+
+			var reason string
+			for _, detail := range st.Details() {
+				if info, ok := detail.(*errdetails.ErrorInfo); ok {
+					assert.Equal(t, errorDomain, info.GetDomain())
+					reason = info.GetReason()
+				}
+			}
+			assert.Equal(t, test.expectedReason, reason)
+		})
+	}
 }
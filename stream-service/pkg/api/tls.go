@@ -0,0 +1,139 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"os"
+	"stream-service/pkg/config"
+	"stream-service/pkg/logger"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// tls config of the server loaded from the certificate files, reloaded on the new connections if the files
+// modified, so the renewed certificates used without a restart
+type tlsReloader struct {
+	certFile     string
+	keyFile      string
+	clientCAFile string // empty to not require the client certificates
+	logger       *slog.Logger
+
+	mu       sync.Mutex
+	config   *tls.Config
+	modTimes []time.Time
+}
+
+// To create the server credentials with the certificates of the config
+func newServerCredentials(cfg config.Config, logger *slog.Logger) (credentials.TransportCredentials, error) {
+
+	reloader := &tlsReloader{
+		certFile:     cfg.TLSCertFile,
+		keyFile:      cfg.TLSKeyFile,
+		clientCAFile: cfg.TLSClientCAFile,
+		logger:       logger,
+	}
+	if _, err := reloader.tlsConfig(); err != nil {
+		return nil, err
+	}
+
+	return credentials.NewTLS(&tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return reloader.tlsConfig()
+		},
+	}), nil
+}
+
+// To get the tls config, reloaded if any of the files modified since loaded.
+// the loaded config kept if the reload failed, the files may be partially written on renewal
+func (r *tlsReloader) tlsConfig() (*tls.Config, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	modTimes, err := fileModTimes(r.certFile, r.keyFile, r.clientCAFile)
+	if err == nil && r.config != nil && equalTimes(modTimes, r.modTimes) {
+		return r.config, nil
+	}
+
+	config, loadErr := r.load()
+	if err == nil {
+		err = loadErr
+	}
+	if err != nil {
+		if r.config == nil {
+			return nil, err
+		}
+		r.logger.Error("failed to reload tls certificates, using the loaded certificates",
+			logger.KeyError, err)
+		return r.config, nil
+	}
+
+	r.config, r.modTimes = config, modTimes
+
+	return config, nil
+}
+
+func (r *tlsReloader) load() (*tls.Config, error) {
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load server certificate: %w", err)
+	}
+
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		NextProtos:   []string{"h2"},
+	}
+
+	if r.clientCAFile != "" {
+		data, err := os.ReadFile(r.clientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client ca file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificates found on client ca file %s", r.clientCAFile)
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return config, nil
+}
+
+// To get the modification times of the files, zero time for the empty paths
+func fileModTimes(paths ...string) ([]time.Time, error) {
+
+	modTimes := make([]time.Time, len(paths))
+	for i, path := range paths {
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		modTimes[i] = info.ModTime()
+	}
+
+	return modTimes, nil
+}
+
+func equalTimes(a, b []time.Time) bool {
+
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,288 @@
+package api
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log/slog"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"stream-service/pkg/auth"
+	"stream-service/pkg/config"
+	"stream-service/pkg/pb"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// self-signed ca to issue the certificates of the tests
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T, name string) *testCA {
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCA{
+		cert: cert,
+		key:  key,
+		pem:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+// To issue a certificate of the name for both server and client auth, returns the pem encoded certificate and key
+func (ca *testCA) issue(t *testing.T, name string) ([]byte, []byte) {
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// To write the file on the dir with the modification time, so the modification detected on rewrite
+func writeTestFile(t *testing.T, dir, name string, data []byte, modTime time.Time) string {
+
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+
+	return path
+}
+
+// server returning the owner of the request as the owner of the file
+type ownerStreamServer struct {
+	pb.UnimplementedStreamServiceServer
+}
+
+func (s *ownerStreamServer) GetFile(ctx context.Context, req *pb.GetFileRequest) (*pb.FileDetails, error) {
+	return &pb.FileDetails{Id: req.GetId(), Owner: auth.OwnerFromContext(ctx)}, nil
+}
+
+// To start the server with the options of the config, returns the address of the server
+func startTestServer(t *testing.T, cfg config.Config) string {
+
+	opts, err := newServerOptions(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer(opts...)
+	pb.RegisterStreamServiceServer(server, &ownerStreamServer{})
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String()
+}
+
+// To get the file on a new connection with the client certificate, returns the certificate of the server
+func getFileOverTLS(t *testing.T, addr string, ca *testCA, certPEM, keyPEM []byte) (*x509.Certificate, error) {
+
+	config := &tls.Config{
+		RootCAs:    x509.NewCertPool(),
+		ServerName: "stream-service",
+	}
+	config.RootCAs.AddCert(ca.cert)
+	if certPEM != nil {
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		require.NoError(t, err)
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	var serverCert *x509.Certificate
+	config.VerifyConnection = func(state tls.ConnectionState) error {
+		serverCert = state.PeerCertificates[0]
+		return nil
+	}
+
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(credentials.NewTLS(config)))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	ctx = metadata.AppendToOutgoingContext(ctx, auth.OwnerMetadataKey, "owner")
+
+	res, err := pb.NewStreamServiceClient(conn).GetFile(ctx, &pb.GetFileRequest{Id: "file"})
+	if err != nil {
+		return nil, err
+	}
+	assert.Equal(t, "owner", res.GetOwner(), "owner should be forwarded after the client verified")
+
+	return serverCert, nil
+}
+
+func TestMutualTLS(t *testing.T) {
+
+	ca := newTestCA(t, "test ca")
+	otherCA := newTestCA(t, "other ca")
+
+	dir := t.TempDir()
+	now := time.Now()
+	certPEM, keyPEM := ca.issue(t, "stream-service")
+	addr := startTestServer(t, config.Config{
+		TLSCertFile:       writeTestFile(t, dir, "cert.pem", certPEM, now),
+		TLSKeyFile:        writeTestFile(t, dir, "key.pem", keyPEM, now),
+		TLSClientCAFile:   writeTestFile(t, dir, "ca.pem", ca.pem, now),
+		TLSAllowedClients: []string{"api-gateway"},
+	})
+
+	allowedCertPEM, allowedKeyPEM := ca.issue(t, "api-gateway")
+	otherCertPEM, otherKeyPEM := ca.issue(t, "other-service")
+	otherCACertPEM, otherCAKeyPEM := otherCA.issue(t, "api-gateway")
+
+	testCases := map[string]struct {
+		certPEM, keyPEM []byte
+		expectedCode    codes.Code
+	}{
+		"allowed_client_should_get_file": {
+			certPEM:      allowedCertPEM,
+			keyPEM:       allowedKeyPEM,
+			expectedCode: codes.OK,
+		},
+		"client_not_allowed_should_return_permission_denied": {
+			certPEM:      otherCertPEM,
+			keyPEM:       otherKeyPEM,
+			expectedCode: codes.PermissionDenied,
+		},
+		"client_of_other_ca_should_fail_handshake": {
+			certPEM:      otherCACertPEM,
+			keyPEM:       otherCAKeyPEM,
+			expectedCode: codes.Unavailable,
+		},
+		"no_client_certificate_should_fail_handshake": {
+			expectedCode: codes.Unavailable,
+		},
+	}
+
+	for name, test := range testCases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			_, err := getFileOverTLS(t, addr, ca, test.certPEM, test.keyPEM)
+			assert.Equal(t, test.expectedCode, status.Code(err))
+		})
+	}
+}
+
+func TestTLSReload(t *testing.T) {
+
+	ca := newTestCA(t, "test ca")
+	dir := t.TempDir()
+	loadedAt := time.Now().Add(-time.Minute)
+
+	certPEM, keyPEM := ca.issue(t, "stream-service")
+	addr := startTestServer(t, config.Config{
+		TLSCertFile:     writeTestFile(t, dir, "cert.pem", certPEM, loadedAt),
+		TLSKeyFile:      writeTestFile(t, dir, "key.pem", keyPEM, loadedAt),
+		TLSClientCAFile: writeTestFile(t, dir, "ca.pem", ca.pem, loadedAt),
+	})
+	clientCertPEM, clientKeyPEM := ca.issue(t, "api-gateway")
+
+	initial, err := getFileOverTLS(t, addr, ca, clientCertPEM, clientKeyPEM)
+	require.NoError(t, err)
+
+	// the renewed server certificate should be used on the new connections
+	renewedAt := loadedAt.Add(time.Second)
+	certPEM, keyPEM = ca.issue(t, "stream-service")
+	writeTestFile(t, dir, "cert.pem", certPEM, renewedAt)
+	writeTestFile(t, dir, "key.pem", keyPEM, renewedAt)
+
+	renewed, err := getFileOverTLS(t, addr, ca, clientCertPEM, clientKeyPEM)
+	require.NoError(t, err)
+	assert.NotEqual(t, initial.SerialNumber, renewed.SerialNumber, "renewed certificate should be served")
+
+	// the clients of the rotated client ca should be accepted, the clients of the old ca rejected
+	rotatedCA := newTestCA(t, "rotated ca")
+	writeTestFile(t, dir, "ca.pem", rotatedCA.pem, renewedAt.Add(time.Second))
+	rotatedCertPEM, rotatedKeyPEM := rotatedCA.issue(t, "api-gateway")
+
+	_, err = getFileOverTLS(t, addr, ca, rotatedCertPEM, rotatedKeyPEM)
+	assert.NoError(t, err)
+	_, err = getFileOverTLS(t, addr, ca, clientCertPEM, clientKeyPEM)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	// the loaded certificates should be kept if the files are invalid
+	writeTestFile(t, dir, "cert.pem", []byte("partially written"), renewedAt.Add(time.Second*2))
+	served, err := getFileOverTLS(t, addr, ca, rotatedCertPEM, rotatedKeyPEM)
+	require.NoError(t, err)
+	assert.Equal(t, renewed.SerialNumber, served.SerialNumber)
+}
+
+func TestServerOptionsRequireClientCA(t *testing.T) {
+
+	ca := newTestCA(t, "test ca")
+	dir := t.TempDir()
+	certPEM, keyPEM := ca.issue(t, "stream-service")
+
+	testCases := map[string]config.Config{
+		"no_tls_should_return_error": {},
+		"tls_without_client_ca_should_return_error": {
+			TLSCertFile: writeTestFile(t, dir, "cert.pem", certPEM, time.Now()),
+			TLSKeyFile:  writeTestFile(t, dir, "key.pem", keyPEM, time.Now()),
+		},
+	}
+
+	for name, cfg := range testCases {
+		cfg := cfg
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			// the owner on the metadata should never be trusted from the clients not verified
+			_, err := newServerOptions(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package auth
+
+import "context"
+
+// key of the gRPC metadata carrying the authenticated subject of the caller
+const OwnerMetadataKey = "owner"
+
+type ownerKey struct{}
+
+// To add the owner of the request to the context
+func ContextWithOwner(ctx context.Context, owner string) context.Context {
+	return context.WithValue(ctx, ownerKey{}, owner)
+}
+
+// To get the owner of the request from the context, empty if not authenticated
+func OwnerFromContext(ctx context.Context) string {
+	owner, _ := ctx.Value(ownerKey{}).(string)
+	return owner
+}
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/go-playground/validator/v10"
 	"github.com/spf13/viper"
 )
@@ -13,20 +15,110 @@ type Config struct {
 	DBName            string `mapstructure:"DB_NAME"`
 	DBUser            string `mapstructure:"DB_USER"`
 	DBPassword        string `mapstructure:"DB_PASSWORD"`
+
+	LogLevel  string `mapstructure:"LOG_LEVEL" validate:"oneof=debug info warn error"`
+	LogFormat string `mapstructure:"LOG_FORMAT" validate:"oneof=json text"`
+
+	MetricsPort string `mapstructure:"METRICS_PORT" validate:"required"` // port of the http listener serving /metrics
+
+	// served over mutual tls, the clients required a certificate of the client ca, as the owner forwarded
+	// on the metadata trusted only from the verified clients
+	TLSCertFile     string `mapstructure:"TLS_CERT_FILE" validate:"required"`
+	TLSKeyFile      string `mapstructure:"TLS_KEY_FILE" validate:"required"`
+	TLSClientCAFile string `mapstructure:"TLS_CLIENT_CA_FILE" validate:"required"`
+	// identities (uri, dns name or common name) of the client certificates allowed, empty to allow any client of the ca
+	TLSAllowedClients []string `mapstructure:"TLS_ALLOWED_CLIENTS"`
+
+	FileRetentionPeriod time.Duration `mapstructure:"FILE_RETENTION_PERIOD"` // time to keep the deleted files on trash
+	FilePurgeInterval   time.Duration `mapstructure:"FILE_PURGE_INTERVAL" validate:"gt=0"`
+	// min age of the leftover temporary files to recover, younger files can be active uploads of other instances
+	UploadRecoveryGracePeriod time.Duration `mapstructure:"UPLOAD_RECOVERY_GRACE_PERIOD"`
+	// time to keep a not completed upload session after the last upload, zero to keep forever
+	UploadSessionExpiry time.Duration `mapstructure:"UPLOAD_SESSION_EXPIRY"`
+
+	ReconcileInterval    time.Duration `mapstructure:"RECONCILE_INTERVAL"`     // zero to disable the periodic reconciliation
+	ReconcileRepair      bool          `mapstructure:"RECONCILE_REPAIR"`       // repair the mismatches on periodic reconciliation
+	ReconcileGracePeriod time.Duration `mapstructure:"RECONCILE_GRACE_PERIOD"` // min age of the files and objects to reconcile
+
+	StorageBackend  string `mapstructure:"STORAGE_BACKEND" validate:"oneof=local s3 memory"` // memory only for development
+	StorageLocalDir string `mapstructure:"STORAGE_LOCAL_DIR"`                                // root directory of local storage
+	S3Endpoint      string `mapstructure:"S3_ENDPOINT" validate:"required_if=StorageBackend s3"`
+	S3Bucket        string `mapstructure:"S3_BUCKET" validate:"required_if=StorageBackend s3"`
+	S3AccessKey     string `mapstructure:"S3_ACCESS_KEY"`
+	S3SecretKey     string `mapstructure:"S3_SECRET_KEY"`
+	S3Region        string `mapstructure:"S3_REGION"`
+	S3UseSSL        bool   `mapstructure:"S3_USE_SSL"`
 }
 
 var envs = []string{
 	"STREAMER_SERVICE_HOST", "STREAMER_SERVICE_PORT",
 	"DB_HOST", "DB_PORT", "DB_NAME", "DB_USER", "DB_PASSWORD",
+	"LOG_LEVEL", "LOG_FORMAT", "METRICS_PORT",
+	"TLS_CERT_FILE", "TLS_KEY_FILE", "TLS_CLIENT_CA_FILE", "TLS_ALLOWED_CLIENTS",
+	"FILE_RETENTION_PERIOD", "FILE_PURGE_INTERVAL", "UPLOAD_RECOVERY_GRACE_PERIOD", "UPLOAD_SESSION_EXPIRY",
+	"RECONCILE_INTERVAL", "RECONCILE_REPAIR", "RECONCILE_GRACE_PERIOD",
+	"STORAGE_BACKEND", "STORAGE_LOCAL_DIR",
+	"S3_ENDPOINT", "S3_BUCKET", "S3_ACCESS_KEY", "S3_SECRET_KEY", "S3_REGION", "S3_USE_SSL",
+}
+
+// default values for optional envs
+var defaults = map[string]interface{}{
+	"LOG_LEVEL":                    "info",
+	"LOG_FORMAT":                   "json",
+	"METRICS_PORT":                 "9090",
+	"FILE_RETENTION_PERIOD":        time.Hour * 24 * 30,
+	"FILE_PURGE_INTERVAL":          time.Hour,
+	"UPLOAD_RECOVERY_GRACE_PERIOD": time.Minute,
+	"UPLOAD_SESSION_EXPIRY":        time.Hour * 24,
+	"RECONCILE_INTERVAL":           time.Hour * 24,
+	"RECONCILE_GRACE_PERIOD":       time.Hour,
+	"STORAGE_BACKEND":              "local",
+	"STORAGE_LOCAL_DIR":            "./uploads/",
 }
 
 func LoadConfig() (Config, error) {
+
+	config, err := readConfig()
+	if err != nil {
+		return config, err
+	}
+
+	if err := validator.New().Struct(&config); err != nil {
+		return config, err
+	}
+
+	return config, nil
+}
+
+// To load the config of the offline commands, only the database, storage and logger config validated
+// as the api listeners (tls, metrics) not used
+func LoadOfflineConfig() (Config, error) {
+
+	config, err := readConfig()
+	if err != nil {
+		return config, err
+	}
+
+	err = validator.New().StructPartial(&config, "LogLevel", "LogFormat", "StorageBackend", "S3Endpoint", "S3Bucket")
+	if err != nil {
+		return config, err
+	}
+
+	return config, nil
+}
+
+// To read the config from the .env file and the envs without validation
+func readConfig() (Config, error) {
 	var config Config
 
 	viper.AddConfigPath("./")
 	viper.SetConfigFile(".env")
 	viper.ReadInConfig()
 
+	for env, value := range defaults {
+		viper.SetDefault(env, value)
+	}
+
 	for _, env := range envs {
 		if err := viper.BindEnv(env); err != nil {
 			return config, err
@@ -37,9 +129,5 @@ func LoadConfig() (Config, error) {
 		return config, err
 	}
 
-	if err := validator.New().Struct(&config); err != nil {
-		return config, err
-	}
-
 	return config, nil
 }
@@ -20,6 +20,7 @@ func ConnectDatabase(cfg config.Config) (*gorm.DB, error) {
 
 	err = db.AutoMigrate(
 		domain.FileDetails{},
+		domain.UploadSession{},
 	)
 
 	if err != nil {
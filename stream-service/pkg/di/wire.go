@@ -8,8 +8,11 @@ import (
 	"stream-service/pkg/api/service"
 	"stream-service/pkg/config"
 	"stream-service/pkg/db"
-	"stream-service/pkg/file"
+	"stream-service/pkg/job"
+	"stream-service/pkg/logger"
+	"stream-service/pkg/metrics"
 	"stream-service/pkg/repository"
+	"stream-service/pkg/storage"
 	"stream-service/pkg/usecase"
 
 	"github.com/google/wire"
@@ -18,13 +21,33 @@ import (
 func InitializeAPI(cfg config.Config) (*api.Server, error) {
 
 	wire.Build(
+		logger.NewLogger,
+		metrics.NewMetrics,
 		db.ConnectDatabase,
 		repository.NewStreamRepository,
-		file.NewHandler,
+		storage.NewBackend,
 		usecase.NewStreamUseCase,
 		service.NewStreamService,
+		job.NewPurger,
+		job.NewRecovery,
+		job.NewReconciler,
 		api.NewServerGRPC,
 	)
 
 	return &api.Server{}, nil
 }
+
+func InitializeReconciler(cfg config.Config) (*job.Reconciler, error) {
+
+	wire.Build(
+		logger.NewLogger,
+		metrics.NewMetrics,
+		db.ConnectDatabase,
+		repository.NewStreamRepository,
+		storage.NewBackend,
+		usecase.NewStreamUseCase,
+		job.NewReconciler,
+	)
+
+	return &job.Reconciler{}, nil
+}
@@ -11,8 +11,11 @@ import (
 	"stream-service/pkg/api/service"
 	"stream-service/pkg/config"
 	"stream-service/pkg/db"
-	"stream-service/pkg/file"
+	"stream-service/pkg/job"
+	"stream-service/pkg/logger"
+	"stream-service/pkg/metrics"
 	"stream-service/pkg/repository"
+	"stream-service/pkg/storage"
 	"stream-service/pkg/usecase"
 )
 
@@ -23,13 +26,44 @@ func InitializeAPI(cfg config.Config) (*api.Server, error) {
 	if err != nil {
 		return nil, err
 	}
-	streamRepository := repository.NewStreamRepository(gormDB)
-	handler := file.NewHandler()
-	streamUseCase := usecase.NewStreamUseCase(streamRepository, handler)
-	streamServiceServer := service.NewStreamService(streamUseCase)
-	server, err := api.NewServerGRPC(cfg, streamServiceServer)
+	slogLogger, err := logger.NewLogger(cfg)
+	if err != nil {
+		return nil, err
+	}
+	streamRepository := repository.NewStreamRepository(gormDB, slogLogger)
+	backend, err := storage.NewBackend(cfg)
+	if err != nil {
+		return nil, err
+	}
+	metricsMetrics := metrics.NewMetrics()
+	streamUseCase := usecase.NewStreamUseCase(cfg, streamRepository, backend, slogLogger, metricsMetrics)
+	streamServiceServer := service.NewStreamService(streamUseCase, slogLogger, metricsMetrics)
+	purger := job.NewPurger(cfg, streamUseCase, slogLogger)
+	recovery := job.NewRecovery(streamUseCase, slogLogger)
+	reconciler := job.NewReconciler(cfg, streamUseCase, slogLogger)
+	server, err := api.NewServerGRPC(cfg, streamServiceServer, purger, recovery, reconciler, slogLogger, metricsMetrics)
 	if err != nil {
 		return nil, err
 	}
 	return server, nil
 }
+
+func InitializeReconciler(cfg config.Config) (*job.Reconciler, error) {
+	gormDB, err := db.ConnectDatabase(cfg)
+	if err != nil {
+		return nil, err
+	}
+	slogLogger, err := logger.NewLogger(cfg)
+	if err != nil {
+		return nil, err
+	}
+	streamRepository := repository.NewStreamRepository(gormDB, slogLogger)
+	backend, err := storage.NewBackend(cfg)
+	if err != nil {
+		return nil, err
+	}
+	metricsMetrics := metrics.NewMetrics()
+	streamUseCase := usecase.NewStreamUseCase(cfg, streamRepository, backend, slogLogger, metricsMetrics)
+	reconciler := job.NewReconciler(cfg, streamUseCase, slogLogger)
+	return reconciler, nil
+}
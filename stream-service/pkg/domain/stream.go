@@ -6,9 +6,44 @@ import (
 	"github.com/google/uuid"
 )
 
+// status of the file upload
+type UploadStatus string
+
+const (
+	UploadStatusPending   UploadStatus = "pending"   // file details saved and waiting for data
+	UploadStatusUploading UploadStatus = "uploading" // receiving data from stream
+	UploadStatusCompleted UploadStatus = "completed" // all data received and verified
+	UploadStatusFailed    UploadStatus = "failed"    // failed on server side, reason saved as failure reason
+	UploadStatusAborted   UploadStatus = "aborted"   // stream closed by client before completion
+)
+
 type FileDetails struct {
-	ID          uuid.UUID `gorm:"primaryKey;not null"`
-	Name        string    `gorm:"not null"`
-	ContentType string    `gorm:"not null"`
-	UploadedAt  time.Time `gorm:"not null"`
+	ID            uuid.UUID    `gorm:"primaryKey;not null"`
+	Owner         string       `gorm:"not null;default:'';index"` // subject of the uploader
+	Name          string       `gorm:"not null"`
+	ContentType   string       `gorm:"not null"`
+	UploadedAt    time.Time    `gorm:"not null"`
+	DeletedAt     *time.Time   `gorm:"index"` // moved to trash time
+	Size          int64        `gorm:"not null;default:0"`
+	SHA256        string       `gorm:"column:sha256"` // sha256 digest as hex
+	CRC32C        uint32       `gorm:"column:crc32c"` // crc32c (castagnoli) checksum
+	Status        UploadStatus `gorm:"not null;default:completed;index"`
+	CompletedAt   *time.Time
+	FailureReason string
+}
+
+// upload session to resume a failed upload from the committed offset
+type UploadSession struct {
+	ID              uuid.UUID  `gorm:"primaryKey;not null"`
+	Owner           string     `gorm:"not null;default:'';index"`
+	Name            string     `gorm:"not null"`
+	ContentType     string     `gorm:"not null"`
+	CommittedOffset int64      `gorm:"not null;default:0"`
+	Completed       bool       `gorm:"not null;default:false"`
+	CreatedAt       time.Time  `gorm:"not null"`
+	UpdatedAt       time.Time  `gorm:"not null"`
+	ExpectedSHA256  string     `gorm:"column:expected_sha256"`
+	ExpectedCRC32C  *uint32    `gorm:"column:expected_crc32c"`
+	ExpectedSize    *int64     `gorm:"column:expected_size"`
+	ClaimedUntil    *time.Time // claimed by an upload in progress until the time
 }
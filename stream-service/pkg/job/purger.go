@@ -0,0 +1,54 @@
+package job
+
+import (
+	"context"
+	"log/slog"
+	"stream-service/pkg/config"
+	"stream-service/pkg/logger"
+	"stream-service/pkg/usecase/interfaces"
+	"time"
+)
+
+// Purger to permanently remove the deleted files after the retention period and the expired upload sessions
+type Purger struct {
+	usecase  interfaces.StreamUseCase
+	logger   *slog.Logger
+	interval time.Duration
+}
+
+func NewPurger(cfg config.Config, usecase interfaces.StreamUseCase, logger *slog.Logger) *Purger {
+	return &Purger{
+		usecase:  usecase,
+		logger:   logger,
+		interval: cfg.FilePurgeInterval,
+	}
+}
+
+// Start run the purge on each interval until the context cancelled
+func (p *Purger) Start(ctx context.Context) {
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := p.usecase.PurgeDeletedFiles(ctx)
+		if err != nil {
+			p.logger.ErrorContext(ctx, "failed to purge deleted files", logger.KeyError, err)
+		} else if purged > 0 {
+			p.logger.InfoContext(ctx, "purged deleted files", "count", purged)
+		}
+
+		purged, err = p.usecase.PurgeExpiredUploadSessions(ctx)
+		if err != nil {
+			p.logger.ErrorContext(ctx, "failed to purge expired upload sessions", logger.KeyError, err)
+		} else if purged > 0 {
+			p.logger.InfoContext(ctx, "purged expired upload sessions", "count", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package job
+
+import (
+	"context"
+	"log/slog"
+	"stream-service/pkg/config"
+	"stream-service/pkg/logger"
+	"stream-service/pkg/models/response"
+	"stream-service/pkg/usecase/interfaces"
+	"time"
+)
+
+// Reconciler to find and repair the mismatches between the storage and database
+type Reconciler struct {
+	usecase  interfaces.StreamUseCase
+	logger   *slog.Logger
+	interval time.Duration
+	repair   bool
+}
+
+func NewReconciler(cfg config.Config, usecase interfaces.StreamUseCase, logger *slog.Logger) *Reconciler {
+	return &Reconciler{
+		usecase:  usecase,
+		logger:   logger,
+		interval: cfg.ReconcileInterval,
+		repair:   cfg.ReconcileRepair,
+	}
+}
+
+// Start run the reconciliation on each interval until the context cancelled, disabled if the interval is zero
+func (r *Reconciler) Start(ctx context.Context) {
+
+	if r.interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		report, err := r.Run(ctx, r.repair)
+		if err != nil {
+			r.logger.ErrorContext(ctx, "failed to reconcile storage and database", logger.KeyError, err)
+			continue
+		}
+		for _, mismatch := range report.Mismatches {
+			r.logger.WarnContext(ctx, "reconcile mismatch", logger.KeyFileID, mismatch.FileID,
+				"kind", mismatch.Kind, "detail", mismatch.Detail, "repaired", mismatch.Repaired)
+		}
+	}
+}
+
+// Run the reconciliation once, the mismatches only reported if repair is false
+func (r *Reconciler) Run(ctx context.Context, repair bool) (response.Reconciliation, error) {
+	return r.usecase.Reconcile(ctx, repair)
+}
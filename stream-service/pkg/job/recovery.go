@@ -0,0 +1,32 @@
+package job
+
+import (
+	"context"
+	"log/slog"
+	"stream-service/pkg/logger"
+	"stream-service/pkg/usecase/interfaces"
+)
+
+// Recovery to clean up or commit the temporary files left by the uploads interrupted on a crash
+type Recovery struct {
+	usecase interfaces.StreamUseCase
+	logger  *slog.Logger
+}
+
+func NewRecovery(usecase interfaces.StreamUseCase, logger *slog.Logger) *Recovery {
+	return &Recovery{
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+// Run the recovery once, should run on startup before accepting uploads
+func (r *Recovery) Run(ctx context.Context) {
+
+	recovered, err := r.usecase.RecoverUploads(ctx)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to recover interrupted uploads", logger.KeyError, err)
+	} else if recovered > 0 {
+		r.logger.InfoContext(ctx, "recovered interrupted uploads", "count", recovered)
+	}
+}
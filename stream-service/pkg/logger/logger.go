@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"stream-service/pkg/auth"
+	"stream-service/pkg/config"
+	"stream-service/pkg/requestid"
+)
+
+// keys of the attributes logged with the logs
+const (
+	KeyFileID    = "file_id"
+	KeyRequestID = "request_id"
+	KeyOwner     = "owner"
+	KeyError     = "error"
+)
+
+// To create the logger writing to stderr on the level and format of the config,
+// the request id and owner of the context added to the logs written with the context
+func NewLogger(cfg config.Config) (*slog.Logger, error) {
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.LogLevel)); err != nil {
+		return nil, err
+	}
+	opts := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	if cfg.LogFormat == "text" {
+		handler = slog.NewTextHandler(os.Stderr, opts)
+	} else {
+		handler = slog.NewJSONHandler(os.Stderr, opts)
+	}
+
+	return New(handler), nil
+}
+
+// To create the logger writing to the handler, with the request id and owner of the context added to the logs
+func New(handler slog.Handler) *slog.Logger {
+	return slog.New(contextHandler{Handler: handler})
+}
+
+// handler adding the attributes of the request on the context to the records
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+
+	if id := requestid.FromContext(ctx); id != "" {
+		record.AddAttrs(slog.String(KeyRequestID, id))
+	}
+	if owner := auth.OwnerFromContext(ctx); owner != "" {
+		record.AddAttrs(slog.String(KeyOwner, owner))
+	}
+
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// reasons of the upload failures
+const (
+	ReasonTimeout     = "timeout"      // the stream idle or its deadline exceeded
+	ReasonWriteError  = "write_error"  // the data failed to store
+	ReasonClientAbort = "client_abort" // the client cancelled or failed the stream
+	ReasonOther       = "other"
+)
+
+// Metrics of the upload streams, registered on its own registry to serve on the metrics listener
+type Metrics struct {
+	registry *prometheus.Registry
+
+	BytesReceived  prometheus.Counter
+	BytesWritten   prometheus.Counter
+	UploadDuration prometheus.Histogram
+	ChunkSize      prometheus.Histogram
+	ActiveStreams  prometheus.Gauge
+	UploadFailures *prometheus.CounterVec // by grpc code and reason
+}
+
+func NewMetrics() *Metrics {
+
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		BytesReceived: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "stream_bytes_received_total",
+			Help: "Bytes of file data received on the upload streams.",
+		}),
+		BytesWritten: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "stream_bytes_written_total",
+			Help: "Bytes of file data written to storage.",
+		}),
+		UploadDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "stream_upload_duration_seconds",
+			Help:    "Duration of the upload streams.",
+			Buckets: prometheus.ExponentialBuckets(0.01, 4, 10), // 10ms to ~45m
+		}),
+		ChunkSize: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "stream_chunk_size_bytes",
+			Help:    "Size of the data on the chunks received on the upload streams.",
+			Buckets: prometheus.ExponentialBuckets(1024, 4, 7), // 1KiB to 4MiB
+		}),
+		ActiveStreams: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "stream_active_upload_streams",
+			Help: "Upload streams in progress.",
+		}),
+		UploadFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "stream_upload_failures_total",
+			Help: "Failed upload streams by grpc code and reason.",
+		}, []string{"code", "reason"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.BytesReceived,
+		m.BytesWritten,
+		m.UploadDuration,
+		m.ChunkSize,
+		m.ActiveStreams,
+		m.UploadFailures,
+	)
+
+	return m
+}
+
+// To get the handler serving the metrics on the prometheus text format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
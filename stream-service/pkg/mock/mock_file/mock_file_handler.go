@@ -1,117 +0,0 @@
-// Code generated by MockGen. DO NOT EDIT.
-// Source: ./pkg/file/file_handler.go
-
-// Package mock_file is a generated GoMock package.
-package mock_file
-
-import (
-	fs "io/fs"
-	reflect "reflect"
-	file "stream-service/pkg/file"
-
-	gomock "github.com/golang/mock/gomock"
-)
-
-// MockHandler is a mock of Handler interface.
-type MockHandler struct {
-	ctrl     *gomock.Controller
-	recorder *MockHandlerMockRecorder
-}
-
-// MockHandlerMockRecorder is the mock recorder for MockHandler.
-type MockHandlerMockRecorder struct {
-	mock *MockHandler
-}
-
-// NewMockHandler creates a new mock instance.
-func NewMockHandler(ctrl *gomock.Controller) *MockHandler {
-	mock := &MockHandler{ctrl: ctrl}
-	mock.recorder = &MockHandlerMockRecorder{mock}
-	return mock
-}
-
-// EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockHandler) EXPECT() *MockHandlerMockRecorder {
-	return m.recorder
-}
-
-// Create mocks base method.
-func (m *MockHandler) Create(name string) (file.File, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", name)
-	ret0, _ := ret[0].(file.File)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
-
-// Create indicates an expected call of Create.
-func (mr *MockHandlerMockRecorder) Create(name interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockHandler)(nil).Create), name)
-}
-
-// MkdirAll mocks base method.
-func (m *MockHandler) MkdirAll(path string, perm fs.FileMode) error {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "MkdirAll", path, perm)
-	ret0, _ := ret[0].(error)
-	return ret0
-}
-
-// MkdirAll indicates an expected call of MkdirAll.
-func (mr *MockHandlerMockRecorder) MkdirAll(path, perm interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MkdirAll", reflect.TypeOf((*MockHandler)(nil).MkdirAll), path, perm)
-}
-
-// MockFile is a mock of File interface.
-type MockFile struct {
-	ctrl     *gomock.Controller
-	recorder *MockFileMockRecorder
-}
-
-// MockFileMockRecorder is the mock recorder for MockFile.
-type MockFileMockRecorder struct {
-	mock *MockFile
-}
-
-// NewMockFile creates a new mock instance.
-func NewMockFile(ctrl *gomock.Controller) *MockFile {
-	mock := &MockFile{ctrl: ctrl}
-	mock.recorder = &MockFileMockRecorder{mock}
-	return mock
-}
-
-// EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockFile) EXPECT() *MockFileMockRecorder {
-	return m.recorder
-}
-
-// Close mocks base method.
-func (m *MockFile) Close() error {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Close")
-	ret0, _ := ret[0].(error)
-	return ret0
-}
-
-// Close indicates an expected call of Close.
-func (mr *MockFileMockRecorder) Close() *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockFile)(nil).Close))
-}
-
-// Write mocks base method.
-func (m *MockFile) Write(data []byte) (int, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Write", data)
-	ret0, _ := ret[0].(int)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
-
-// Write indicates an expected call of Write.
-func (mr *MockFileMockRecorder) Write(data interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Write", reflect.TypeOf((*MockFile)(nil).Write), data)
-}
@@ -8,6 +8,8 @@ import (
 	context "context"
 	reflect "reflect"
 	domain "stream-service/pkg/domain"
+	request "stream-service/pkg/models/request"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -35,6 +37,195 @@ func (m *MockStreamRepository) EXPECT() *MockStreamRepositoryMockRecorder {
 	return m.recorder
 }
 
+// ClaimUploadSession mocks base method.
+func (m *MockStreamRepository) ClaimUploadSession(ctx context.Context, id string, offset int64, until time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimUploadSession", ctx, id, offset, until)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimUploadSession indicates an expected call of ClaimUploadSession.
+func (mr *MockStreamRepositoryMockRecorder) ClaimUploadSession(ctx, id, offset, until interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimUploadSession", reflect.TypeOf((*MockStreamRepository)(nil).ClaimUploadSession), ctx, id, offset, until)
+}
+
+// CompleteFileDetails mocks base method.
+func (m *MockStreamRepository) CompleteFileDetails(ctx context.Context, details domain.FileDetails) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteFileDetails", ctx, details)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteFileDetails indicates an expected call of CompleteFileDetails.
+func (mr *MockStreamRepositoryMockRecorder) CompleteFileDetails(ctx, details interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteFileDetails", reflect.TypeOf((*MockStreamRepository)(nil).CompleteFileDetails), ctx, details)
+}
+
+// CompleteUploadSession mocks base method.
+func (m *MockStreamRepository) CompleteUploadSession(ctx context.Context, details domain.FileDetails) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteUploadSession", ctx, details)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteUploadSession indicates an expected call of CompleteUploadSession.
+func (mr *MockStreamRepositoryMockRecorder) CompleteUploadSession(ctx, details interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteUploadSession", reflect.TypeOf((*MockStreamRepository)(nil).CompleteUploadSession), ctx, details)
+}
+
+// DeleteFileDetails mocks base method.
+func (m *MockStreamRepository) DeleteFileDetails(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFileDetails", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFileDetails indicates an expected call of DeleteFileDetails.
+func (mr *MockStreamRepositoryMockRecorder) DeleteFileDetails(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFileDetails", reflect.TypeOf((*MockStreamRepository)(nil).DeleteFileDetails), ctx, id)
+}
+
+// ExtendUploadSessionClaim mocks base method.
+func (m *MockStreamRepository) ExtendUploadSessionClaim(ctx context.Context, id string, until time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExtendUploadSessionClaim", ctx, id, until)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExtendUploadSessionClaim indicates an expected call of ExtendUploadSessionClaim.
+func (mr *MockStreamRepositoryMockRecorder) ExtendUploadSessionClaim(ctx, id, until interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtendUploadSessionClaim", reflect.TypeOf((*MockStreamRepository)(nil).ExtendUploadSessionClaim), ctx, id, until)
+}
+
+// FindAllFileDetails mocks base method.
+func (m *MockStreamRepository) FindAllFileDetails(ctx context.Context, filter request.FileFilter) ([]domain.FileDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAllFileDetails", ctx, filter)
+	ret0, _ := ret[0].([]domain.FileDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAllFileDetails indicates an expected call of FindAllFileDetails.
+func (mr *MockStreamRepositoryMockRecorder) FindAllFileDetails(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllFileDetails", reflect.TypeOf((*MockStreamRepository)(nil).FindAllFileDetails), ctx, filter)
+}
+
+// FindDeletedFileDetails mocks base method.
+func (m *MockStreamRepository) FindDeletedFileDetails(ctx context.Context, deletedBefore time.Time, limit int) ([]domain.FileDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDeletedFileDetails", ctx, deletedBefore, limit)
+	ret0, _ := ret[0].([]domain.FileDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDeletedFileDetails indicates an expected call of FindDeletedFileDetails.
+func (mr *MockStreamRepositoryMockRecorder) FindDeletedFileDetails(ctx, deletedBefore, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDeletedFileDetails", reflect.TypeOf((*MockStreamRepository)(nil).FindDeletedFileDetails), ctx, deletedBefore, limit)
+}
+
+// FindExpiredUploadSessions mocks base method.
+func (m *MockStreamRepository) FindExpiredUploadSessions(ctx context.Context, updatedBefore time.Time, limit int) ([]domain.UploadSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindExpiredUploadSessions", ctx, updatedBefore, limit)
+	ret0, _ := ret[0].([]domain.UploadSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindExpiredUploadSessions indicates an expected call of FindExpiredUploadSessions.
+func (mr *MockStreamRepositoryMockRecorder) FindExpiredUploadSessions(ctx, updatedBefore, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindExpiredUploadSessions", reflect.TypeOf((*MockStreamRepository)(nil).FindExpiredUploadSessions), ctx, updatedBefore, limit)
+}
+
+// FindFileDetailsAfterID mocks base method.
+func (m *MockStreamRepository) FindFileDetailsAfterID(ctx context.Context, afterID string, limit int) ([]domain.FileDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFileDetailsAfterID", ctx, afterID, limit)
+	ret0, _ := ret[0].([]domain.FileDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindFileDetailsAfterID indicates an expected call of FindFileDetailsAfterID.
+func (mr *MockStreamRepositoryMockRecorder) FindFileDetailsAfterID(ctx, afterID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFileDetailsAfterID", reflect.TypeOf((*MockStreamRepository)(nil).FindFileDetailsAfterID), ctx, afterID, limit)
+}
+
+// FindFileDetailsByID mocks base method.
+func (m *MockStreamRepository) FindFileDetailsByID(ctx context.Context, id string) (domain.FileDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFileDetailsByID", ctx, id)
+	ret0, _ := ret[0].(domain.FileDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindFileDetailsByID indicates an expected call of FindFileDetailsByID.
+func (mr *MockStreamRepositoryMockRecorder) FindFileDetailsByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFileDetailsByID", reflect.TypeOf((*MockStreamRepository)(nil).FindFileDetailsByID), ctx, id)
+}
+
+// FindUploadSessionByID mocks base method.
+func (m *MockStreamRepository) FindUploadSessionByID(ctx context.Context, id string) (domain.UploadSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUploadSessionByID", ctx, id)
+	ret0, _ := ret[0].(domain.UploadSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUploadSessionByID indicates an expected call of FindUploadSessionByID.
+func (mr *MockStreamRepositoryMockRecorder) FindUploadSessionByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUploadSessionByID", reflect.TypeOf((*MockStreamRepository)(nil).FindUploadSessionByID), ctx, id)
+}
+
+// ReleaseUploadSession mocks base method.
+func (m *MockStreamRepository) ReleaseUploadSession(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseUploadSession", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseUploadSession indicates an expected call of ReleaseUploadSession.
+func (mr *MockStreamRepositoryMockRecorder) ReleaseUploadSession(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseUploadSession", reflect.TypeOf((*MockStreamRepository)(nil).ReleaseUploadSession), ctx, id)
+}
+
+// RestoreFileDetails mocks base method.
+func (m *MockStreamRepository) RestoreFileDetails(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreFileDetails", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreFileDetails indicates an expected call of RestoreFileDetails.
+func (mr *MockStreamRepositoryMockRecorder) RestoreFileDetails(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreFileDetails", reflect.TypeOf((*MockStreamRepository)(nil).RestoreFileDetails), ctx, id)
+}
+
 // SaveFileDetails mocks base method.
 func (m *MockStreamRepository) SaveFileDetails(ctx context.Context, details domain.FileDetails) error {
 	m.ctrl.T.Helper()
@@ -48,3 +239,59 @@ func (mr *MockStreamRepositoryMockRecorder) SaveFileDetails(ctx, details interfa
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveFileDetails", reflect.TypeOf((*MockStreamRepository)(nil).SaveFileDetails), ctx, details)
 }
+
+// SaveUploadSession mocks base method.
+func (m *MockStreamRepository) SaveUploadSession(ctx context.Context, session domain.UploadSession) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveUploadSession", ctx, session)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveUploadSession indicates an expected call of SaveUploadSession.
+func (mr *MockStreamRepositoryMockRecorder) SaveUploadSession(ctx, session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveUploadSession", reflect.TypeOf((*MockStreamRepository)(nil).SaveUploadSession), ctx, session)
+}
+
+// SoftDeleteFileDetails mocks base method.
+func (m *MockStreamRepository) SoftDeleteFileDetails(ctx context.Context, id string, deletedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SoftDeleteFileDetails", ctx, id, deletedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SoftDeleteFileDetails indicates an expected call of SoftDeleteFileDetails.
+func (mr *MockStreamRepositoryMockRecorder) SoftDeleteFileDetails(ctx, id, deletedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDeleteFileDetails", reflect.TypeOf((*MockStreamRepository)(nil).SoftDeleteFileDetails), ctx, id, deletedAt)
+}
+
+// UpdateFileDetailsStatus mocks base method.
+func (m *MockStreamRepository) UpdateFileDetailsStatus(ctx context.Context, id string, status domain.UploadStatus, failureReason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFileDetailsStatus", ctx, id, status, failureReason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateFileDetailsStatus indicates an expected call of UpdateFileDetailsStatus.
+func (mr *MockStreamRepositoryMockRecorder) UpdateFileDetailsStatus(ctx, id, status, failureReason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFileDetailsStatus", reflect.TypeOf((*MockStreamRepository)(nil).UpdateFileDetailsStatus), ctx, id, status, failureReason)
+}
+
+// UpdateUploadSessionOffset mocks base method.
+func (m *MockStreamRepository) UpdateUploadSessionOffset(ctx context.Context, id string, offset int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUploadSessionOffset", ctx, id, offset)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUploadSessionOffset indicates an expected call of UpdateUploadSessionOffset.
+func (mr *MockStreamRepositoryMockRecorder) UpdateUploadSessionOffset(ctx, id, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUploadSessionOffset", reflect.TypeOf((*MockStreamRepository)(nil).UpdateUploadSessionOffset), ctx, id, offset)
+}
@@ -37,6 +37,206 @@ func (m *MockStreamServiceClient) EXPECT() *MockStreamServiceClientMockRecorder
 	return m.recorder
 }
 
+// CompleteMultipartUpload mocks base method.
+func (m *MockStreamServiceClient) CompleteMultipartUpload(ctx context.Context, in *pb.CompleteMultipartUploadRequest, opts ...grpc.CallOption) (*pb.UploadResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CompleteMultipartUpload", varargs...)
+	ret0, _ := ret[0].(*pb.UploadResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteMultipartUpload indicates an expected call of CompleteMultipartUpload.
+func (mr *MockStreamServiceClientMockRecorder) CompleteMultipartUpload(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteMultipartUpload", reflect.TypeOf((*MockStreamServiceClient)(nil).CompleteMultipartUpload), varargs...)
+}
+
+// CreateUploadSession mocks base method.
+func (m *MockStreamServiceClient) CreateUploadSession(ctx context.Context, in *pb.FileMetaData, opts ...grpc.CallOption) (*pb.UploadSession, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateUploadSession", varargs...)
+	ret0, _ := ret[0].(*pb.UploadSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUploadSession indicates an expected call of CreateUploadSession.
+func (mr *MockStreamServiceClientMockRecorder) CreateUploadSession(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUploadSession", reflect.TypeOf((*MockStreamServiceClient)(nil).CreateUploadSession), varargs...)
+}
+
+// DeleteFile mocks base method.
+func (m *MockStreamServiceClient) DeleteFile(ctx context.Context, in *pb.DeleteFileRequest, opts ...grpc.CallOption) (*pb.DeleteFileResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteFile", varargs...)
+	ret0, _ := ret[0].(*pb.DeleteFileResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteFile indicates an expected call of DeleteFile.
+func (mr *MockStreamServiceClientMockRecorder) DeleteFile(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFile", reflect.TypeOf((*MockStreamServiceClient)(nil).DeleteFile), varargs...)
+}
+
+// DeleteUploadSession mocks base method.
+func (m *MockStreamServiceClient) DeleteUploadSession(ctx context.Context, in *pb.UploadSessionRequest, opts ...grpc.CallOption) (*pb.DeleteUploadSessionResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteUploadSession", varargs...)
+	ret0, _ := ret[0].(*pb.DeleteUploadSessionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteUploadSession indicates an expected call of DeleteUploadSession.
+func (mr *MockStreamServiceClientMockRecorder) DeleteUploadSession(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUploadSession", reflect.TypeOf((*MockStreamServiceClient)(nil).DeleteUploadSession), varargs...)
+}
+
+// Download mocks base method.
+func (m *MockStreamServiceClient) Download(ctx context.Context, in *pb.DownloadRequest, opts ...grpc.CallOption) (pb.StreamService_DownloadClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Download", varargs...)
+	ret0, _ := ret[0].(pb.StreamService_DownloadClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Download indicates an expected call of Download.
+func (mr *MockStreamServiceClientMockRecorder) Download(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Download", reflect.TypeOf((*MockStreamServiceClient)(nil).Download), varargs...)
+}
+
+// GetFile mocks base method.
+func (m *MockStreamServiceClient) GetFile(ctx context.Context, in *pb.GetFileRequest, opts ...grpc.CallOption) (*pb.FileDetails, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetFile", varargs...)
+	ret0, _ := ret[0].(*pb.FileDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFile indicates an expected call of GetFile.
+func (mr *MockStreamServiceClientMockRecorder) GetFile(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFile", reflect.TypeOf((*MockStreamServiceClient)(nil).GetFile), varargs...)
+}
+
+// GetUploadSession mocks base method.
+func (m *MockStreamServiceClient) GetUploadSession(ctx context.Context, in *pb.UploadSessionRequest, opts ...grpc.CallOption) (*pb.UploadSession, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetUploadSession", varargs...)
+	ret0, _ := ret[0].(*pb.UploadSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUploadSession indicates an expected call of GetUploadSession.
+func (mr *MockStreamServiceClientMockRecorder) GetUploadSession(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUploadSession", reflect.TypeOf((*MockStreamServiceClient)(nil).GetUploadSession), varargs...)
+}
+
+// ListFiles mocks base method.
+func (m *MockStreamServiceClient) ListFiles(ctx context.Context, in *pb.ListFilesRequest, opts ...grpc.CallOption) (*pb.ListFilesResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListFiles", varargs...)
+	ret0, _ := ret[0].(*pb.ListFilesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFiles indicates an expected call of ListFiles.
+func (mr *MockStreamServiceClientMockRecorder) ListFiles(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFiles", reflect.TypeOf((*MockStreamServiceClient)(nil).ListFiles), varargs...)
+}
+
+// RestoreFile mocks base method.
+func (m *MockStreamServiceClient) RestoreFile(ctx context.Context, in *pb.RestoreFileRequest, opts ...grpc.CallOption) (*pb.FileDetails, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RestoreFile", varargs...)
+	ret0, _ := ret[0].(*pb.FileDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreFile indicates an expected call of RestoreFile.
+func (mr *MockStreamServiceClientMockRecorder) RestoreFile(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreFile", reflect.TypeOf((*MockStreamServiceClient)(nil).RestoreFile), varargs...)
+}
+
+// ResumeUpload mocks base method.
+func (m *MockStreamServiceClient) ResumeUpload(ctx context.Context, opts ...grpc.CallOption) (pb.StreamService_ResumeUploadClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ResumeUpload", varargs...)
+	ret0, _ := ret[0].(pb.StreamService_ResumeUploadClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResumeUpload indicates an expected call of ResumeUpload.
+func (mr *MockStreamServiceClientMockRecorder) ResumeUpload(ctx interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeUpload", reflect.TypeOf((*MockStreamServiceClient)(nil).ResumeUpload), varargs...)
+}
+
 // Upload mocks base method.
 func (m *MockStreamServiceClient) Upload(ctx context.Context, opts ...grpc.CallOption) (pb.StreamService_UploadClient, error) {
 	m.ctrl.T.Helper()
@@ -57,6 +257,26 @@ func (mr *MockStreamServiceClientMockRecorder) Upload(ctx interface{}, opts ...i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upload", reflect.TypeOf((*MockStreamServiceClient)(nil).Upload), varargs...)
 }
 
+// UploadWithProgress mocks base method.
+func (m *MockStreamServiceClient) UploadWithProgress(ctx context.Context, opts ...grpc.CallOption) (pb.StreamService_UploadWithProgressClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UploadWithProgress", varargs...)
+	ret0, _ := ret[0].(pb.StreamService_UploadWithProgressClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadWithProgress indicates an expected call of UploadWithProgress.
+func (mr *MockStreamServiceClientMockRecorder) UploadWithProgress(ctx interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadWithProgress", reflect.TypeOf((*MockStreamServiceClient)(nil).UploadWithProgress), varargs...)
+}
+
 // MockStreamService_UploadClient is a mock of StreamService_UploadClient interface.
 type MockStreamService_UploadClient struct {
 	ctrl     *gomock.Controller
@@ -194,144 +414,89 @@ func (mr *MockStreamService_UploadClientMockRecorder) Trailer() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockStreamService_UploadClient)(nil).Trailer))
 }
 
-// MockStreamServiceServer is a mock of StreamServiceServer interface.
-type MockStreamServiceServer struct {
+// MockStreamService_UploadWithProgressClient is a mock of StreamService_UploadWithProgressClient interface.
+type MockStreamService_UploadWithProgressClient struct {
 	ctrl     *gomock.Controller
-	recorder *MockStreamServiceServerMockRecorder
+	recorder *MockStreamService_UploadWithProgressClientMockRecorder
 }
 
-// MockStreamServiceServerMockRecorder is the mock recorder for MockStreamServiceServer.
-type MockStreamServiceServerMockRecorder struct {
-	mock *MockStreamServiceServer
+// MockStreamService_UploadWithProgressClientMockRecorder is the mock recorder for MockStreamService_UploadWithProgressClient.
+type MockStreamService_UploadWithProgressClientMockRecorder struct {
+	mock *MockStreamService_UploadWithProgressClient
 }
 
-// NewMockStreamServiceServer creates a new mock instance.
-func NewMockStreamServiceServer(ctrl *gomock.Controller) *MockStreamServiceServer {
-	mock := &MockStreamServiceServer{ctrl: ctrl}
-	mock.recorder = &MockStreamServiceServerMockRecorder{mock}
+// NewMockStreamService_UploadWithProgressClient creates a new mock instance.
+func NewMockStreamService_UploadWithProgressClient(ctrl *gomock.Controller) *MockStreamService_UploadWithProgressClient {
+	mock := &MockStreamService_UploadWithProgressClient{ctrl: ctrl}
+	mock.recorder = &MockStreamService_UploadWithProgressClientMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockStreamServiceServer) EXPECT() *MockStreamServiceServerMockRecorder {
+func (m *MockStreamService_UploadWithProgressClient) EXPECT() *MockStreamService_UploadWithProgressClientMockRecorder {
 	return m.recorder
 }
 
-// Upload mocks base method.
-func (m *MockStreamServiceServer) Upload(arg0 pb.StreamService_UploadServer) error {
+// CloseSend mocks base method.
+func (m *MockStreamService_UploadWithProgressClient) CloseSend() error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Upload", arg0)
+	ret := m.ctrl.Call(m, "CloseSend")
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Upload indicates an expected call of Upload.
-func (mr *MockStreamServiceServerMockRecorder) Upload(arg0 interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upload", reflect.TypeOf((*MockStreamServiceServer)(nil).Upload), arg0)
-}
-
-// mustEmbedUnimplementedStreamServiceServer mocks base method.
-func (m *MockStreamServiceServer) mustEmbedUnimplementedStreamServiceServer() {
-	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "mustEmbedUnimplementedStreamServiceServer")
-}
-
-// mustEmbedUnimplementedStreamServiceServer indicates an expected call of mustEmbedUnimplementedStreamServiceServer.
-func (mr *MockStreamServiceServerMockRecorder) mustEmbedUnimplementedStreamServiceServer() *gomock.Call {
+// CloseSend indicates an expected call of CloseSend.
+func (mr *MockStreamService_UploadWithProgressClientMockRecorder) CloseSend() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "mustEmbedUnimplementedStreamServiceServer", reflect.TypeOf((*MockStreamServiceServer)(nil).mustEmbedUnimplementedStreamServiceServer))
-}
-
-// MockUnsafeStreamServiceServer is a mock of UnsafeStreamServiceServer interface.
-type MockUnsafeStreamServiceServer struct {
-	ctrl     *gomock.Controller
-	recorder *MockUnsafeStreamServiceServerMockRecorder
-}
-
-// MockUnsafeStreamServiceServerMockRecorder is the mock recorder for MockUnsafeStreamServiceServer.
-type MockUnsafeStreamServiceServerMockRecorder struct {
-	mock *MockUnsafeStreamServiceServer
-}
-
-// NewMockUnsafeStreamServiceServer creates a new mock instance.
-func NewMockUnsafeStreamServiceServer(ctrl *gomock.Controller) *MockUnsafeStreamServiceServer {
-	mock := &MockUnsafeStreamServiceServer{ctrl: ctrl}
-	mock.recorder = &MockUnsafeStreamServiceServerMockRecorder{mock}
-	return mock
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockStreamService_UploadWithProgressClient)(nil).CloseSend))
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockUnsafeStreamServiceServer) EXPECT() *MockUnsafeStreamServiceServerMockRecorder {
-	return m.recorder
-}
-
-// mustEmbedUnimplementedStreamServiceServer mocks base method.
-func (m *MockUnsafeStreamServiceServer) mustEmbedUnimplementedStreamServiceServer() {
+// Context mocks base method.
+func (m *MockStreamService_UploadWithProgressClient) Context() context.Context {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "mustEmbedUnimplementedStreamServiceServer")
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
 }
 
-// mustEmbedUnimplementedStreamServiceServer indicates an expected call of mustEmbedUnimplementedStreamServiceServer.
-func (mr *MockUnsafeStreamServiceServerMockRecorder) mustEmbedUnimplementedStreamServiceServer() *gomock.Call {
+// Context indicates an expected call of Context.
+func (mr *MockStreamService_UploadWithProgressClientMockRecorder) Context() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "mustEmbedUnimplementedStreamServiceServer", reflect.TypeOf((*MockUnsafeStreamServiceServer)(nil).mustEmbedUnimplementedStreamServiceServer))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockStreamService_UploadWithProgressClient)(nil).Context))
 }
 
-// MockStreamService_UploadServer is a mock of StreamService_UploadServer interface.
-type MockStreamService_UploadServer struct {
-	ctrl     *gomock.Controller
-	recorder *MockStreamService_UploadServerMockRecorder
-}
-
-// MockStreamService_UploadServerMockRecorder is the mock recorder for MockStreamService_UploadServer.
-type MockStreamService_UploadServerMockRecorder struct {
-	mock *MockStreamService_UploadServer
-}
-
-// NewMockStreamService_UploadServer creates a new mock instance.
-func NewMockStreamService_UploadServer(ctrl *gomock.Controller) *MockStreamService_UploadServer {
-	mock := &MockStreamService_UploadServer{ctrl: ctrl}
-	mock.recorder = &MockStreamService_UploadServerMockRecorder{mock}
-	return mock
-}
-
-// EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockStreamService_UploadServer) EXPECT() *MockStreamService_UploadServerMockRecorder {
-	return m.recorder
-}
-
-// Context mocks base method.
-func (m *MockStreamService_UploadServer) Context() context.Context {
+// Header mocks base method.
+func (m *MockStreamService_UploadWithProgressClient) Header() (metadata.MD, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Context")
-	ret0, _ := ret[0].(context.Context)
-	return ret0
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// Context indicates an expected call of Context.
-func (mr *MockStreamService_UploadServerMockRecorder) Context() *gomock.Call {
+// Header indicates an expected call of Header.
+func (mr *MockStreamService_UploadWithProgressClientMockRecorder) Header() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockStreamService_UploadServer)(nil).Context))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockStreamService_UploadWithProgressClient)(nil).Header))
 }
 
 // Recv mocks base method.
-func (m *MockStreamService_UploadServer) Recv() (*pb.UploadRequest, error) {
+func (m *MockStreamService_UploadWithProgressClient) Recv() (*pb.UploadProgress, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Recv")
-	ret0, _ := ret[0].(*pb.UploadRequest)
+	ret0, _ := ret[0].(*pb.UploadProgress)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Recv indicates an expected call of Recv.
-func (mr *MockStreamService_UploadServerMockRecorder) Recv() *gomock.Call {
+func (mr *MockStreamService_UploadWithProgressClientMockRecorder) Recv() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockStreamService_UploadServer)(nil).Recv))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockStreamService_UploadWithProgressClient)(nil).Recv))
 }
 
 // RecvMsg mocks base method.
-func (m_2 *MockStreamService_UploadServer) RecvMsg(m interface{}) error {
+func (m_2 *MockStreamService_UploadWithProgressClient) RecvMsg(m interface{}) error {
 	m_2.ctrl.T.Helper()
 	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
 	ret0, _ := ret[0].(error)
@@ -339,41 +504,789 @@ func (m_2 *MockStreamService_UploadServer) RecvMsg(m interface{}) error {
 }
 
 // RecvMsg indicates an expected call of RecvMsg.
-func (mr *MockStreamService_UploadServerMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+func (mr *MockStreamService_UploadWithProgressClientMockRecorder) RecvMsg(m interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockStreamService_UploadServer)(nil).RecvMsg), m)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockStreamService_UploadWithProgressClient)(nil).RecvMsg), m)
 }
 
-// SendAndClose mocks base method.
-func (m *MockStreamService_UploadServer) SendAndClose(arg0 *pb.UploadResponse) error {
+// Send mocks base method.
+func (m *MockStreamService_UploadWithProgressClient) Send(arg0 *pb.UploadRequest) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SendAndClose", arg0)
+	ret := m.ctrl.Call(m, "Send", arg0)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SendAndClose indicates an expected call of SendAndClose.
-func (mr *MockStreamService_UploadServerMockRecorder) SendAndClose(arg0 interface{}) *gomock.Call {
+// Send indicates an expected call of Send.
+func (mr *MockStreamService_UploadWithProgressClientMockRecorder) Send(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendAndClose", reflect.TypeOf((*MockStreamService_UploadServer)(nil).SendAndClose), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockStreamService_UploadWithProgressClient)(nil).Send), arg0)
 }
 
-// SendHeader mocks base method.
-func (m *MockStreamService_UploadServer) SendHeader(arg0 metadata.MD) error {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SendHeader", arg0)
+// SendMsg mocks base method.
+func (m_2 *MockStreamService_UploadWithProgressClient) SendMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SendHeader indicates an expected call of SendHeader.
-func (mr *MockStreamService_UploadServerMockRecorder) SendHeader(arg0 interface{}) *gomock.Call {
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockStreamService_UploadWithProgressClientMockRecorder) SendMsg(m interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockStreamService_UploadServer)(nil).SendHeader), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockStreamService_UploadWithProgressClient)(nil).SendMsg), m)
+}
+
+// Trailer mocks base method.
+func (m *MockStreamService_UploadWithProgressClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer.
+func (mr *MockStreamService_UploadWithProgressClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockStreamService_UploadWithProgressClient)(nil).Trailer))
+}
+
+// MockStreamService_DownloadClient is a mock of StreamService_DownloadClient interface.
+type MockStreamService_DownloadClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockStreamService_DownloadClientMockRecorder
+}
+
+// MockStreamService_DownloadClientMockRecorder is the mock recorder for MockStreamService_DownloadClient.
+type MockStreamService_DownloadClientMockRecorder struct {
+	mock *MockStreamService_DownloadClient
+}
+
+// NewMockStreamService_DownloadClient creates a new mock instance.
+func NewMockStreamService_DownloadClient(ctrl *gomock.Controller) *MockStreamService_DownloadClient {
+	mock := &MockStreamService_DownloadClient{ctrl: ctrl}
+	mock.recorder = &MockStreamService_DownloadClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStreamService_DownloadClient) EXPECT() *MockStreamService_DownloadClientMockRecorder {
+	return m.recorder
+}
+
+// CloseSend mocks base method.
+func (m *MockStreamService_DownloadClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend.
+func (mr *MockStreamService_DownloadClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockStreamService_DownloadClient)(nil).CloseSend))
+}
+
+// Context mocks base method.
+func (m *MockStreamService_DownloadClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockStreamService_DownloadClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockStreamService_DownloadClient)(nil).Context))
+}
+
+// Header mocks base method.
+func (m *MockStreamService_DownloadClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header.
+func (mr *MockStreamService_DownloadClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockStreamService_DownloadClient)(nil).Header))
+}
+
+// Recv mocks base method.
+func (m *MockStreamService_DownloadClient) Recv() (*pb.DownloadResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*pb.DownloadResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockStreamService_DownloadClientMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockStreamService_DownloadClient)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockStreamService_DownloadClient) RecvMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockStreamService_DownloadClientMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockStreamService_DownloadClient)(nil).RecvMsg), m)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockStreamService_DownloadClient) SendMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockStreamService_DownloadClientMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockStreamService_DownloadClient)(nil).SendMsg), m)
+}
+
+// Trailer mocks base method.
+func (m *MockStreamService_DownloadClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer.
+func (mr *MockStreamService_DownloadClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockStreamService_DownloadClient)(nil).Trailer))
+}
+
+// MockStreamService_ResumeUploadClient is a mock of StreamService_ResumeUploadClient interface.
+type MockStreamService_ResumeUploadClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockStreamService_ResumeUploadClientMockRecorder
+}
+
+// MockStreamService_ResumeUploadClientMockRecorder is the mock recorder for MockStreamService_ResumeUploadClient.
+type MockStreamService_ResumeUploadClientMockRecorder struct {
+	mock *MockStreamService_ResumeUploadClient
+}
+
+// NewMockStreamService_ResumeUploadClient creates a new mock instance.
+func NewMockStreamService_ResumeUploadClient(ctrl *gomock.Controller) *MockStreamService_ResumeUploadClient {
+	mock := &MockStreamService_ResumeUploadClient{ctrl: ctrl}
+	mock.recorder = &MockStreamService_ResumeUploadClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStreamService_ResumeUploadClient) EXPECT() *MockStreamService_ResumeUploadClientMockRecorder {
+	return m.recorder
+}
+
+// CloseAndRecv mocks base method.
+func (m *MockStreamService_ResumeUploadClient) CloseAndRecv() (*pb.UploadResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseAndRecv")
+	ret0, _ := ret[0].(*pb.UploadResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseAndRecv indicates an expected call of CloseAndRecv.
+func (mr *MockStreamService_ResumeUploadClientMockRecorder) CloseAndRecv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseAndRecv", reflect.TypeOf((*MockStreamService_ResumeUploadClient)(nil).CloseAndRecv))
+}
+
+// CloseSend mocks base method.
+func (m *MockStreamService_ResumeUploadClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend.
+func (mr *MockStreamService_ResumeUploadClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockStreamService_ResumeUploadClient)(nil).CloseSend))
+}
+
+// Context mocks base method.
+func (m *MockStreamService_ResumeUploadClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockStreamService_ResumeUploadClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockStreamService_ResumeUploadClient)(nil).Context))
+}
+
+// Header mocks base method.
+func (m *MockStreamService_ResumeUploadClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header.
+func (mr *MockStreamService_ResumeUploadClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockStreamService_ResumeUploadClient)(nil).Header))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockStreamService_ResumeUploadClient) RecvMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockStreamService_ResumeUploadClientMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockStreamService_ResumeUploadClient)(nil).RecvMsg), m)
+}
+
+// Send mocks base method.
+func (m *MockStreamService_ResumeUploadClient) Send(arg0 *pb.ResumeUploadRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockStreamService_ResumeUploadClientMockRecorder) Send(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockStreamService_ResumeUploadClient)(nil).Send), arg0)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockStreamService_ResumeUploadClient) SendMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockStreamService_ResumeUploadClientMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockStreamService_ResumeUploadClient)(nil).SendMsg), m)
+}
+
+// Trailer mocks base method.
+func (m *MockStreamService_ResumeUploadClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer.
+func (mr *MockStreamService_ResumeUploadClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockStreamService_ResumeUploadClient)(nil).Trailer))
+}
+
+// MockStreamServiceServer is a mock of StreamServiceServer interface.
+type MockStreamServiceServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockStreamServiceServerMockRecorder
+}
+
+// MockStreamServiceServerMockRecorder is the mock recorder for MockStreamServiceServer.
+type MockStreamServiceServerMockRecorder struct {
+	mock *MockStreamServiceServer
+}
+
+// NewMockStreamServiceServer creates a new mock instance.
+func NewMockStreamServiceServer(ctrl *gomock.Controller) *MockStreamServiceServer {
+	mock := &MockStreamServiceServer{ctrl: ctrl}
+	mock.recorder = &MockStreamServiceServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStreamServiceServer) EXPECT() *MockStreamServiceServerMockRecorder {
+	return m.recorder
+}
+
+// CompleteMultipartUpload mocks base method.
+func (m *MockStreamServiceServer) CompleteMultipartUpload(arg0 context.Context, arg1 *pb.CompleteMultipartUploadRequest) (*pb.UploadResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteMultipartUpload", arg0, arg1)
+	ret0, _ := ret[0].(*pb.UploadResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteMultipartUpload indicates an expected call of CompleteMultipartUpload.
+func (mr *MockStreamServiceServerMockRecorder) CompleteMultipartUpload(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteMultipartUpload", reflect.TypeOf((*MockStreamServiceServer)(nil).CompleteMultipartUpload), arg0, arg1)
+}
+
+// CreateUploadSession mocks base method.
+func (m *MockStreamServiceServer) CreateUploadSession(arg0 context.Context, arg1 *pb.FileMetaData) (*pb.UploadSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUploadSession", arg0, arg1)
+	ret0, _ := ret[0].(*pb.UploadSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUploadSession indicates an expected call of CreateUploadSession.
+func (mr *MockStreamServiceServerMockRecorder) CreateUploadSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUploadSession", reflect.TypeOf((*MockStreamServiceServer)(nil).CreateUploadSession), arg0, arg1)
+}
+
+// DeleteFile mocks base method.
+func (m *MockStreamServiceServer) DeleteFile(arg0 context.Context, arg1 *pb.DeleteFileRequest) (*pb.DeleteFileResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFile", arg0, arg1)
+	ret0, _ := ret[0].(*pb.DeleteFileResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteFile indicates an expected call of DeleteFile.
+func (mr *MockStreamServiceServerMockRecorder) DeleteFile(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFile", reflect.TypeOf((*MockStreamServiceServer)(nil).DeleteFile), arg0, arg1)
+}
+
+// DeleteUploadSession mocks base method.
+func (m *MockStreamServiceServer) DeleteUploadSession(arg0 context.Context, arg1 *pb.UploadSessionRequest) (*pb.DeleteUploadSessionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUploadSession", arg0, arg1)
+	ret0, _ := ret[0].(*pb.DeleteUploadSessionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteUploadSession indicates an expected call of DeleteUploadSession.
+func (mr *MockStreamServiceServerMockRecorder) DeleteUploadSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUploadSession", reflect.TypeOf((*MockStreamServiceServer)(nil).DeleteUploadSession), arg0, arg1)
+}
+
+// Download mocks base method.
+func (m *MockStreamServiceServer) Download(arg0 *pb.DownloadRequest, arg1 pb.StreamService_DownloadServer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Download", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Download indicates an expected call of Download.
+func (mr *MockStreamServiceServerMockRecorder) Download(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Download", reflect.TypeOf((*MockStreamServiceServer)(nil).Download), arg0, arg1)
+}
+
+// GetFile mocks base method.
+func (m *MockStreamServiceServer) GetFile(arg0 context.Context, arg1 *pb.GetFileRequest) (*pb.FileDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFile", arg0, arg1)
+	ret0, _ := ret[0].(*pb.FileDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFile indicates an expected call of GetFile.
+func (mr *MockStreamServiceServerMockRecorder) GetFile(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFile", reflect.TypeOf((*MockStreamServiceServer)(nil).GetFile), arg0, arg1)
+}
+
+// GetUploadSession mocks base method.
+func (m *MockStreamServiceServer) GetUploadSession(arg0 context.Context, arg1 *pb.UploadSessionRequest) (*pb.UploadSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUploadSession", arg0, arg1)
+	ret0, _ := ret[0].(*pb.UploadSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUploadSession indicates an expected call of GetUploadSession.
+func (mr *MockStreamServiceServerMockRecorder) GetUploadSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUploadSession", reflect.TypeOf((*MockStreamServiceServer)(nil).GetUploadSession), arg0, arg1)
+}
+
+// ListFiles mocks base method.
+func (m *MockStreamServiceServer) ListFiles(arg0 context.Context, arg1 *pb.ListFilesRequest) (*pb.ListFilesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFiles", arg0, arg1)
+	ret0, _ := ret[0].(*pb.ListFilesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFiles indicates an expected call of ListFiles.
+func (mr *MockStreamServiceServerMockRecorder) ListFiles(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFiles", reflect.TypeOf((*MockStreamServiceServer)(nil).ListFiles), arg0, arg1)
+}
+
+// RestoreFile mocks base method.
+func (m *MockStreamServiceServer) RestoreFile(arg0 context.Context, arg1 *pb.RestoreFileRequest) (*pb.FileDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreFile", arg0, arg1)
+	ret0, _ := ret[0].(*pb.FileDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreFile indicates an expected call of RestoreFile.
+func (mr *MockStreamServiceServerMockRecorder) RestoreFile(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreFile", reflect.TypeOf((*MockStreamServiceServer)(nil).RestoreFile), arg0, arg1)
+}
+
+// ResumeUpload mocks base method.
+func (m *MockStreamServiceServer) ResumeUpload(arg0 pb.StreamService_ResumeUploadServer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResumeUpload", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResumeUpload indicates an expected call of ResumeUpload.
+func (mr *MockStreamServiceServerMockRecorder) ResumeUpload(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeUpload", reflect.TypeOf((*MockStreamServiceServer)(nil).ResumeUpload), arg0)
+}
+
+// Upload mocks base method.
+func (m *MockStreamServiceServer) Upload(arg0 pb.StreamService_UploadServer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upload", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upload indicates an expected call of Upload.
+func (mr *MockStreamServiceServerMockRecorder) Upload(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upload", reflect.TypeOf((*MockStreamServiceServer)(nil).Upload), arg0)
+}
+
+// UploadWithProgress mocks base method.
+func (m *MockStreamServiceServer) UploadWithProgress(arg0 pb.StreamService_UploadWithProgressServer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadWithProgress", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UploadWithProgress indicates an expected call of UploadWithProgress.
+func (mr *MockStreamServiceServerMockRecorder) UploadWithProgress(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadWithProgress", reflect.TypeOf((*MockStreamServiceServer)(nil).UploadWithProgress), arg0)
+}
+
+// mustEmbedUnimplementedStreamServiceServer mocks base method.
+func (m *MockStreamServiceServer) mustEmbedUnimplementedStreamServiceServer() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "mustEmbedUnimplementedStreamServiceServer")
+}
+
+// mustEmbedUnimplementedStreamServiceServer indicates an expected call of mustEmbedUnimplementedStreamServiceServer.
+func (mr *MockStreamServiceServerMockRecorder) mustEmbedUnimplementedStreamServiceServer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "mustEmbedUnimplementedStreamServiceServer", reflect.TypeOf((*MockStreamServiceServer)(nil).mustEmbedUnimplementedStreamServiceServer))
+}
+
+// MockUnsafeStreamServiceServer is a mock of UnsafeStreamServiceServer interface.
+type MockUnsafeStreamServiceServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockUnsafeStreamServiceServerMockRecorder
+}
+
+// MockUnsafeStreamServiceServerMockRecorder is the mock recorder for MockUnsafeStreamServiceServer.
+type MockUnsafeStreamServiceServerMockRecorder struct {
+	mock *MockUnsafeStreamServiceServer
+}
+
+// NewMockUnsafeStreamServiceServer creates a new mock instance.
+func NewMockUnsafeStreamServiceServer(ctrl *gomock.Controller) *MockUnsafeStreamServiceServer {
+	mock := &MockUnsafeStreamServiceServer{ctrl: ctrl}
+	mock.recorder = &MockUnsafeStreamServiceServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUnsafeStreamServiceServer) EXPECT() *MockUnsafeStreamServiceServerMockRecorder {
+	return m.recorder
+}
+
+// mustEmbedUnimplementedStreamServiceServer mocks base method.
+func (m *MockUnsafeStreamServiceServer) mustEmbedUnimplementedStreamServiceServer() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "mustEmbedUnimplementedStreamServiceServer")
+}
+
+// mustEmbedUnimplementedStreamServiceServer indicates an expected call of mustEmbedUnimplementedStreamServiceServer.
+func (mr *MockUnsafeStreamServiceServerMockRecorder) mustEmbedUnimplementedStreamServiceServer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "mustEmbedUnimplementedStreamServiceServer", reflect.TypeOf((*MockUnsafeStreamServiceServer)(nil).mustEmbedUnimplementedStreamServiceServer))
+}
+
+// MockStreamService_UploadServer is a mock of StreamService_UploadServer interface.
+type MockStreamService_UploadServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockStreamService_UploadServerMockRecorder
+}
+
+// MockStreamService_UploadServerMockRecorder is the mock recorder for MockStreamService_UploadServer.
+type MockStreamService_UploadServerMockRecorder struct {
+	mock *MockStreamService_UploadServer
+}
+
+// NewMockStreamService_UploadServer creates a new mock instance.
+func NewMockStreamService_UploadServer(ctrl *gomock.Controller) *MockStreamService_UploadServer {
+	mock := &MockStreamService_UploadServer{ctrl: ctrl}
+	mock.recorder = &MockStreamService_UploadServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStreamService_UploadServer) EXPECT() *MockStreamService_UploadServerMockRecorder {
+	return m.recorder
+}
+
+// Context mocks base method.
+func (m *MockStreamService_UploadServer) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockStreamService_UploadServerMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockStreamService_UploadServer)(nil).Context))
+}
+
+// Recv mocks base method.
+func (m *MockStreamService_UploadServer) Recv() (*pb.UploadRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*pb.UploadRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockStreamService_UploadServerMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockStreamService_UploadServer)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockStreamService_UploadServer) RecvMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockStreamService_UploadServerMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockStreamService_UploadServer)(nil).RecvMsg), m)
+}
+
+// SendAndClose mocks base method.
+func (m *MockStreamService_UploadServer) SendAndClose(arg0 *pb.UploadResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendAndClose", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendAndClose indicates an expected call of SendAndClose.
+func (mr *MockStreamService_UploadServerMockRecorder) SendAndClose(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendAndClose", reflect.TypeOf((*MockStreamService_UploadServer)(nil).SendAndClose), arg0)
+}
+
+// SendHeader mocks base method.
+func (m *MockStreamService_UploadServer) SendHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader.
+func (mr *MockStreamService_UploadServerMockRecorder) SendHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockStreamService_UploadServer)(nil).SendHeader), arg0)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockStreamService_UploadServer) SendMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockStreamService_UploadServerMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockStreamService_UploadServer)(nil).SendMsg), m)
+}
+
+// SetHeader mocks base method.
+func (m *MockStreamService_UploadServer) SetHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeader indicates an expected call of SetHeader.
+func (mr *MockStreamService_UploadServerMockRecorder) SetHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockStreamService_UploadServer)(nil).SetHeader), arg0)
+}
+
+// SetTrailer mocks base method.
+func (m *MockStreamService_UploadServer) SetTrailer(arg0 metadata.MD) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTrailer", arg0)
+}
+
+// SetTrailer indicates an expected call of SetTrailer.
+func (mr *MockStreamService_UploadServerMockRecorder) SetTrailer(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockStreamService_UploadServer)(nil).SetTrailer), arg0)
+}
+
+// MockStreamService_UploadWithProgressServer is a mock of StreamService_UploadWithProgressServer interface.
+type MockStreamService_UploadWithProgressServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockStreamService_UploadWithProgressServerMockRecorder
+}
+
+// MockStreamService_UploadWithProgressServerMockRecorder is the mock recorder for MockStreamService_UploadWithProgressServer.
+type MockStreamService_UploadWithProgressServerMockRecorder struct {
+	mock *MockStreamService_UploadWithProgressServer
+}
+
+// NewMockStreamService_UploadWithProgressServer creates a new mock instance.
+func NewMockStreamService_UploadWithProgressServer(ctrl *gomock.Controller) *MockStreamService_UploadWithProgressServer {
+	mock := &MockStreamService_UploadWithProgressServer{ctrl: ctrl}
+	mock.recorder = &MockStreamService_UploadWithProgressServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStreamService_UploadWithProgressServer) EXPECT() *MockStreamService_UploadWithProgressServerMockRecorder {
+	return m.recorder
+}
+
+// Context mocks base method.
+func (m *MockStreamService_UploadWithProgressServer) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockStreamService_UploadWithProgressServerMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockStreamService_UploadWithProgressServer)(nil).Context))
+}
+
+// Recv mocks base method.
+func (m *MockStreamService_UploadWithProgressServer) Recv() (*pb.UploadRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*pb.UploadRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockStreamService_UploadWithProgressServerMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockStreamService_UploadWithProgressServer)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockStreamService_UploadWithProgressServer) RecvMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockStreamService_UploadWithProgressServerMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockStreamService_UploadWithProgressServer)(nil).RecvMsg), m)
+}
+
+// Send mocks base method.
+func (m *MockStreamService_UploadWithProgressServer) Send(arg0 *pb.UploadProgress) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockStreamService_UploadWithProgressServerMockRecorder) Send(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockStreamService_UploadWithProgressServer)(nil).Send), arg0)
+}
+
+// SendHeader mocks base method.
+func (m *MockStreamService_UploadWithProgressServer) SendHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader.
+func (mr *MockStreamService_UploadWithProgressServerMockRecorder) SendHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockStreamService_UploadWithProgressServer)(nil).SendHeader), arg0)
 }
 
 // SendMsg mocks base method.
-func (m_2 *MockStreamService_UploadServer) SendMsg(m interface{}) error {
+func (m_2 *MockStreamService_UploadWithProgressServer) SendMsg(m interface{}) error {
 	m_2.ctrl.T.Helper()
 	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
 	ret0, _ := ret[0].(error)
@@ -381,13 +1294,13 @@ func (m_2 *MockStreamService_UploadServer) SendMsg(m interface{}) error {
 }
 
 // SendMsg indicates an expected call of SendMsg.
-func (mr *MockStreamService_UploadServerMockRecorder) SendMsg(m interface{}) *gomock.Call {
+func (mr *MockStreamService_UploadWithProgressServerMockRecorder) SendMsg(m interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockStreamService_UploadServer)(nil).SendMsg), m)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockStreamService_UploadWithProgressServer)(nil).SendMsg), m)
 }
 
 // SetHeader mocks base method.
-func (m *MockStreamService_UploadServer) SetHeader(arg0 metadata.MD) error {
+func (m *MockStreamService_UploadWithProgressServer) SetHeader(arg0 metadata.MD) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "SetHeader", arg0)
 	ret0, _ := ret[0].(error)
@@ -395,19 +1308,272 @@ func (m *MockStreamService_UploadServer) SetHeader(arg0 metadata.MD) error {
 }
 
 // SetHeader indicates an expected call of SetHeader.
-func (mr *MockStreamService_UploadServerMockRecorder) SetHeader(arg0 interface{}) *gomock.Call {
+func (mr *MockStreamService_UploadWithProgressServerMockRecorder) SetHeader(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockStreamService_UploadServer)(nil).SetHeader), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockStreamService_UploadWithProgressServer)(nil).SetHeader), arg0)
 }
 
 // SetTrailer mocks base method.
-func (m *MockStreamService_UploadServer) SetTrailer(arg0 metadata.MD) {
+func (m *MockStreamService_UploadWithProgressServer) SetTrailer(arg0 metadata.MD) {
 	m.ctrl.T.Helper()
 	m.ctrl.Call(m, "SetTrailer", arg0)
 }
 
 // SetTrailer indicates an expected call of SetTrailer.
-func (mr *MockStreamService_UploadServerMockRecorder) SetTrailer(arg0 interface{}) *gomock.Call {
+func (mr *MockStreamService_UploadWithProgressServerMockRecorder) SetTrailer(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockStreamService_UploadServer)(nil).SetTrailer), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockStreamService_UploadWithProgressServer)(nil).SetTrailer), arg0)
+}
+
+// MockStreamService_DownloadServer is a mock of StreamService_DownloadServer interface.
+type MockStreamService_DownloadServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockStreamService_DownloadServerMockRecorder
+}
+
+// MockStreamService_DownloadServerMockRecorder is the mock recorder for MockStreamService_DownloadServer.
+type MockStreamService_DownloadServerMockRecorder struct {
+	mock *MockStreamService_DownloadServer
+}
+
+// NewMockStreamService_DownloadServer creates a new mock instance.
+func NewMockStreamService_DownloadServer(ctrl *gomock.Controller) *MockStreamService_DownloadServer {
+	mock := &MockStreamService_DownloadServer{ctrl: ctrl}
+	mock.recorder = &MockStreamService_DownloadServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStreamService_DownloadServer) EXPECT() *MockStreamService_DownloadServerMockRecorder {
+	return m.recorder
+}
+
+// Context mocks base method.
+func (m *MockStreamService_DownloadServer) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockStreamService_DownloadServerMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockStreamService_DownloadServer)(nil).Context))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockStreamService_DownloadServer) RecvMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockStreamService_DownloadServerMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockStreamService_DownloadServer)(nil).RecvMsg), m)
+}
+
+// Send mocks base method.
+func (m *MockStreamService_DownloadServer) Send(arg0 *pb.DownloadResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockStreamService_DownloadServerMockRecorder) Send(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockStreamService_DownloadServer)(nil).Send), arg0)
+}
+
+// SendHeader mocks base method.
+func (m *MockStreamService_DownloadServer) SendHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader.
+func (mr *MockStreamService_DownloadServerMockRecorder) SendHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockStreamService_DownloadServer)(nil).SendHeader), arg0)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockStreamService_DownloadServer) SendMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockStreamService_DownloadServerMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockStreamService_DownloadServer)(nil).SendMsg), m)
+}
+
+// SetHeader mocks base method.
+func (m *MockStreamService_DownloadServer) SetHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeader indicates an expected call of SetHeader.
+func (mr *MockStreamService_DownloadServerMockRecorder) SetHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockStreamService_DownloadServer)(nil).SetHeader), arg0)
+}
+
+// SetTrailer mocks base method.
+func (m *MockStreamService_DownloadServer) SetTrailer(arg0 metadata.MD) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTrailer", arg0)
+}
+
+// SetTrailer indicates an expected call of SetTrailer.
+func (mr *MockStreamService_DownloadServerMockRecorder) SetTrailer(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockStreamService_DownloadServer)(nil).SetTrailer), arg0)
+}
+
+// MockStreamService_ResumeUploadServer is a mock of StreamService_ResumeUploadServer interface.
+type MockStreamService_ResumeUploadServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockStreamService_ResumeUploadServerMockRecorder
+}
+
+// MockStreamService_ResumeUploadServerMockRecorder is the mock recorder for MockStreamService_ResumeUploadServer.
+type MockStreamService_ResumeUploadServerMockRecorder struct {
+	mock *MockStreamService_ResumeUploadServer
+}
+
+// NewMockStreamService_ResumeUploadServer creates a new mock instance.
+func NewMockStreamService_ResumeUploadServer(ctrl *gomock.Controller) *MockStreamService_ResumeUploadServer {
+	mock := &MockStreamService_ResumeUploadServer{ctrl: ctrl}
+	mock.recorder = &MockStreamService_ResumeUploadServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStreamService_ResumeUploadServer) EXPECT() *MockStreamService_ResumeUploadServerMockRecorder {
+	return m.recorder
+}
+
+// Context mocks base method.
+func (m *MockStreamService_ResumeUploadServer) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockStreamService_ResumeUploadServerMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockStreamService_ResumeUploadServer)(nil).Context))
+}
+
+// Recv mocks base method.
+func (m *MockStreamService_ResumeUploadServer) Recv() (*pb.ResumeUploadRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*pb.ResumeUploadRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockStreamService_ResumeUploadServerMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockStreamService_ResumeUploadServer)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockStreamService_ResumeUploadServer) RecvMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockStreamService_ResumeUploadServerMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockStreamService_ResumeUploadServer)(nil).RecvMsg), m)
+}
+
+// SendAndClose mocks base method.
+func (m *MockStreamService_ResumeUploadServer) SendAndClose(arg0 *pb.UploadResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendAndClose", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendAndClose indicates an expected call of SendAndClose.
+func (mr *MockStreamService_ResumeUploadServerMockRecorder) SendAndClose(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendAndClose", reflect.TypeOf((*MockStreamService_ResumeUploadServer)(nil).SendAndClose), arg0)
+}
+
+// SendHeader mocks base method.
+func (m *MockStreamService_ResumeUploadServer) SendHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader.
+func (mr *MockStreamService_ResumeUploadServerMockRecorder) SendHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockStreamService_ResumeUploadServer)(nil).SendHeader), arg0)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockStreamService_ResumeUploadServer) SendMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockStreamService_ResumeUploadServerMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockStreamService_ResumeUploadServer)(nil).SendMsg), m)
+}
+
+// SetHeader mocks base method.
+func (m *MockStreamService_ResumeUploadServer) SetHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeader indicates an expected call of SetHeader.
+func (mr *MockStreamService_ResumeUploadServerMockRecorder) SetHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockStreamService_ResumeUploadServer)(nil).SetHeader), arg0)
+}
+
+// SetTrailer mocks base method.
+func (m *MockStreamService_ResumeUploadServer) SetTrailer(arg0 metadata.MD) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTrailer", arg0)
+}
+
+// SetTrailer indicates an expected call of SetTrailer.
+func (mr *MockStreamService_ResumeUploadServerMockRecorder) SetTrailer(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockStreamService_ResumeUploadServer)(nil).SetTrailer), arg0)
 }
@@ -6,8 +6,11 @@ package mock_usecase
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
 	request "stream-service/pkg/models/request"
+	response "stream-service/pkg/models/response"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -35,16 +38,227 @@ func (m *MockStreamUseCase) EXPECT() *MockStreamUseCaseMockRecorder {
 	return m.recorder
 }
 
+// CheckUploadSessionOffset mocks base method.
+func (m *MockStreamUseCase) CheckUploadSessionOffset(ctx context.Context, sessionID string, offset int64) (response.UploadSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckUploadSessionOffset", ctx, sessionID, offset)
+	ret0, _ := ret[0].(response.UploadSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckUploadSessionOffset indicates an expected call of CheckUploadSessionOffset.
+func (mr *MockStreamUseCaseMockRecorder) CheckUploadSessionOffset(ctx, sessionID, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckUploadSessionOffset", reflect.TypeOf((*MockStreamUseCase)(nil).CheckUploadSessionOffset), ctx, sessionID, offset)
+}
+
+// CompleteMultipartUpload mocks base method.
+func (m *MockStreamUseCase) CompleteMultipartUpload(ctx context.Context, uploadID string, parts []request.MultipartPart) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteMultipartUpload", ctx, uploadID, parts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteMultipartUpload indicates an expected call of CompleteMultipartUpload.
+func (mr *MockStreamUseCaseMockRecorder) CompleteMultipartUpload(ctx, uploadID, parts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteMultipartUpload", reflect.TypeOf((*MockStreamUseCase)(nil).CompleteMultipartUpload), ctx, uploadID, parts)
+}
+
+// CreateUploadSession mocks base method.
+func (m *MockStreamUseCase) CreateUploadSession(ctx context.Context, details request.FileDetails) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUploadSession", ctx, details)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUploadSession indicates an expected call of CreateUploadSession.
+func (mr *MockStreamUseCaseMockRecorder) CreateUploadSession(ctx, details interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUploadSession", reflect.TypeOf((*MockStreamUseCase)(nil).CreateUploadSession), ctx, details)
+}
+
+// DeleteFile mocks base method.
+func (m *MockStreamUseCase) DeleteFile(ctx context.Context, id string) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFile", ctx, id)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteFile indicates an expected call of DeleteFile.
+func (mr *MockStreamUseCaseMockRecorder) DeleteFile(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFile", reflect.TypeOf((*MockStreamUseCase)(nil).DeleteFile), ctx, id)
+}
+
+// DeleteUploadSession mocks base method.
+func (m *MockStreamUseCase) DeleteUploadSession(ctx context.Context, sessionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUploadSession", ctx, sessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUploadSession indicates an expected call of DeleteUploadSession.
+func (mr *MockStreamUseCaseMockRecorder) DeleteUploadSession(ctx, sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUploadSession", reflect.TypeOf((*MockStreamUseCase)(nil).DeleteUploadSession), ctx, sessionID)
+}
+
+// DownloadFile mocks base method.
+func (m *MockStreamUseCase) DownloadFile(ctx context.Context, id string) (response.FileDetails, io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadFile", ctx, id)
+	ret0, _ := ret[0].(response.FileDetails)
+	ret1, _ := ret[1].(io.ReadCloser)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// DownloadFile indicates an expected call of DownloadFile.
+func (mr *MockStreamUseCaseMockRecorder) DownloadFile(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadFile", reflect.TypeOf((*MockStreamUseCase)(nil).DownloadFile), ctx, id)
+}
+
+// GetFileDetails mocks base method.
+func (m *MockStreamUseCase) GetFileDetails(ctx context.Context, id string) (response.FileDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFileDetails", ctx, id)
+	ret0, _ := ret[0].(response.FileDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFileDetails indicates an expected call of GetFileDetails.
+func (mr *MockStreamUseCaseMockRecorder) GetFileDetails(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileDetails", reflect.TypeOf((*MockStreamUseCase)(nil).GetFileDetails), ctx, id)
+}
+
+// GetUploadSession mocks base method.
+func (m *MockStreamUseCase) GetUploadSession(ctx context.Context, sessionID string) (response.UploadSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUploadSession", ctx, sessionID)
+	ret0, _ := ret[0].(response.UploadSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUploadSession indicates an expected call of GetUploadSession.
+func (mr *MockStreamUseCaseMockRecorder) GetUploadSession(ctx, sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUploadSession", reflect.TypeOf((*MockStreamUseCase)(nil).GetUploadSession), ctx, sessionID)
+}
+
+// ListFiles mocks base method.
+func (m *MockStreamUseCase) ListFiles(ctx context.Context, req request.ListFiles) (response.FileList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFiles", ctx, req)
+	ret0, _ := ret[0].(response.FileList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFiles indicates an expected call of ListFiles.
+func (mr *MockStreamUseCaseMockRecorder) ListFiles(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFiles", reflect.TypeOf((*MockStreamUseCase)(nil).ListFiles), ctx, req)
+}
+
+// PurgeDeletedFiles mocks base method.
+func (m *MockStreamUseCase) PurgeDeletedFiles(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeletedFiles", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeletedFiles indicates an expected call of PurgeDeletedFiles.
+func (mr *MockStreamUseCaseMockRecorder) PurgeDeletedFiles(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeletedFiles", reflect.TypeOf((*MockStreamUseCase)(nil).PurgeDeletedFiles), ctx)
+}
+
+// PurgeExpiredUploadSessions mocks base method.
+func (m *MockStreamUseCase) PurgeExpiredUploadSessions(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeExpiredUploadSessions", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeExpiredUploadSessions indicates an expected call of PurgeExpiredUploadSessions.
+func (mr *MockStreamUseCaseMockRecorder) PurgeExpiredUploadSessions(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeExpiredUploadSessions", reflect.TypeOf((*MockStreamUseCase)(nil).PurgeExpiredUploadSessions), ctx)
+}
+
+// Reconcile mocks base method.
+func (m *MockStreamUseCase) Reconcile(ctx context.Context, repair bool) (response.Reconciliation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reconcile", ctx, repair)
+	ret0, _ := ret[0].(response.Reconciliation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reconcile indicates an expected call of Reconcile.
+func (mr *MockStreamUseCaseMockRecorder) Reconcile(ctx, repair interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reconcile", reflect.TypeOf((*MockStreamUseCase)(nil).Reconcile), ctx, repair)
+}
+
+// RecoverUploads mocks base method.
+func (m *MockStreamUseCase) RecoverUploads(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecoverUploads", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecoverUploads indicates an expected call of RecoverUploads.
+func (mr *MockStreamUseCaseMockRecorder) RecoverUploads(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecoverUploads", reflect.TypeOf((*MockStreamUseCase)(nil).RecoverUploads), ctx)
+}
+
+// RestoreFile mocks base method.
+func (m *MockStreamUseCase) RestoreFile(ctx context.Context, id string) (response.FileDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreFile", ctx, id)
+	ret0, _ := ret[0].(response.FileDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreFile indicates an expected call of RestoreFile.
+func (mr *MockStreamUseCaseMockRecorder) RestoreFile(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreFile", reflect.TypeOf((*MockStreamUseCase)(nil).RestoreFile), ctx, id)
+}
+
 // UploadFileAsStream mocks base method.
-func (m *MockStreamUseCase) UploadFileAsStream(ctx context.Context, id string, dataChan <-chan []byte, errChan chan error) {
+func (m *MockStreamUseCase) UploadFileAsStream(ctx context.Context, id string, expected request.Checksum, stream io.Reader, progress func(response.UploadProgress)) error {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "UploadFileAsStream", ctx, id, dataChan, errChan)
+	ret := m.ctrl.Call(m, "UploadFileAsStream", ctx, id, expected, stream, progress)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // UploadFileAsStream indicates an expected call of UploadFileAsStream.
-func (mr *MockStreamUseCaseMockRecorder) UploadFileAsStream(ctx, id, dataChan, errChan interface{}) *gomock.Call {
+func (mr *MockStreamUseCaseMockRecorder) UploadFileAsStream(ctx, id, expected, stream, progress interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadFileAsStream", reflect.TypeOf((*MockStreamUseCase)(nil).UploadFileAsStream), ctx, id, dataChan, errChan)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadFileAsStream", reflect.TypeOf((*MockStreamUseCase)(nil).UploadFileAsStream), ctx, id, expected, stream, progress)
 }
 
 // UploadFileDetails mocks base method.
@@ -61,3 +275,47 @@ func (mr *MockStreamUseCaseMockRecorder) UploadFileDetails(ctx, details interfac
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadFileDetails", reflect.TypeOf((*MockStreamUseCase)(nil).UploadFileDetails), ctx, details)
 }
+
+// UploadMultipartPartAsStream mocks base method.
+func (m *MockStreamUseCase) UploadMultipartPartAsStream(ctx context.Context, uploadID string, number int, size *int64, stream io.Reader) (response.MultipartPart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadMultipartPartAsStream", ctx, uploadID, number, size, stream)
+	ret0, _ := ret[0].(response.MultipartPart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadMultipartPartAsStream indicates an expected call of UploadMultipartPartAsStream.
+func (mr *MockStreamUseCaseMockRecorder) UploadMultipartPartAsStream(ctx, uploadID, number, size, stream interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadMultipartPartAsStream", reflect.TypeOf((*MockStreamUseCase)(nil).UploadMultipartPartAsStream), ctx, uploadID, number, size, stream)
+}
+
+// UploadSessionAsStream mocks base method.
+func (m *MockStreamUseCase) UploadSessionAsStream(ctx context.Context, sessionID string, offset int64, stream io.Reader) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadSessionAsStream", ctx, sessionID, offset, stream)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UploadSessionAsStream indicates an expected call of UploadSessionAsStream.
+func (mr *MockStreamUseCaseMockRecorder) UploadSessionAsStream(ctx, sessionID, offset, stream interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadSessionAsStream", reflect.TypeOf((*MockStreamUseCase)(nil).UploadSessionAsStream), ctx, sessionID, offset, stream)
+}
+
+// UploadSessionPartAsStream mocks base method.
+func (m *MockStreamUseCase) UploadSessionPartAsStream(ctx context.Context, sessionID string, offset int64, checksum *request.PartChecksum, stream io.Reader) (response.UploadSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadSessionPartAsStream", ctx, sessionID, offset, checksum, stream)
+	ret0, _ := ret[0].(response.UploadSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadSessionPartAsStream indicates an expected call of UploadSessionPartAsStream.
+func (mr *MockStreamUseCaseMockRecorder) UploadSessionPartAsStream(ctx, sessionID, offset, checksum, stream interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadSessionPartAsStream", reflect.TypeOf((*MockStreamUseCase)(nil).UploadSessionPartAsStream), ctx, sessionID, offset, checksum, stream)
+}
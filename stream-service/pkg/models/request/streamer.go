@@ -1,6 +1,68 @@
 package request
 
+import "time"
+
 type FileDetails struct {
 	Name        string `validator:"required,min=3"`
 	ContentType string `validator:"required"`
+	Checksum    Checksum
+}
+
+// expected size and checksum of the file to verify after upload
+type Checksum struct {
+	Size   *int64  // declared total size (nil to skip)
+	SHA256 string  // sha256 digest as hex (empty to skip)
+	CRC32C *uint32 // crc32c (castagnoli) checksum (nil to skip)
+}
+
+// checksum of a part of the file uploaded on an upload session
+type PartChecksum struct {
+	Algorithm string // md5, sha1 or sha256
+	Digest    []byte
+}
+
+// part of a multipart upload to combine as the file
+type MultipartPart struct {
+	Number int
+	Size   int64
+	SHA256 string // sha256 digest as hex (empty to skip)
+}
+
+// fields to sort the file list
+const (
+	SortByUploadedAt = "uploaded_at"
+	SortByName       = "name"
+)
+
+type ListFiles struct {
+	Cursor         string
+	Limit          int
+	ContentType    string
+	NamePrefix     string
+	UploadedAfter  time.Time
+	UploadedBefore time.Time
+	SortBy         string
+	Descending     bool
+	Deleted        bool // list the deleted files (trash)
+}
+
+// filter to find file details from database
+type FileFilter struct {
+	Owner          string // only the files of the owner
+	ContentType    string
+	NamePrefix     string
+	UploadedAfter  time.Time
+	UploadedBefore time.Time
+	SortBy         string
+	Descending     bool
+	Deleted        bool
+	Limit          int
+	After          *FileCursor // find the files after this cursor
+}
+
+// position of a file on the sorted file list
+type FileCursor struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	UploadedAt time.Time `json:"uploaded_at"`
 }
@@ -0,0 +1,65 @@
+package response
+
+import "time"
+
+type FileDetails struct {
+	ID            string
+	Owner         string
+	Name          string
+	ContentType   string
+	UploadedAt    time.Time
+	DeletedAt     time.Time // zero if the file not deleted
+	Size          int64
+	SHA256        string
+	CRC32C        uint32
+	Status        string
+	CompletedAt   time.Time // zero if the upload not completed
+	FailureReason string
+}
+
+type UploadSession struct {
+	ID        string
+	Offset    int64
+	Completed bool
+	Size      *int64    // declared total size (nil if not declared)
+	ExpiresAt time.Time // zero if completed or not expire
+}
+
+// stored part of a multipart upload
+type MultipartPart struct {
+	Number int
+	Size   int64
+	SHA256 string
+	CRC32C uint32
+}
+
+type UploadProgress struct {
+	Written   int64
+	Completed bool
+}
+
+type FileList struct {
+	Files      []FileDetails
+	NextCursor string
+}
+
+// kinds of mismatch between the storage and database
+const (
+	MismatchMissingFile     = "missing_file"     // completed file without the file on storage
+	MismatchSizeMismatch    = "size_mismatch"    // stored file size not matching the completed file size
+	MismatchLeftoverObjects = "leftover_objects" // objects on storage of a not completed file
+	MismatchOrphanObjects   = "orphan_objects"   // objects on storage without file details or upload session
+)
+
+type Mismatch struct {
+	Kind     string
+	FileID   string
+	Detail   string
+	Repaired bool
+}
+
+type Reconciliation struct {
+	CheckedFiles   int // count of file details checked
+	CheckedObjects int // count of objects on storage checked
+	Mismatches     []Mismatch
+}
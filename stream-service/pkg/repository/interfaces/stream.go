@@ -3,8 +3,36 @@ package interfaces
 import (
 	"context"
 	"stream-service/pkg/domain"
+	"stream-service/pkg/models/request"
+	"time"
 )
 
 type StreamRepository interface {
 	SaveFileDetails(ctx context.Context, details domain.FileDetails) error
+	FindFileDetailsByID(ctx context.Context, id string) (domain.FileDetails, error)
+	UpdateFileDetailsStatus(ctx context.Context, id string, status domain.UploadStatus, failureReason string) error
+	// complete the file upload with the size and checksum
+	CompleteFileDetails(ctx context.Context, details domain.FileDetails) error
+	FindAllFileDetails(ctx context.Context, filter request.FileFilter) ([]domain.FileDetails, error)
+	SoftDeleteFileDetails(ctx context.Context, id string, deletedAt time.Time) error
+	RestoreFileDetails(ctx context.Context, id string) error
+	FindDeletedFileDetails(ctx context.Context, deletedBefore time.Time, limit int) ([]domain.FileDetails, error)
+	DeleteFileDetails(ctx context.Context, id string) error
+	// find all the file details (including deleted and not completed) after the id ordered by id
+	FindFileDetailsAfterID(ctx context.Context, afterID string, limit int) ([]domain.FileDetails, error)
+
+	SaveUploadSession(ctx context.Context, session domain.UploadSession) error
+	FindUploadSessionByID(ctx context.Context, id string) (domain.UploadSession, error)
+	UpdateUploadSessionOffset(ctx context.Context, id string, offset int64) error
+	// claim the not completed session to upload from the committed offset until the time,
+	// returns false if the offset not committed or the session claimed by another upload
+	ClaimUploadSession(ctx context.Context, id string, offset int64, until time.Time) (bool, error)
+	// extend the claim of the upload in progress until the time
+	ExtendUploadSessionClaim(ctx context.Context, id string, until time.Time) error
+	// release the claim of the upload to resume by another upload
+	ReleaseUploadSession(ctx context.Context, id string) error
+	// find the not completed upload sessions which last updated before the time
+	FindExpiredUploadSessions(ctx context.Context, updatedBefore time.Time, limit int) ([]domain.UploadSession, error)
+	// complete the session and save the file details with the size and checksum
+	CompleteUploadSession(ctx context.Context, details domain.FileDetails) error
 }
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"stream-service/pkg/logger"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// logger of the database queries, the queries logged on debug level and the failed queries on error level
+type queryLogger struct {
+	logger *slog.Logger
+}
+
+func (l queryLogger) LogMode(gormlogger.LogLevel) gormlogger.Interface {
+	return l
+}
+
+func (l queryLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	l.logger.InfoContext(ctx, msg, "args", args)
+}
+
+func (l queryLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	l.logger.WarnContext(ctx, msg, "args", args)
+}
+
+func (l queryLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	l.logger.ErrorContext(ctx, msg, "args", args)
+}
+
+func (l queryLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+
+	level := slog.LevelDebug
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		level = slog.LevelError
+	}
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+
+	sql, rows := fc()
+	attrs := []any{
+		slog.String("sql", sql),
+		slog.Int64("rows", rows),
+		slog.Duration("duration", time.Since(begin)),
+	}
+	if level == slog.LevelError {
+		attrs = append(attrs, slog.Any(logger.KeyError, err))
+	}
+
+	l.logger.Log(ctx, level, "database query", attrs...)
+}
@@ -2,8 +2,13 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
 	"stream-service/pkg/domain"
+	"stream-service/pkg/models/request"
 	"stream-service/pkg/repository/interfaces"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -12,15 +17,237 @@ type streamRepo struct {
 	db *gorm.DB
 }
 
-func NewStreamRepository(db *gorm.DB) interfaces.StreamRepository {
+func NewStreamRepository(db *gorm.DB, logger *slog.Logger) interfaces.StreamRepository {
 
+	// the queries logged with the request of the context
 	return &streamRepo{
-		db: db,
+		db: db.Session(&gorm.Session{Logger: queryLogger{logger: logger}}),
 	}
 }
 
 func (s *streamRepo) SaveFileDetails(ctx context.Context, details domain.FileDetails) error {
 
-	query := `INSERT INTO file_details (id, name, content_type, uploaded_at) VALUES($1, $2, $3, $4)`
-	return s.db.Exec(query, details.ID, details.Name, details.ContentType, details.UploadedAt).Error
+	query := `INSERT INTO file_details (id, owner, name, content_type, uploaded_at, status) VALUES($1, $2, $3, $4, $5, $6)`
+	return s.db.WithContext(ctx).Exec(query, details.ID, details.Owner, details.Name, details.ContentType, details.UploadedAt,
+		details.Status).Error
+}
+
+func (s *streamRepo) FindFileDetailsByID(ctx context.Context, id string) (details domain.FileDetails, err error) {
+
+	query := `SELECT id, owner, name, content_type, uploaded_at, deleted_at, size, sha256, crc32c,
+	status, completed_at, failure_reason FROM file_details WHERE id = $1`
+	err = s.db.WithContext(ctx).Raw(query, id).Scan(&details).Error
+
+	return
+}
+
+func (s *streamRepo) UpdateFileDetailsStatus(ctx context.Context, id string, status domain.UploadStatus,
+	failureReason string) error {
+
+	query := `UPDATE file_details SET status = $1, failure_reason = $2 WHERE id = $3`
+	return s.db.WithContext(ctx).Exec(query, status, failureReason, id).Error
+}
+
+func (s *streamRepo) CompleteFileDetails(ctx context.Context, details domain.FileDetails) error {
+
+	query := `UPDATE file_details SET size = $1, sha256 = $2, crc32c = $3, status = $4, completed_at = $5 WHERE id = $6`
+	return s.db.WithContext(ctx).Exec(query, details.Size, details.SHA256, details.CRC32C, domain.UploadStatusCompleted,
+		details.CompletedAt, details.ID).Error
+}
+
+func (s *streamRepo) FindAllFileDetails(ctx context.Context, filter request.FileFilter) (files []domain.FileDetails, err error) {
+
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	// add the condition with the arg as next placeholder
+	addCondition := func(condition string, arg ...interface{}) {
+		placeholders := make([]interface{}, len(arg))
+		for i := range arg {
+			args = append(args, arg[i])
+			placeholders[i] = len(args)
+		}
+		conditions = append(conditions, fmt.Sprintf(condition, placeholders...))
+	}
+
+	// only the completed uploads are visible on list
+	addCondition("status = $%d", domain.UploadStatusCompleted)
+	addCondition("owner = $%d", filter.Owner)
+
+	// list either the deleted files (trash) or active files
+	if filter.Deleted {
+		conditions = append(conditions, "deleted_at IS NOT NULL")
+	} else {
+		conditions = append(conditions, "deleted_at IS NULL")
+	}
+
+	if filter.ContentType != "" {
+		addCondition("content_type = $%d", filter.ContentType)
+	}
+	if filter.NamePrefix != "" {
+		addCondition("name LIKE $%d", escapeLike(filter.NamePrefix)+"%")
+	}
+	if !filter.UploadedAfter.IsZero() {
+		addCondition("uploaded_at >= $%d", filter.UploadedAfter)
+	}
+	if !filter.UploadedBefore.IsZero() {
+		addCondition("uploaded_at < $%d", filter.UploadedBefore)
+	}
+
+	// only allow the known columns to sort
+	sortColumn := request.SortByUploadedAt
+	if filter.SortBy == request.SortByName {
+		sortColumn = request.SortByName
+	}
+	order, compare := "ASC", ">"
+	if filter.Descending {
+		order, compare = "DESC", "<"
+	}
+
+	// find the files after the cursor using the sort column and id
+	if filter.After != nil {
+		var sortValue interface{} = filter.After.UploadedAt
+		if sortColumn == request.SortByName {
+			sortValue = filter.After.Name
+		}
+		addCondition("("+sortColumn+", id) "+compare+" ($%d, $%d)", sortValue, filter.After.ID)
+	}
+
+	query := `SELECT id, owner, name, content_type, uploaded_at, deleted_at, size, sha256, crc32c,
+	status, completed_at, failure_reason FROM file_details WHERE ` +
+		strings.Join(conditions, " AND ")
+	query += fmt.Sprintf(" ORDER BY %s %s, id %s LIMIT %d", sortColumn, order, order, filter.Limit)
+
+	err = s.db.WithContext(ctx).Raw(query, args...).Scan(&files).Error
+
+	return
+}
+
+func (s *streamRepo) SoftDeleteFileDetails(ctx context.Context, id string, deletedAt time.Time) error {
+
+	query := `UPDATE file_details SET deleted_at = $1 WHERE id = $2`
+	return s.db.WithContext(ctx).Exec(query, deletedAt, id).Error
+}
+
+func (s *streamRepo) RestoreFileDetails(ctx context.Context, id string) error {
+
+	query := `UPDATE file_details SET deleted_at = NULL WHERE id = $1`
+	return s.db.WithContext(ctx).Exec(query, id).Error
+}
+
+func (s *streamRepo) FindDeletedFileDetails(ctx context.Context, deletedBefore time.Time,
+	limit int) (files []domain.FileDetails, err error) {
+
+	query := `SELECT id, owner, name, content_type, uploaded_at, deleted_at, size, sha256, crc32c,
+	status, completed_at, failure_reason FROM file_details
+	WHERE deleted_at IS NOT NULL AND deleted_at < $1 ORDER BY deleted_at LIMIT $2`
+	err = s.db.WithContext(ctx).Raw(query, deletedBefore, limit).Scan(&files).Error
+
+	return
+}
+
+func (s *streamRepo) FindFileDetailsAfterID(ctx context.Context, afterID string,
+	limit int) (files []domain.FileDetails, err error) {
+
+	query := `SELECT id, owner, name, content_type, uploaded_at, deleted_at, size, sha256, crc32c,
+	status, completed_at, failure_reason FROM file_details WHERE id > $1 ORDER BY id LIMIT $2`
+	err = s.db.WithContext(ctx).Raw(query, afterID, limit).Scan(&files).Error
+
+	return
+}
+
+func (s *streamRepo) DeleteFileDetails(ctx context.Context, id string) error {
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+
+		// remove the upload session of the file if it uploaded through session
+		query := `DELETE FROM upload_sessions WHERE id = $1`
+		if err := tx.Exec(query, id).Error; err != nil {
+			return err
+		}
+
+		query = `DELETE FROM file_details WHERE id = $1`
+		return tx.Exec(query, id).Error
+	})
+}
+
+func (s *streamRepo) SaveUploadSession(ctx context.Context, session domain.UploadSession) error {
+
+	query := `INSERT INTO upload_sessions (id, owner, name, content_type, committed_offset, completed, created_at,
+	updated_at, expected_sha256, expected_crc32c, expected_size) VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+	return s.db.WithContext(ctx).Exec(query, session.ID, session.Owner, session.Name, session.ContentType, session.CommittedOffset,
+		session.Completed, session.CreatedAt, session.UpdatedAt, session.ExpectedSHA256, session.ExpectedCRC32C,
+		session.ExpectedSize).Error
+}
+
+func (s *streamRepo) FindUploadSessionByID(ctx context.Context, id string) (session domain.UploadSession, err error) {
+
+	query := `SELECT id, owner, name, content_type, committed_offset, completed, created_at, updated_at,
+	expected_sha256, expected_crc32c, expected_size FROM upload_sessions WHERE id = $1`
+	err = s.db.WithContext(ctx).Raw(query, id).Scan(&session).Error
+
+	return
+}
+
+func (s *streamRepo) UpdateUploadSessionOffset(ctx context.Context, id string, offset int64) error {
+
+	query := `UPDATE upload_sessions SET committed_offset = $1, updated_at = $2 WHERE id = $3`
+	return s.db.WithContext(ctx).Exec(query, offset, time.Now(), id).Error
+}
+
+func (s *streamRepo) ClaimUploadSession(ctx context.Context, id string, offset int64,
+	until time.Time) (bool, error) {
+
+	now := time.Now()
+	query := `UPDATE upload_sessions SET claimed_until = $1, updated_at = $2 WHERE id = $3 AND committed_offset = $4
+	AND completed = false AND (claimed_until IS NULL OR claimed_until < $2)`
+	result := s.db.WithContext(ctx).Exec(query, until, now, id, offset)
+
+	return result.RowsAffected == 1, result.Error
+}
+
+func (s *streamRepo) ExtendUploadSessionClaim(ctx context.Context, id string, until time.Time) error {
+
+	query := `UPDATE upload_sessions SET claimed_until = $1, updated_at = $2 WHERE id = $3`
+	return s.db.WithContext(ctx).Exec(query, until, time.Now(), id).Error
+}
+
+func (s *streamRepo) ReleaseUploadSession(ctx context.Context, id string) error {
+
+	query := `UPDATE upload_sessions SET claimed_until = NULL WHERE id = $1`
+	return s.db.WithContext(ctx).Exec(query, id).Error
+}
+
+func (s *streamRepo) FindExpiredUploadSessions(ctx context.Context, updatedBefore time.Time,
+	limit int) (sessions []domain.UploadSession, err error) {
+
+	query := `SELECT id, owner, name, content_type, committed_offset, completed, created_at, updated_at,
+	expected_sha256, expected_crc32c, expected_size FROM upload_sessions
+	WHERE completed = false AND updated_at < $1 ORDER BY updated_at LIMIT $2`
+	err = s.db.WithContext(ctx).Raw(query, updatedBefore, limit).Scan(&sessions).Error
+
+	return
+}
+
+func (s *streamRepo) CompleteUploadSession(ctx context.Context, details domain.FileDetails) error {
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+
+		query := `UPDATE upload_sessions SET committed_offset = $1, completed = true, updated_at = $2 WHERE id = $3`
+		if err := tx.Exec(query, details.Size, details.UploadedAt, details.ID).Error; err != nil {
+			return err
+		}
+
+		// save the file details from session details
+		query = `INSERT INTO file_details (id, owner, name, content_type, uploaded_at, size, sha256, crc32c, status,
+		completed_at) SELECT id, owner, name, content_type, $1, $2, $3, $4, $5, $6 FROM upload_sessions WHERE id = $7`
+		return tx.Exec(query, details.UploadedAt, details.Size, details.SHA256, details.CRC32C,
+			domain.UploadStatusCompleted, details.CompletedAt, details.ID).Error
+	})
+}
+
+// To escape the special characters of like pattern
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
 }
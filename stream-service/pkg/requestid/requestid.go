@@ -0,0 +1,46 @@
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// key of the gRPC metadata carrying the id of the request, generated by the gateway
+const MetadataKey = "x-request-id"
+
+// max length of a request id accepted from the metadata
+const maxLength = 128
+
+type requestIDKey struct{}
+
+// To generate a new request id
+func New() string {
+	return uuid.NewString()
+}
+
+// To check the request id from the metadata can be used on logs
+func Valid(id string) bool {
+
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for _, c := range id {
+		if c < '!' || c > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// To add the request id to the context
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// To get the request id from the context, empty if not found
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
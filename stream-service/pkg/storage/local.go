@@ -0,0 +1,219 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// backend to store the objects as files on local disk under the root directory
+type localBackend struct {
+	root string
+}
+
+func NewLocalBackend(root string) (Backend, error) {
+
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	return &localBackend{
+		root: root,
+	}, nil
+}
+
+func (l *localBackend) Put(ctx context.Context, key string, reader io.Reader) (int64, error) {
+	return l.PutWithProgress(ctx, key, reader, 0, nil)
+}
+
+func (l *localBackend) PutWithProgress(ctx context.Context, key string, reader io.Reader, interval int64,
+	progress func(stored int64)) (int64, error) {
+
+	filePath := l.filePath(key)
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return 0, err
+	}
+
+	file, err := os.Create(filePath)
+	if err != nil {
+		return 0, err
+	}
+
+	// flush the data to disk before reporting each interval of it as stored
+	writer := io.Writer(file)
+	if progress != nil {
+		writer = &progressWriter{writer: file, interval: interval, sync: file.Sync, progress: progress}
+	}
+
+	size, err := io.Copy(writer, reader)
+	// flush the data to disk before reporting it as stored
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	// remove the partially written file
+	if err != nil {
+		os.Remove(filePath)
+		return 0, err
+	}
+
+	return size, nil
+}
+
+func (l *localBackend) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+
+	file, err := os.Open(l.filePath(key))
+	if err != nil {
+		return nil, convertLocalError(err)
+	}
+
+	if offset > 0 {
+		if _, err := file.Seek(offset, io.SeekStart); err != nil {
+			file.Close()
+			return nil, err
+		}
+	}
+	if length < 0 {
+		return file, nil
+	}
+
+	// read only the length of data from the file
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(file, length), file}, nil
+}
+
+func (l *localBackend) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+
+	info, err := os.Stat(l.filePath(key))
+	if err != nil {
+		return ObjectInfo{}, convertLocalError(err)
+	}
+
+	return ObjectInfo{
+		Key:        key,
+		Size:       info.Size(),
+		ModifiedAt: info.ModTime(),
+	}, nil
+}
+
+func (l *localBackend) Rename(ctx context.Context, oldKey, newKey string) error {
+
+	newPath := l.filePath(newKey)
+	if err := os.MkdirAll(filepath.Dir(newPath), 0755); err != nil {
+		return err
+	}
+
+	oldPath := l.filePath(oldKey)
+	if err := os.Rename(oldPath, newPath); err != nil {
+		return convertLocalError(err)
+	}
+
+	// sync the directory to persist the rename, then remove the old directory if it became empty
+	if err := syncDir(filepath.Dir(newPath)); err != nil {
+		return err
+	}
+	if dir := filepath.Dir(oldPath); dir != filepath.Clean(l.root) {
+		os.Remove(dir)
+	}
+
+	return nil
+}
+
+func (l *localBackend) Delete(ctx context.Context, key string) error {
+
+	filePath := l.filePath(key)
+	if err := os.Remove(filePath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	// remove the parent directories which became empty, it fails on the first non empty directory
+	for dir := filepath.Dir(filePath); dir != filepath.Clean(l.root); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			break
+		}
+	}
+
+	return nil
+}
+
+func (l *localBackend) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+
+	// walk only the directory of the prefix
+	dir := l.root
+	if i := strings.LastIndex(prefix, "/"); i >= 0 {
+		dir = l.filePath(prefix[:i])
+	}
+
+	var objects []ObjectInfo
+	err := filepath.WalkDir(dir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(l.root, filePath)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, ObjectInfo{
+			Key:        key,
+			Size:       info.Size(),
+			ModifiedAt: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].Key < objects[j].Key
+	})
+
+	return objects, nil
+}
+
+// To get the file path of the key, the key cleaned to keep the file under root
+func (l *localBackend) filePath(key string) string {
+	return filepath.Join(l.root, filepath.FromSlash(path.Clean("/"+key)))
+}
+
+func syncDir(dir string) error {
+
+	file, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return file.Sync()
+}
+
+func convertLocalError(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,240 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// error returned by default for the write errors injected on memory backend
+var ErrInjectedWrite = errors.New("injected write error")
+
+// backend to store the objects on memory, for tests and local development.
+// all the objects are lost when the process exits
+type MemoryBackend struct {
+	mu          sync.RWMutex
+	objects     map[string]memoryObject
+	writeErrors []writeError
+}
+
+type memoryObject struct {
+	data       []byte
+	modifiedAt time.Time
+}
+
+// error to return on the put of a key with the prefix after writing the bytes
+type writeError struct {
+	prefix string
+	after  int64
+	err    error
+}
+
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{
+		objects: make(map[string]memoryObject),
+	}
+}
+
+func (m *MemoryBackend) Put(ctx context.Context, key string, reader io.Reader) (int64, error) {
+	return m.PutWithProgress(ctx, key, reader, 0, nil)
+}
+
+// the data reported as stored once held in memory, as nothing persisted more on the memory backend
+func (m *MemoryBackend) PutWithProgress(ctx context.Context, key string, reader io.Reader, interval int64,
+	progress func(stored int64)) (int64, error) {
+
+	// read up to the bytes of the injected error, so the reader consumed as on a real storage
+	limited := reader
+	injected, hasInjected := m.findWriteError(key)
+	if hasInjected {
+		limited = io.LimitReader(reader, injected.after)
+	}
+
+	var buffer bytes.Buffer
+	writer := io.Writer(&buffer)
+	if progress != nil {
+		writer = &progressWriter{writer: &buffer, interval: interval, progress: progress}
+	}
+	size, err := io.Copy(writer, limited)
+	if err != nil {
+		return 0, err
+	}
+	// fail only if there is more data to write after the bytes
+	if hasInjected && size == injected.after {
+		if n, _ := io.ReadFull(reader, make([]byte, 1)); n > 0 {
+			return 0, injected.err
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.objects[key] = memoryObject{
+		data:       buffer.Bytes(),
+		modifiedAt: time.Now(),
+	}
+
+	return size, nil
+}
+
+func (m *MemoryBackend) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+
+	m.mu.RLock()
+	object, ok := m.objects[key]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	// the stored data never modified, so reading it without copy
+	data := object.data[min(offset, int64(len(object.data))):]
+	if length >= 0 && length < int64(len(data)) {
+		data = data[:length]
+	}
+
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (m *MemoryBackend) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	object, ok := m.objects[key]
+	if !ok {
+		return ObjectInfo{}, ErrNotFound
+	}
+
+	return ObjectInfo{
+		Key:        key,
+		Size:       int64(len(object.data)),
+		ModifiedAt: object.modifiedAt,
+	}, nil
+}
+
+func (m *MemoryBackend) Rename(ctx context.Context, oldKey, newKey string) error {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	object, ok := m.objects[oldKey]
+	if !ok {
+		return ErrNotFound
+	}
+	m.objects[newKey] = object
+	delete(m.objects, oldKey)
+
+	return nil
+}
+
+func (m *MemoryBackend) Delete(ctx context.Context, key string) error {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.objects, key)
+	return nil
+}
+
+func (m *MemoryBackend) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var objects []ObjectInfo
+	for key, object := range m.objects {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		objects = append(objects, ObjectInfo{
+			Key:        key,
+			Size:       int64(len(object.data)),
+			ModifiedAt: object.modifiedAt,
+		})
+	}
+
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].Key < objects[j].Key
+	})
+
+	return objects, nil
+}
+
+/**
+*  Inspection helpers for tests
+**/
+
+// To get the keys of all the stored objects sorted
+func (m *MemoryBackend) Keys() []string {
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	keys := make([]string, 0, len(m.objects))
+	for key := range m.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// To get a copy of the object data, false if the object not exist
+func (m *MemoryBackend) Contents(key string) ([]byte, bool) {
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	object, ok := m.objects[key]
+	if !ok {
+		return nil, false
+	}
+
+	return bytes.Clone(object.data), true
+}
+
+// To fail the puts of keys starting with the prefix after writing the bytes.
+// the err returned on put, ErrInjectedWrite used if err is nil
+func (m *MemoryBackend) InjectWriteError(prefix string, after int64, err error) {
+
+	if err == nil {
+		err = ErrInjectedWrite
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.writeErrors = append(m.writeErrors, writeError{
+		prefix: prefix,
+		after:  after,
+		err:    err,
+	})
+}
+
+// To remove all the injected write errors
+func (m *MemoryBackend) ClearWriteErrors() {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.writeErrors = nil
+}
+
+func (m *MemoryBackend) findWriteError(key string) (writeError, bool) {
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, writeErr := range m.writeErrors {
+		if strings.HasPrefix(key, writeErr.prefix) {
+			return writeErr, true
+		}
+	}
+
+	return writeError{}, false
+}
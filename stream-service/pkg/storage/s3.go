@@ -0,0 +1,164 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"stream-service/pkg/config"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// size of each part on multipart upload, also the size of data buffered on memory for a put
+var s3PartSize uint64 = 16 * 1024 * 1024
+
+// backend to store the objects on a bucket of S3 compatible storage (AWS S3, MinIO ...)
+type s3Backend struct {
+	client *minio.Client
+	bucket string
+}
+
+func NewS3Backend(cfg config.Config) (Backend, error) {
+
+	client, err := minio.New(cfg.S3Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.S3AccessKey, cfg.S3SecretKey, ""),
+		Secure: cfg.S3UseSSL,
+		Region: cfg.S3Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create s3 client: %w", err)
+	}
+
+	// create the bucket if not exist
+	ctx := context.Background()
+	exists, err := client.BucketExists(ctx, cfg.S3Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check s3 bucket: %w", err)
+	}
+	if !exists {
+		err = client.MakeBucket(ctx, cfg.S3Bucket, minio.MakeBucketOptions{Region: cfg.S3Region})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create s3 bucket: %w", err)
+		}
+	}
+
+	return &s3Backend{
+		client: client,
+		bucket: cfg.S3Bucket,
+	}, nil
+}
+
+func (s *s3Backend) Put(ctx context.Context, key string, reader io.Reader) (int64, error) {
+
+	// unknown size, so the data uploaded as parts and aborted if the reader returns an error
+	info, err := s.client.PutObject(ctx, s.bucket, key, reader, -1, minio.PutObjectOptions{
+		PartSize: s3PartSize,
+		// sign without the payload hash, streaming signature is not supported by all S3 compatible storages
+		DisableContentSha256: true,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return info.Size, nil
+}
+
+// the parts of the upload not persisted as the object until completed, so no progress reported
+func (s *s3Backend) PutWithProgress(ctx context.Context, key string, reader io.Reader, interval int64,
+	progress func(stored int64)) (int64, error) {
+	return s.Put(ctx, key, reader)
+}
+
+func (s *s3Backend) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+
+	if length == 0 {
+		if _, err := s.Stat(ctx, key); err != nil {
+			return nil, err
+		}
+		return io.NopCloser(bytes.NewReader(nil)), nil
+	}
+
+	var opts minio.GetObjectOptions
+	if length > 0 {
+		if err := opts.SetRange(offset, offset+length-1); err != nil {
+			return nil, err
+		}
+	} else if offset > 0 {
+		// range from the offset to the end
+		if err := opts.SetRange(offset, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	// core client to request the range directly, so the error is returned before reading
+	object, _, _, err := minio.Core{Client: s.client}.GetObject(ctx, s.bucket, key, opts)
+	if err != nil {
+		return nil, convertS3Error(err)
+	}
+
+	return object, nil
+}
+
+func (s *s3Backend) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+
+	info, err := s.client.StatObject(ctx, s.bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		return ObjectInfo{}, convertS3Error(err)
+	}
+
+	return ObjectInfo{
+		Key:        info.Key,
+		Size:       info.Size,
+		ModifiedAt: info.LastModified,
+	}, nil
+}
+
+func (s *s3Backend) Rename(ctx context.Context, oldKey, newKey string) error {
+
+	// objects can't move, so copy as the new object (visible only after completed) and delete the old.
+	// compose used instead of copy to support objects larger than the max size of a single copy
+	_, err := s.client.ComposeObject(ctx,
+		minio.CopyDestOptions{Bucket: s.bucket, Object: newKey},
+		minio.CopySrcOptions{Bucket: s.bucket, Object: oldKey},
+	)
+	if err != nil {
+		return convertS3Error(err)
+	}
+
+	return s.Delete(ctx, oldKey)
+}
+
+func (s *s3Backend) Delete(ctx context.Context, key string) error {
+
+	return s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{})
+}
+
+func (s *s3Backend) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+
+	var objects []ObjectInfo
+	// objects listed in the order of key
+	for info := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{
+		Prefix:    prefix,
+		Recursive: true,
+	}) {
+		if info.Err != nil {
+			return nil, info.Err
+		}
+		objects = append(objects, ObjectInfo{
+			Key:        info.Key,
+			Size:       info.Size,
+			ModifiedAt: info.LastModified,
+		})
+	}
+
+	return objects, nil
+}
+
+func convertS3Error(err error) error {
+	if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,147 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"stream-service/pkg/config"
+	"time"
+)
+
+/**
+*  Abstract the storage of file data as objects
+**/
+
+// backends can be selected on config
+const (
+	BackendLocal  = "local"
+	BackendS3     = "s3"
+	BackendMemory = "memory"
+)
+
+var ErrNotFound = errors.New("object not found")
+
+// backend to abstract all storage functionalities needed
+type Backend interface {
+	// store all the data from the reader as the object and return the stored size.
+	// nothing will be stored if the reader returns an error
+	Put(ctx context.Context, key string, reader io.Reader) (int64, error)
+	// store the data as put, the progress called with the size of the data durably stored on each interval
+	// of the data. the progress not called if the backend not able to persist the data before completed
+	PutWithProgress(ctx context.Context, key string, reader io.Reader, interval int64,
+		progress func(stored int64)) (int64, error)
+	// get the object data from the offset, length -1 to read until the end
+	GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error)
+	Stat(ctx context.Context, key string) (ObjectInfo, error)
+	// move the object to the new key atomically, the object of the new key replaced if exist
+	Rename(ctx context.Context, oldKey, newKey string) error
+	// delete the object, no error if the object not exist
+	Delete(ctx context.Context, key string) error
+	// list all the objects which key start with the prefix, sorted by key
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+}
+
+type ObjectInfo struct {
+	Key        string
+	Size       int64
+	ModifiedAt time.Time
+}
+
+// To create the backend selected on config
+func NewBackend(cfg config.Config) (Backend, error) {
+
+	switch cfg.StorageBackend {
+	case BackendLocal:
+		return NewLocalBackend(cfg.StorageLocalDir)
+	case BackendS3:
+		return NewS3Backend(cfg)
+	case BackendMemory:
+		return NewMemoryBackend(), nil
+	default:
+		return nil, fmt.Errorf("invalid storage backend %q", cfg.StorageBackend)
+	}
+}
+
+// reader to read the objects one after another as a single stream.
+// each object opened only when the previous object read completely
+type concatReader struct {
+	ctx     context.Context
+	backend Backend
+	keys    []string
+	current io.ReadCloser
+}
+
+func NewConcatReader(ctx context.Context, backend Backend, keys []string) io.ReadCloser {
+	return &concatReader{
+		ctx:     ctx,
+		backend: backend,
+		keys:    keys,
+	}
+}
+
+func (c *concatReader) Read(data []byte) (int, error) {
+
+	for {
+		if c.current == nil {
+			if len(c.keys) == 0 {
+				return 0, io.EOF
+			}
+			reader, err := c.backend.GetRange(c.ctx, c.keys[0], 0, -1)
+			if err != nil {
+				return 0, fmt.Errorf("failed to get object %s: %w", c.keys[0], err)
+			}
+			c.current, c.keys = reader, c.keys[1:]
+		}
+
+		n, err := c.current.Read(data)
+		if err == io.EOF {
+			// continue with the next object
+			c.current.Close()
+			c.current = nil
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+		return n, err
+	}
+}
+
+func (c *concatReader) Close() error {
+	if c.current == nil {
+		return nil
+	}
+	return c.current.Close()
+}
+
+// writer to report the size of the data written on each interval of the data,
+// the sync called before the report to persist the data written
+type progressWriter struct {
+	writer   io.Writer
+	interval int64
+	sync     func() error
+	progress func(stored int64)
+
+	written  int64
+	reported int64
+}
+
+func (w *progressWriter) Write(data []byte) (int, error) {
+
+	n, err := w.writer.Write(data)
+	w.written += int64(n)
+	if err != nil || w.written-w.reported < w.interval {
+		return n, err
+	}
+
+	if w.sync != nil {
+		if err := w.sync(); err != nil {
+			return n, err
+		}
+	}
+	w.reported = w.written
+	w.progress(w.written)
+
+	return n, nil
+}
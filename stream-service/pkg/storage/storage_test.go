@@ -0,0 +1,229 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"stream-service/pkg/config"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalBackend(t *testing.T) {
+
+	backend, err := NewLocalBackend(t.TempDir())
+	require.NoError(t, err)
+
+	testBackend(t, backend)
+}
+
+func TestMemoryBackend(t *testing.T) {
+
+	testBackend(t, NewMemoryBackend())
+}
+
+func TestPutWithProgress(t *testing.T) {
+
+	local, err := NewLocalBackend(t.TempDir())
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		backend Backend
+	}{
+		"local_backend_should_report_synced_data": {backend: local},
+		"memory_backend_should_report_data":       {backend: NewMemoryBackend()},
+	}
+
+	for name, test := range testCases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			// the data read a byte at a time, so reported on each interval of the data
+			var reported []int64
+			size, err := test.backend.PutWithProgress(context.Background(), "object",
+				iotest.OneByteReader(strings.NewReader("0123456789")), 4, func(stored int64) {
+					reported = append(reported, stored)
+				})
+			require.NoError(t, err)
+			assert.Equal(t, int64(10), size)
+			assert.Equal(t, []int64{4, 8}, reported)
+		})
+	}
+}
+
+func TestMemoryBackendInjectWriteError(t *testing.T) {
+
+	ctx := context.Background()
+	backend := NewMemoryBackend()
+	backend.InjectWriteError("failing/", 4, nil)
+
+	// data up to the bytes should store
+	_, err := backend.Put(ctx, "failing/exact", strings.NewReader("data"))
+	assert.NoError(t, err)
+
+	// more data than the bytes should fail and not store
+	reader := strings.NewReader("data and more")
+	_, err = backend.Put(ctx, "failing/more", reader)
+	assert.ErrorIs(t, err, ErrInjectedWrite)
+	assert.NotZero(t, reader.Len(), "should not read after the failure")
+
+	// other keys not affected
+	_, err = backend.Put(ctx, "other", strings.NewReader("data and more"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"failing/exact", "other"}, backend.Keys())
+	data, ok := backend.Contents("other")
+	assert.True(t, ok)
+	assert.Equal(t, "data and more", string(data))
+
+	backend.ClearWriteErrors()
+	_, err = backend.Put(ctx, "failing/more", strings.NewReader("data and more"))
+	assert.NoError(t, err)
+}
+
+// To run against a local MinIO set the S3_TEST_ENDPOINT (ex: localhost:9000),
+// S3_TEST_ACCESS_KEY and S3_TEST_SECRET_KEY, the test workflow runs it against a MinIO container
+func TestS3Backend(t *testing.T) {
+
+	endpoint := os.Getenv("S3_TEST_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("S3_TEST_ENDPOINT not set to test the s3 backend")
+	}
+
+	backend, err := NewS3Backend(config.Config{
+		S3Endpoint:  endpoint,
+		S3Bucket:    "stream-service-test",
+		S3AccessKey: os.Getenv("S3_TEST_ACCESS_KEY"),
+		S3SecretKey: os.Getenv("S3_TEST_SECRET_KEY"),
+	})
+	require.NoError(t, err)
+
+	testBackend(t, backend)
+}
+
+// To test the behaviour expected from all the backends
+func testBackend(t *testing.T, backend Backend) {
+
+	ctx := context.Background()
+	// unique prefix to not conflict with the objects of previous runs
+	prefix := uuid.NewString() + "/"
+
+	t.Run("put_and_get_range", func(t *testing.T) {
+
+		key := prefix + "object"
+		size, err := backend.Put(ctx, key, strings.NewReader("0123456789"))
+		require.NoError(t, err)
+		assert.Equal(t, int64(10), size)
+
+		tests := []struct {
+			offset, length int64
+			expected       string
+		}{
+			{offset: 0, length: -1, expected: "0123456789"},
+			{offset: 4, length: -1, expected: "456789"},
+			{offset: 2, length: 3, expected: "234"},
+			{offset: 0, length: 0, expected: ""},
+		}
+		for _, test := range tests {
+			reader, err := backend.GetRange(ctx, key, test.offset, test.length)
+			require.NoError(t, err)
+			data, err := io.ReadAll(reader)
+			reader.Close()
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, string(data))
+		}
+
+		info, err := backend.Stat(ctx, key)
+		require.NoError(t, err)
+		assert.Equal(t, key, info.Key)
+		assert.Equal(t, int64(10), info.Size)
+	})
+
+	t.Run("not_exist_object_should_return_not_found", func(t *testing.T) {
+
+		_, err := backend.GetRange(ctx, prefix+"not_exist", 0, -1)
+		assert.ErrorIs(t, err, ErrNotFound)
+
+		_, err = backend.Stat(ctx, prefix+"not_exist")
+		assert.ErrorIs(t, err, ErrNotFound)
+
+		// deleting a not exist object should not return error
+		assert.NoError(t, backend.Delete(ctx, prefix+"not_exist"))
+	})
+
+	t.Run("rename_should_move_object", func(t *testing.T) {
+
+		oldKey, newKey := prefix+"tmp/renamed", prefix+"renamed/renamed"
+		_, err := backend.Put(ctx, oldKey, strings.NewReader("data"))
+		require.NoError(t, err)
+
+		require.NoError(t, backend.Rename(ctx, oldKey, newKey))
+
+		_, err = backend.Stat(ctx, oldKey)
+		assert.ErrorIs(t, err, ErrNotFound)
+		info, err := backend.Stat(ctx, newKey)
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), info.Size)
+
+		// renaming a not exist object should return not found
+		assert.ErrorIs(t, backend.Rename(ctx, oldKey, newKey), ErrNotFound)
+		require.NoError(t, backend.Delete(ctx, newKey))
+	})
+
+	t.Run("reader_error_should_not_store_object", func(t *testing.T) {
+
+		key := prefix + "failed"
+		reader := io.MultiReader(strings.NewReader("data"), errReader{})
+		_, err := backend.Put(ctx, key, reader)
+		assert.Error(t, err)
+
+		_, err = backend.Stat(ctx, key)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("list_delete_and_concat", func(t *testing.T) {
+
+		listPrefix := prefix + "parts/"
+		keys := []string{listPrefix + "2", listPrefix + "0", listPrefix + "1"}
+		for _, key := range keys {
+			_, err := backend.Put(ctx, key, strings.NewReader("part"+strings.TrimPrefix(key, listPrefix)))
+			require.NoError(t, err)
+		}
+
+		// objects should list in the order of key
+		objects, err := backend.List(ctx, listPrefix)
+		require.NoError(t, err)
+		var listed []string
+		for _, object := range objects {
+			listed = append(listed, object.Key)
+		}
+		assert.Equal(t, []string{listPrefix + "0", listPrefix + "1", listPrefix + "2"}, listed)
+
+		reader := NewConcatReader(ctx, backend, listed)
+		data, err := io.ReadAll(reader)
+		reader.Close()
+		require.NoError(t, err)
+		assert.Equal(t, "part0part1part2", string(data))
+
+		for _, key := range listed {
+			require.NoError(t, backend.Delete(ctx, key))
+		}
+		objects, err = backend.List(ctx, listPrefix)
+		require.NoError(t, err)
+		assert.Empty(t, objects)
+	})
+}
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) {
+	return 0, errors.New("read error")
+}
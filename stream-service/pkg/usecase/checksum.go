@@ -0,0 +1,167 @@
+package usecase
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"stream-service/pkg/models/request"
+	"strings"
+)
+
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+// size and checksums of a file
+type checksum struct {
+	size   int64
+	sha256 string
+	crc32c uint32
+}
+
+// writer to compute the size and checksums of all the data written on it
+type checksumWriter struct {
+	size   int64
+	sha256 hash.Hash
+	crc32c hash.Hash32
+}
+
+func newChecksumWriter() *checksumWriter {
+	return &checksumWriter{
+		sha256: sha256.New(),
+		crc32c: crc32.New(crc32cTable),
+	}
+}
+
+func (c *checksumWriter) Write(data []byte) (int, error) {
+	// writes on hash never return an error
+	c.sha256.Write(data)
+	c.crc32c.Write(data)
+	c.size += int64(len(data))
+
+	return len(data), nil
+}
+
+func (c *checksumWriter) sum() checksum {
+	return checksum{
+		size:   c.size,
+		sha256: hex.EncodeToString(c.sha256.Sum(nil)),
+		crc32c: c.crc32c.Sum32(),
+	}
+}
+
+// To verify the actual size and checksum with the expected (only the provided expected values)
+func verifyChecksum(expected request.Checksum, actual checksum) error {
+
+	if expected.Size != nil && *expected.Size != actual.size {
+		return fmt.Errorf("%w: declared %d bytes but got %d bytes", ErrSizeMismatch, *expected.Size, actual.size)
+	}
+	if expected.SHA256 != "" && !strings.EqualFold(expected.SHA256, actual.sha256) {
+		return fmt.Errorf("%w: expected sha256 %s but got %s", ErrChecksumMismatch, expected.SHA256, actual.sha256)
+	}
+	if expected.CRC32C != nil && *expected.CRC32C != actual.crc32c {
+		return fmt.Errorf("%w: expected crc32c %d but got %d", ErrChecksumMismatch, *expected.CRC32C, actual.crc32c)
+	}
+
+	return nil
+}
+
+// hash functions of the algorithms supported to verify a part of the file
+var partHashes = map[string]func() hash.Hash{
+	"md5":    md5.New,
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+}
+
+// To create the hash to verify the part checksum, nil if the checksum not provided
+func newPartHash(expected *request.PartChecksum) (hash.Hash, error) {
+
+	if expected == nil {
+		return nil, nil
+	}
+
+	newHash, ok := partHashes[expected.Algorithm]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidChecksumAlgorithm, expected.Algorithm)
+	}
+
+	return newHash(), nil
+}
+
+// To verify the digest of the part with the expected
+func verifyPartChecksum(expected *request.PartChecksum, actual hash.Hash) error {
+
+	if digest := actual.Sum(nil); !bytes.Equal(expected.Digest, digest) {
+		return fmt.Errorf("%w: expected %s %x but got %x", ErrChecksumMismatch, expected.Algorithm,
+			expected.Digest, digest)
+	}
+
+	return nil
+}
+
+// writer to verify the sha256 of each part on the data of the parts written in order,
+// the size of the parts should be verified before writing
+type partsVerifier struct {
+	parts   []request.MultipartPart
+	index   int   // index of the part writing
+	written int64 // size of the data written of the part
+	sha256  hash.Hash
+	err     error // error of the first part not matching
+}
+
+func newPartsVerifier(parts []request.MultipartPart) *partsVerifier {
+	return &partsVerifier{
+		parts:  parts,
+		sha256: sha256.New(),
+	}
+}
+
+func (v *partsVerifier) Write(data []byte) (int, error) {
+
+	n := len(data)
+	for {
+		// verify the parts completely written (a part can be empty)
+		for v.index < len(v.parts) && v.written == v.parts[v.index].Size {
+			v.verifyPart()
+		}
+		if len(data) == 0 || v.index == len(v.parts) {
+			break
+		}
+
+		size := min(int64(len(data)), v.parts[v.index].Size-v.written)
+		v.sha256.Write(data[:size])
+		v.written += size
+		data = data[size:]
+	}
+
+	return n, nil
+}
+
+// To verify the sha256 of the part completely written and move to the next part
+func (v *partsVerifier) verifyPart() {
+
+	part := v.parts[v.index]
+	actual := hex.EncodeToString(v.sha256.Sum(nil))
+	if v.err == nil && part.SHA256 != "" && !strings.EqualFold(part.SHA256, actual) {
+		v.err = fmt.Errorf("%w: expected sha256 %s of part %d but got %s", ErrChecksumMismatch,
+			part.SHA256, part.Number, actual)
+	}
+
+	v.index++
+	v.written = 0
+	v.sha256.Reset()
+}
+
+// To get the error of the first part not matching after all the data written
+func (v *partsVerifier) verify() error {
+
+	v.Write(nil)
+	if v.err == nil && v.index < len(v.parts) {
+		return fmt.Errorf("%w: part %d not completely written", ErrSizeMismatch, v.parts[v.index].Number)
+	}
+
+	return v.err
+}
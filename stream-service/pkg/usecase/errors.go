@@ -0,0 +1,31 @@
+package usecase
+
+import "errors"
+
+var (
+	ErrInvalidFileID  = errors.New("invalid file id")
+	ErrFileNotFound   = errors.New("file not found")
+	ErrFileNotDeleted = errors.New("file not deleted")
+
+	ErrChecksumMismatch  = errors.New("checksum of uploaded file not matching")
+	ErrSizeMismatch      = errors.New("size of uploaded file not matching the declared size")
+	ErrUploadTimeout     = errors.New("upload stream timed out waiting for data")
+	ErrUploadInterrupted = errors.New("upload interrupted before completed")
+	ErrFileMissing       = errors.New("file missing on storage")
+	ErrStorageWrite      = errors.New("failed to store data")
+
+	ErrInvalidCursor    = errors.New("invalid cursor")
+	ErrInvalidSortField = errors.New("invalid sort field")
+
+	ErrInvalidUploadSessionID = errors.New("invalid upload session id")
+	ErrUploadSessionNotFound  = errors.New("upload session not found")
+	ErrUploadSessionCompleted = errors.New("upload session already completed")
+	ErrUploadOffsetMismatch   = errors.New("upload offset not matching with committed offset")
+	ErrUploadSessionExpired   = errors.New("upload session expired")
+
+	ErrInvalidChecksumAlgorithm = errors.New("invalid checksum algorithm")
+
+	ErrInvalidPartNumber     = errors.New("invalid part number")
+	ErrInvalidMultipartParts = errors.New("invalid multipart parts")
+	ErrMultipartPartNotFound = errors.New("multipart part not found")
+)
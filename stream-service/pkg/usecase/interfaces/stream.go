@@ -2,10 +2,49 @@ package interfaces
 
 import (
 	"context"
+	"io"
 	"stream-service/pkg/models/request"
+	"stream-service/pkg/models/response"
+	"time"
 )
 
 type StreamUseCase interface {
 	UploadFileDetails(ctx context.Context, details request.FileDetails) (string, error)
-	UploadFileAsStream(ctx context.Context, id string, dataChan <-chan []byte, errChan chan error)
+	// store the data read from the stream until EOF as the file and return the result after the upload completed.
+	// the progress (if not nil) called on each ack size of data stored and after completed
+	UploadFileAsStream(ctx context.Context, id string, expected request.Checksum, stream io.Reader,
+		progress func(response.UploadProgress)) error
+	DownloadFile(ctx context.Context, id string) (response.FileDetails, io.ReadCloser, error)
+	GetFileDetails(ctx context.Context, id string) (response.FileDetails, error)
+	ListFiles(ctx context.Context, req request.ListFiles) (response.FileList, error)
+	// returns the time of the file will be permanently removed
+	DeleteFile(ctx context.Context, id string) (time.Time, error)
+	RestoreFile(ctx context.Context, id string) (response.FileDetails, error)
+	// returns the count of files permanently removed
+	PurgeDeletedFiles(ctx context.Context) (int, error)
+	// commit or remove the temporary files left by the interrupted uploads, returns the count of files recovered
+	RecoverUploads(ctx context.Context) (int, error)
+	// find the mismatches between storage and database, and repair them if repair is true
+	Reconcile(ctx context.Context, repair bool) (response.Reconciliation, error)
+
+	CreateUploadSession(ctx context.Context, details request.FileDetails) (string, error)
+	GetUploadSession(ctx context.Context, sessionID string) (response.UploadSession, error)
+	// returns the session if the upload can resume from the offset
+	CheckUploadSessionOffset(ctx context.Context, sessionID string, offset int64) (response.UploadSession, error)
+	// store the data read from the stream after the offset, the data received before a stream error kept to resume
+	UploadSessionAsStream(ctx context.Context, sessionID string, offset int64, stream io.Reader) error
+	// store the data read from the stream as a part after the offset, the session completed only when
+	// the declared size received. the part discarded if the checksum (if not nil) not matching
+	UploadSessionPartAsStream(ctx context.Context, sessionID string, offset int64, checksum *request.PartChecksum,
+		stream io.Reader) (response.UploadSession, error)
+	// remove the not completed upload session and its data
+	DeleteUploadSession(ctx context.Context, sessionID string) error
+	// store the data read from the stream as a part of the multipart upload (created as an upload session),
+	// the part with the same number replaced. the part not stored if the stream not completed or the size not matching
+	UploadMultipartPartAsStream(ctx context.Context, uploadID string, number int, size *int64,
+		stream io.Reader) (response.MultipartPart, error)
+	// combine the parts in order as the file and complete the upload, the parts not included removed
+	CompleteMultipartUpload(ctx context.Context, uploadID string, parts []request.MultipartPart) error
+	// remove the not completed upload sessions expired and returns the count of removed sessions
+	PurgeExpiredUploadSessions(ctx context.Context) (int, error)
 }
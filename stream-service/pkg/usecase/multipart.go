@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"stream-service/pkg/logger"
+	"stream-service/pkg/models/request"
+	"stream-service/pkg/models/response"
+)
+
+// max number of parts of a multipart upload
+const maxMultipartParts = 10000
+
+func (s *streamUseCase) UploadMultipartPartAsStream(ctx context.Context, uploadID string, number int,
+	size *int64, stream io.Reader) (response.MultipartPart, error) {
+
+	if number < 1 || number > maxMultipartParts {
+		return response.MultipartPart{}, fmt.Errorf("%w: %d should be between 1 and %d",
+			ErrInvalidPartNumber, number, maxMultipartParts)
+	}
+
+	session, err := s.findUploadSession(ctx, uploadID)
+	if err != nil {
+		return response.MultipartPart{}, err
+	}
+	if session.Completed {
+		return response.MultipartPart{}, ErrUploadSessionCompleted
+	}
+	if s.isUploadSessionExpired(session) {
+		return response.MultipartPart{}, ErrUploadSessionExpired
+	}
+
+	// store the part and compute the checksum, the part not kept if the stream not completed
+	key := multipartPartKey(uploadID, number)
+	checksum := newChecksumWriter()
+	if _, err := s.putStream(ctx, key, false, stream, checksum, nil); err != nil {
+		return response.MultipartPart{}, err
+	}
+	actual := checksum.sum()
+
+	// all the data received, so keep the part even if the stream cancelled after
+	ctx = context.WithoutCancel(ctx)
+
+	if err := verifyChecksum(request.Checksum{Size: size}, actual); err != nil {
+		if err := s.storage.Delete(ctx, key); err != nil {
+			s.logger.ErrorContext(ctx, "failed to delete multipart part from storage", logger.KeyFileID, uploadID,
+				"key", key, logger.KeyError, err)
+		}
+		return response.MultipartPart{}, err
+	}
+
+	// update the session to extend the expiry on each part uploaded
+	if err := s.repo.UpdateUploadSessionOffset(ctx, uploadID, session.CommittedOffset); err != nil {
+		s.logger.ErrorContext(ctx, "failed to update upload session", logger.KeyFileID, uploadID, logger.KeyError, err)
+	}
+
+	return response.MultipartPart{
+		Number: number,
+		Size:   actual.size,
+		SHA256: actual.sha256,
+		CRC32C: actual.crc32c,
+	}, nil
+}
+
+func (s *streamUseCase) CompleteMultipartUpload(ctx context.Context, uploadID string,
+	parts []request.MultipartPart) error {
+
+	if len(parts) == 0 {
+		return fmt.Errorf("%w: no parts to complete", ErrInvalidMultipartParts)
+	}
+	for i, part := range parts {
+		if part.Number < 1 || part.Number > maxMultipartParts {
+			return fmt.Errorf("%w: %d should be between 1 and %d", ErrInvalidPartNumber, part.Number, maxMultipartParts)
+		}
+		if i > 0 && part.Number <= parts[i-1].Number {
+			return fmt.Errorf("%w: parts should be in ascending order of number", ErrInvalidMultipartParts)
+		}
+	}
+
+	session, err := s.findUploadSession(ctx, uploadID)
+	if err != nil {
+		return err
+	}
+	if session.Completed {
+		return ErrUploadSessionCompleted
+	}
+	if s.isUploadSessionExpired(session) {
+		return ErrUploadSessionExpired
+	}
+
+	stored, err := s.storage.List(ctx, multipartPartsPrefix(uploadID))
+	if err != nil {
+		return fmt.Errorf("failed to list multipart parts from storage: %w", err)
+	}
+	sizes := make(map[string]int64, len(stored))
+	for _, object := range stored {
+		sizes[object.Key] = object.Size
+	}
+
+	// all the parts should be stored with the same size
+	keys := make([]string, len(parts))
+	for i, part := range parts {
+		keys[i] = multipartPartKey(uploadID, part.Number)
+		size, ok := sizes[keys[i]]
+		if !ok {
+			return fmt.Errorf("%w: part %d", ErrMultipartPartNotFound, part.Number)
+		}
+		if size != part.Size {
+			return fmt.Errorf("%w: part %d stored %d bytes but got %d bytes", ErrSizeMismatch,
+				part.Number, size, part.Size)
+		}
+		delete(sizes, keys[i])
+	}
+
+	// all the parts received, so complete the upload even if the request cancelled after
+	ctx = context.WithoutCancel(ctx)
+
+	if err := s.commitSessionParts(ctx, session, keys, newPartsVerifier(parts)); err != nil {
+		return err
+	}
+
+	// remove the parts uploaded but not included on the file
+	for key := range sizes {
+		if err := s.storage.Delete(ctx, key); err != nil {
+			s.logger.ErrorContext(ctx, "failed to delete multipart part from storage", logger.KeyFileID, uploadID,
+				"key", key, logger.KeyError, err)
+		}
+	}
+
+	return nil
+}
+
+// To generate the key prefix of the multipart upload parts
+func multipartPartsPrefix(uploadID string) string {
+	return objectsPrefix(uploadID) + "multipart/"
+}
+
+// To generate the key of the multipart upload part, number padded with zero to keep the parts sorted by key
+func multipartPartKey(uploadID string, number int) string {
+	return fmt.Sprintf("%s%05d", multipartPartsPrefix(uploadID), number)
+}
@@ -0,0 +1,266 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash/crc32"
+	"io"
+	"stream-service/pkg/config"
+	"stream-service/pkg/domain"
+	"stream-service/pkg/metrics"
+	"stream-service/pkg/mock/mock_repo"
+	"stream-service/pkg/models/request"
+	"stream-service/pkg/models/response"
+	"stream-service/pkg/storage"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func sha256Hex(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestUploadMultipartPartAsStream(t *testing.T) {
+
+	uploadID := uuid.New()
+
+	testCases := map[string]struct {
+		number           int
+		size             *int64
+		stream           func(t *testing.T, cancel context.CancelFunc) io.Reader
+		buildStub        func(mockRepo *mock_repo.MockStreamRepository)
+		expectedPart     response.MultipartPart
+		expectedError    error
+		expectedContents map[string]string // contents of the objects after the upload
+	}{
+		"invalid_part_number_should_return_error": {
+			number:           0,
+			stream:           func(t *testing.T, cancel context.CancelFunc) io.Reader { return strings.NewReader("data") },
+			buildStub:        func(mockRepo *mock_repo.MockStreamRepository) {},
+			expectedError:    ErrInvalidPartNumber,
+			expectedContents: map[string]string{},
+		},
+		"completed_upload_should_return_error": {
+			number: 1,
+			stream: func(t *testing.T, cancel context.CancelFunc) io.Reader { return strings.NewReader("data") },
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+				mockRepo.EXPECT().FindUploadSessionByID(gomock.Any(), uploadID.String()).Times(1).
+					Return(domain.UploadSession{ID: uploadID, Completed: true}, nil)
+			},
+			expectedError:    ErrUploadSessionCompleted,
+			expectedContents: map[string]string{},
+		},
+		"part_not_matching_declared_size_should_not_store": {
+			number: 2,
+			size:   func() *int64 { size := int64(5); return &size }(),
+			stream: func(t *testing.T, cancel context.CancelFunc) io.Reader { return strings.NewReader("data") },
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+				mockRepo.EXPECT().FindUploadSessionByID(gomock.Any(), uploadID.String()).Times(1).
+					Return(domain.UploadSession{ID: uploadID}, nil)
+			},
+			expectedError:    ErrSizeMismatch,
+			expectedContents: map[string]string{},
+		},
+		"cancelled_part_should_not_store": {
+			number: 2,
+			stream: func(t *testing.T, cancel context.CancelFunc) io.Reader {
+				return blockingStream(t, cancel, "data")
+			},
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+				mockRepo.EXPECT().FindUploadSessionByID(gomock.Any(), uploadID.String()).Times(1).
+					Return(domain.UploadSession{ID: uploadID}, nil)
+			},
+			expectedError:    context.Canceled,
+			expectedContents: map[string]string{},
+		},
+		"successful_part_upload_should_store_part": {
+			number: 2,
+			size:   func() *int64 { size := int64(4); return &size }(),
+			stream: func(t *testing.T, cancel context.CancelFunc) io.Reader { return strings.NewReader("data") },
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+				mockRepo.EXPECT().FindUploadSessionByID(gomock.Any(), uploadID.String()).Times(1).
+					Return(domain.UploadSession{ID: uploadID}, nil)
+				// expecting the session updated to extend the expiry
+				mockRepo.EXPECT().UpdateUploadSessionOffset(gomock.Any(), uploadID.String(), int64(0)).
+					Times(1).Return(nil)
+			},
+			expectedPart: response.MultipartPart{Number: 2, Size: 4, SHA256: sha256Hex("data"),
+				CRC32C: crc32.Checksum([]byte("data"), crc32cTable)},
+			expectedContents: map[string]string{
+				multipartPartKey(uploadID.String(), 2): "data",
+			},
+		},
+	}
+
+	for name, test := range testCases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			ctl := gomock.NewController(t)
+			mockRepo := mock_repo.NewMockStreamRepository(ctl)
+			memStorage := storage.NewMemoryBackend()
+
+			test.buildStub(mockRepo)
+			streamUseCase := NewStreamUseCase(config.Config{}, mockRepo, memStorage, discardLogger, metrics.NewMetrics())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			part, err := streamUseCase.UploadMultipartPartAsStream(ctx, uploadID.String(), test.number, test.size,
+				test.stream(t, cancel))
+			if test.expectedError == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, test.expectedError)
+			}
+			assert.Equal(t, test.expectedPart, part)
+
+			contents := make(map[string]string)
+			for _, key := range memStorage.Keys() {
+				data, _ := memStorage.Contents(key)
+				contents[key] = string(data)
+			}
+			assert.Equal(t, test.expectedContents, contents)
+		})
+	}
+}
+
+func TestCompleteMultipartUpload(t *testing.T) {
+
+	uploadID := uuid.New()
+
+	// parts stored before completing the upload
+	storedParts := map[int]string{1: "one", 2: "", 3: "three", 5: "unused"}
+
+	testCases := map[string]struct {
+		parts            []request.MultipartPart
+		buildStub        func(mockRepo *mock_repo.MockStreamRepository)
+		expectedError    error
+		expectedContents map[string]string // contents of the objects after completing
+	}{
+		"parts_not_in_order_should_return_error": {
+			parts: []request.MultipartPart{
+				{Number: 3, Size: 5},
+				{Number: 1, Size: 3},
+			},
+			buildStub:     func(mockRepo *mock_repo.MockStreamRepository) {},
+			expectedError: ErrInvalidMultipartParts,
+		},
+		"part_not_stored_should_return_error": {
+			parts: []request.MultipartPart{
+				{Number: 1, Size: 3},
+				{Number: 4, Size: 4},
+			},
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+				mockRepo.EXPECT().FindUploadSessionByID(gomock.Any(), uploadID.String()).Times(1).
+					Return(domain.UploadSession{ID: uploadID}, nil)
+			},
+			expectedError: ErrMultipartPartNotFound,
+		},
+		"part_size_not_matching_should_return_error": {
+			parts: []request.MultipartPart{
+				{Number: 1, Size: 4},
+			},
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+				mockRepo.EXPECT().FindUploadSessionByID(gomock.Any(), uploadID.String()).Times(1).
+					Return(domain.UploadSession{ID: uploadID}, nil)
+			},
+			expectedError: ErrSizeMismatch,
+		},
+		"part_checksum_not_matching_should_keep_the_upload": {
+			parts: []request.MultipartPart{
+				{Number: 1, Size: 3, SHA256: sha256Hex("one")},
+				{Number: 3, Size: 5, SHA256: sha256Hex("tree")},
+			},
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+				mockRepo.EXPECT().FindUploadSessionByID(gomock.Any(), uploadID.String()).Times(1).
+					Return(domain.UploadSession{ID: uploadID}, nil)
+			},
+			expectedError: ErrChecksumMismatch,
+		},
+		"file_checksum_not_matching_should_remove_the_upload": {
+			parts: []request.MultipartPart{
+				{Number: 1, Size: 3},
+				{Number: 3, Size: 5},
+			},
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+				mockRepo.EXPECT().FindUploadSessionByID(gomock.Any(), uploadID.String()).Times(1).
+					Return(domain.UploadSession{ID: uploadID, ExpectedSHA256: sha256Hex("onetree")}, nil)
+				mockRepo.EXPECT().DeleteFileDetails(gomock.Any(), uploadID.String()).Times(1).Return(nil)
+			},
+			expectedError:    ErrChecksumMismatch,
+			expectedContents: map[string]string{},
+		},
+		"successful_complete_should_combine_the_parts_in_order": {
+			parts: []request.MultipartPart{
+				{Number: 1, Size: 3, SHA256: sha256Hex("one")},
+				{Number: 2, Size: 0, SHA256: sha256Hex("")},
+				{Number: 3, Size: 5, SHA256: sha256Hex("three")},
+			},
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+				size := int64(8)
+				mockRepo.EXPECT().FindUploadSessionByID(gomock.Any(), uploadID.String()).Times(1).
+					Return(domain.UploadSession{ID: uploadID, ExpectedSize: &size,
+						ExpectedSHA256: sha256Hex("onethree")}, nil)
+				mockRepo.EXPECT().CompleteUploadSession(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(ctx context.Context, details domain.FileDetails) error {
+						assert.Equal(t, size, details.Size)
+						assert.Equal(t, sha256Hex("onethree"), details.SHA256)
+						return nil
+					})
+			},
+			// the parts not included also removed
+			expectedContents: map[string]string{
+				fileKey(uploadID.String()): "onethree",
+			},
+		},
+	}
+
+	for name, test := range testCases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			ctl := gomock.NewController(t)
+			mockRepo := mock_repo.NewMockStreamRepository(ctl)
+			memStorage := storage.NewMemoryBackend()
+
+			contents := make(map[string]string)
+			for number, data := range storedParts {
+				key := multipartPartKey(uploadID.String(), number)
+				memStorage.Put(context.Background(), key, strings.NewReader(data))
+				contents[key] = data
+			}
+			// the stored parts expected not changed on error
+			if test.expectedContents == nil {
+				test.expectedContents = contents
+			}
+
+			test.buildStub(mockRepo)
+			streamUseCase := NewStreamUseCase(config.Config{}, mockRepo, memStorage, discardLogger, metrics.NewMetrics())
+
+			err := streamUseCase.CompleteMultipartUpload(context.Background(), uploadID.String(), test.parts)
+			if test.expectedError == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, test.expectedError)
+			}
+
+			contents = make(map[string]string)
+			for _, key := range memStorage.Keys() {
+				data, _ := memStorage.Contents(key)
+				contents[key] = string(data)
+			}
+			assert.Equal(t, test.expectedContents, contents)
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// size of the buffer to read data from the upload stream
+var streamBufferSize = 1024 * 32
+
+// reader to read the upload stream with an idle timeout, which reset on each data received.
+// the stream read on a separate goroutine only when the previous data consumed (backpressure),
+// so a read blocked on the stream can be abandoned on timeout or cancel
+type idleTimeoutReader struct {
+	ctx      context.Context
+	timeout  time.Duration
+	reader   io.Reader
+	buffer   []byte
+	pending  []byte // data received and not consumed
+	reading  bool   // a read requested to the stream and the result not received
+	err      error  // error from stream, cancel or timeout, returned on all the reads after
+	requests chan struct{}
+	results  chan readResult
+	done     chan struct{}
+}
+
+type readResult struct {
+	n   int
+	err error
+}
+
+func newIdleTimeoutReader(ctx context.Context, reader io.Reader, timeout time.Duration) *idleTimeoutReader {
+
+	r := &idleTimeoutReader{
+		ctx:      ctx,
+		timeout:  timeout,
+		reader:   reader,
+		buffer:   make([]byte, streamBufferSize),
+		requests: make(chan struct{}, 1),
+		results:  make(chan readResult),
+		done:     make(chan struct{}),
+	}
+	go r.readStream()
+
+	return r
+}
+
+// To read the stream on each request until the reader closed
+func (r *idleTimeoutReader) readStream() {
+
+	for {
+		select {
+		case <-r.requests:
+		case <-r.done:
+			return
+		}
+
+		n, err := r.readSafe()
+		select {
+		case r.results <- readResult{n: n, err: err}:
+		case <-r.done:
+			return
+		}
+	}
+}
+
+// To read the stream with the panic returned as an error, the panic on this goroutine
+// not recovered by the grpc interceptors and would crash the service
+func (r *idleTimeoutReader) readSafe() (n int, err error) {
+
+	defer func() {
+		if p := recover(); p != nil {
+			n, err = 0, fmt.Errorf("panic while reading upload stream: %v", p)
+		}
+	}()
+
+	return r.reader.Read(r.buffer)
+}
+
+func (r *idleTimeoutReader) Read(data []byte) (int, error) {
+
+	if len(r.pending) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		if err := r.receive(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(data, r.pending)
+	r.pending = r.pending[n:]
+
+	return n, nil
+}
+
+// To receive the next data from the stream with in the timeout
+func (r *idleTimeoutReader) receive() error {
+
+	if !r.reading {
+		r.reading = true
+		r.requests <- struct{}{}
+	}
+
+	timer := time.NewTimer(r.timeout)
+	defer timer.Stop()
+
+	select {
+	case result := <-r.results:
+		r.reading = false
+		r.pending = r.buffer[:result.n]
+		if result.err != nil {
+			r.err = result.err
+			// return the error after consuming the data received with it
+			if result.n == 0 {
+				return result.err
+			}
+		}
+		return nil
+	case <-r.ctx.Done():
+		r.err = r.ctx.Err()
+	case <-timer.C:
+		r.err = ErrUploadTimeout
+	}
+
+	return r.err
+}
+
+// To get the reason of the stream ended, nil if not ended or completed with EOF
+func (r *idleTimeoutReader) Err() error {
+	if r.err == io.EOF {
+		return nil
+	}
+	return r.err
+}
+
+// To stop reading the stream, a read already blocked on the stream returns when the stream closed
+func (r *idleTimeoutReader) Close() error {
+	close(r.done)
+	return nil
+}
+
+// reader to end the stream with EOF on the first error, so the data received before the error can store
+type partialReader struct {
+	reader io.Reader
+	err    error // the error replaced with EOF
+}
+
+func (r *partialReader) Read(data []byte) (int, error) {
+
+	n, err := r.reader.Read(data)
+	if err != nil && err != io.EOF {
+		r.err = err
+		err = io.EOF
+	}
+
+	return n, err
+}
@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdleTimeoutReader(t *testing.T) {
+
+	timeout := time.Millisecond * 50
+
+	testCases := map[string]struct {
+		stream        func(t *testing.T, cancel context.CancelFunc) io.Reader
+		expectedData  string
+		expectedError error // error on read
+		expectedErr   error // reason of the stream ended
+	}{
+		"slow_stream_within_idle_timeout_should_read_all": {
+			stream: func(t *testing.T, cancel context.CancelFunc) io.Reader {
+				// each data received before the idle timeout but the total time longer than it
+				chunks := []string{"one", "two", "three", "four"}
+				return readerFunc(func(data []byte) (int, error) {
+					if len(chunks) == 0 {
+						return 0, io.EOF
+					}
+					time.Sleep(timeout / 2)
+					n := copy(data, chunks[0])
+					chunks = chunks[1:]
+					return n, nil
+				})
+			},
+			expectedData: "onetwothreefour",
+		},
+		"no_data_within_idle_timeout_should_return_timeout": {
+			stream: func(t *testing.T, cancel context.CancelFunc) io.Reader {
+				return blockingStream(t, nil, "data")
+			},
+			expectedData:  "data",
+			expectedError: ErrUploadTimeout,
+			expectedErr:   ErrUploadTimeout,
+		},
+		"cancel_while_waiting_should_return_cancel": {
+			stream: func(t *testing.T, cancel context.CancelFunc) io.Reader {
+				return blockingStream(t, cancel, "data")
+			},
+			expectedData:  "data",
+			expectedError: context.Canceled,
+			expectedErr:   context.Canceled,
+		},
+		"stream_error_should_return_after_the_data": {
+			stream: func(t *testing.T, cancel context.CancelFunc) io.Reader {
+				return readerFunc(func(data []byte) (int, error) {
+					return copy(data, "data"), errors.New("stream error")
+				})
+			},
+			expectedData:  "data",
+			expectedError: errors.New("stream error"),
+			expectedErr:   errors.New("stream error"),
+		},
+		"panic_on_stream_should_return_error": {
+			stream: func(t *testing.T, cancel context.CancelFunc) io.Reader {
+				return readerFunc(func(data []byte) (int, error) {
+					panic("framing bug")
+				})
+			},
+			expectedError: errors.New("panic while reading upload stream: framing bug"),
+			expectedErr:   errors.New("panic while reading upload stream: framing bug"),
+		},
+	}
+
+	for name, test := range testCases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			reader := newIdleTimeoutReader(ctx, test.stream(t, cancel), timeout)
+			defer reader.Close()
+
+			data, err := io.ReadAll(reader)
+			assert.Equal(t, test.expectedData, string(data))
+			assert.Equal(t, test.expectedError, err)
+			assert.Equal(t, test.expectedErr, reader.Err())
+		})
+	}
+}
+
+func TestPartialReader(t *testing.T) {
+
+	reader := &partialReader{reader: io.MultiReader(strings.NewReader("data"),
+		readerFunc(func([]byte) (int, error) { return 0, ErrUploadTimeout }))}
+
+	// data before the error should read with EOF and the error recorded
+	data, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "data", string(data))
+	assert.Equal(t, ErrUploadTimeout, reader.err)
+}
@@ -0,0 +1,197 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"stream-service/pkg/domain"
+	"stream-service/pkg/logger"
+	"stream-service/pkg/models/response"
+	"stream-service/pkg/storage"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// max file details to check on a batch
+var reconcileBatchSize = 500
+
+func (s *streamUseCase) Reconcile(ctx context.Context, repair bool) (response.Reconciliation, error) {
+
+	objects, err := s.storage.List(ctx, "")
+	if err != nil {
+		return response.Reconciliation{}, fmt.Errorf("failed to list objects from storage: %w", err)
+	}
+
+	// group the objects by file id (first segment of the key), temporary files are handled by recovery
+	groups := make(map[string][]storage.ObjectInfo)
+	for _, object := range objects {
+		if strings.HasPrefix(object.Key, tempFilesPrefix) {
+			continue
+		}
+		fileID, _, _ := strings.Cut(object.Key, "/")
+		groups[fileID] = append(groups[fileID], object)
+	}
+
+	report := response.Reconciliation{CheckedObjects: len(objects)}
+
+	// check all the file details with its objects
+	afterID := uuid.Nil.String()
+	for {
+		files, err := s.repo.FindFileDetailsAfterID(ctx, afterID, reconcileBatchSize)
+		if err != nil {
+			return report, fmt.Errorf("failed to find file details from database: %w", err)
+		}
+
+		for _, details := range files {
+			fileID := details.ID.String()
+			fileObjects := groups[fileID]
+			delete(groups, fileID)
+
+			report.CheckedFiles++
+			if mismatch, ok := s.checkFileDetails(details, fileObjects); ok {
+				report.Mismatches = append(report.Mismatches, s.repairMismatch(ctx, mismatch, repair))
+			}
+		}
+
+		if len(files) < reconcileBatchSize {
+			break
+		}
+		afterID = files[len(files)-1].ID.String()
+	}
+
+	// the remaining objects are without file details, sorted to report in a stable order
+	fileIDs := make([]string, 0, len(groups))
+	for fileID := range groups {
+		fileIDs = append(fileIDs, fileID)
+	}
+	sort.Strings(fileIDs)
+
+	for _, fileID := range fileIDs {
+		if mismatch, ok := s.checkOrphanObjects(ctx, fileID, groups[fileID]); ok {
+			report.Mismatches = append(report.Mismatches, s.repairMismatch(ctx, mismatch, repair))
+		}
+	}
+
+	return report, nil
+}
+
+// To check the objects of the file details, the completed file should exist with the same size
+// and the not completed files should not have any object
+func (s *streamUseCase) checkFileDetails(details domain.FileDetails, objects []storage.ObjectInfo) (response.Mismatch, bool) {
+
+	fileID := details.ID.String()
+
+	// skip the recent changes, can be in between the database update and storage update
+	if s.isRecent(objects) || (details.CompletedAt != nil && time.Since(*details.CompletedAt) < s.reconcileGrace) {
+		return response.Mismatch{}, false
+	}
+
+	if details.Status != domain.UploadStatusCompleted {
+		if len(objects) == 0 {
+			return response.Mismatch{}, false
+		}
+		return response.Mismatch{
+			Kind:   response.MismatchLeftoverObjects,
+			FileID: fileID,
+			Detail: fmt.Sprintf("%d objects of %s upload", len(objects), details.Status),
+		}, true
+	}
+
+	for _, object := range objects {
+		if object.Key != fileKey(fileID) {
+			continue
+		}
+		if object.Size != details.Size {
+			return response.Mismatch{
+				Kind:   response.MismatchSizeMismatch,
+				FileID: fileID,
+				Detail: fmt.Sprintf("stored %d bytes of %d bytes", object.Size, details.Size),
+			}, true
+		}
+		return response.Mismatch{}, false
+	}
+
+	return response.Mismatch{
+		Kind:   response.MismatchMissingFile,
+		FileID: fileID,
+		Detail: "completed file not exist on storage",
+	}, true
+}
+
+// To check the objects without file details, which are expected only for the upload sessions not completed
+func (s *streamUseCase) checkOrphanObjects(ctx context.Context, fileID string,
+	objects []storage.ObjectInfo) (response.Mismatch, bool) {
+
+	if s.isRecent(objects) {
+		return response.Mismatch{}, false
+	}
+
+	if _, err := uuid.Parse(fileID); err == nil {
+		session, err := s.repo.FindUploadSessionByID(ctx, fileID)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "failed to find upload session from database", logger.KeyFileID, fileID,
+				logger.KeyError, err)
+			return response.Mismatch{}, false
+		}
+		if session.ID != uuid.Nil && !session.Completed {
+			return response.Mismatch{}, false
+		}
+	}
+
+	return response.Mismatch{
+		Kind:   response.MismatchOrphanObjects,
+		FileID: fileID,
+		Detail: fmt.Sprintf("%d objects without file details", len(objects)),
+	}, true
+}
+
+// To repair the mismatch if repair is true, the missing or corrupted files marked as failed
+// and the objects not needed removed from storage
+func (s *streamUseCase) repairMismatch(ctx context.Context, mismatch response.Mismatch, repair bool) response.Mismatch {
+
+	if !repair {
+		return mismatch
+	}
+
+	switch mismatch.Kind {
+	case response.MismatchMissingFile:
+		err := s.repo.UpdateFileDetailsStatus(ctx, mismatch.FileID, domain.UploadStatusFailed, ErrFileMissing.Error())
+		if err != nil {
+			s.logger.ErrorContext(ctx, "failed to update file upload status as failed", logger.KeyFileID, mismatch.FileID,
+				logger.KeyError, err)
+			return mismatch
+		}
+	case response.MismatchSizeMismatch:
+		if !s.deleteObjects(ctx, mismatch.FileID) {
+			return mismatch
+		}
+		reason := fmt.Errorf("%w: %s", ErrSizeMismatch, mismatch.Detail)
+		err := s.repo.UpdateFileDetailsStatus(ctx, mismatch.FileID, domain.UploadStatusFailed, reason.Error())
+		if err != nil {
+			s.logger.ErrorContext(ctx, "failed to update file upload status as failed", logger.KeyFileID, mismatch.FileID,
+				logger.KeyError, err)
+			return mismatch
+		}
+	case response.MismatchLeftoverObjects, response.MismatchOrphanObjects:
+		if !s.deleteObjects(ctx, mismatch.FileID) {
+			return mismatch
+		}
+	}
+
+	mismatch.Repaired = true
+	return mismatch
+}
+
+// To check any of the objects modified with in the grace period
+func (s *streamUseCase) isRecent(objects []storage.ObjectInfo) bool {
+
+	for _, object := range objects {
+		if time.Since(object.ModifiedAt) < s.reconcileGrace {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"stream-service/pkg/config"
+	"stream-service/pkg/domain"
+	"stream-service/pkg/metrics"
+	"stream-service/pkg/mock/mock_repo"
+	"stream-service/pkg/models/response"
+	"stream-service/pkg/storage"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconcile(t *testing.T) {
+
+	var (
+		validID    = uuid.New()
+		missingID  = uuid.New()
+		corruptID  = uuid.New()
+		failedID   = uuid.New()
+		sessionID  = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+		orphanID   = uuid.MustParse("00000000-0000-0000-0000-000000000002")
+		oldTime    = time.Now().Add(-time.Hour)
+		storedKeys = map[string]string{
+			fileKey(validID.String()):             "data",
+			fileKey(corruptID.String()):           "da",
+			sessionPartKey(failedID.String(), 0):  "data",
+			sessionPartKey(sessionID.String(), 0): "data",
+			fileKey(orphanID.String()):            "data",
+			"junk/object":                         "data",
+			tempFileKey(uuid.NewString()):         "data", // handled by recovery
+		}
+		files = []domain.FileDetails{
+			{ID: validID, Status: domain.UploadStatusCompleted, Size: 4, CompletedAt: &oldTime},
+			{ID: missingID, Status: domain.UploadStatusCompleted, Size: 4, CompletedAt: &oldTime},
+			{ID: corruptID, Status: domain.UploadStatusCompleted, Size: 4, CompletedAt: &oldTime},
+			{ID: failedID, Status: domain.UploadStatusFailed},
+		}
+		expectedMismatches = []response.Mismatch{
+			{Kind: response.MismatchMissingFile, FileID: missingID.String(), Detail: "completed file not exist on storage"},
+			{Kind: response.MismatchSizeMismatch, FileID: corruptID.String(), Detail: "stored 2 bytes of 4 bytes"},
+			{Kind: response.MismatchLeftoverObjects, FileID: failedID.String(), Detail: "1 objects of failed upload"},
+			{Kind: response.MismatchOrphanObjects, FileID: orphanID.String(), Detail: "1 objects without file details"},
+			{Kind: response.MismatchOrphanObjects, FileID: "junk", Detail: "1 objects without file details"},
+		}
+	)
+
+	testCases := map[string]struct {
+		repair             bool
+		gracePeriod        time.Duration
+		buildStub          func(mockRepo *mock_repo.MockStreamRepository)
+		expectedMismatches []response.Mismatch
+		expectedKeys       []string
+		expectedError      error
+	}{
+		"db_error_should_return_error": {
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+				mockRepo.EXPECT().FindFileDetailsAfterID(gomock.Any(), uuid.Nil.String(), reconcileBatchSize).Times(1).
+					Return(nil, errors.New("db error"))
+			},
+			expectedError: errors.New("db error"),
+		},
+		"recent_changes_should_skip": {
+			gracePeriod: time.Minute,
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+				// only the file without object and not recently completed is a mismatch
+				mockRepo.EXPECT().FindFileDetailsAfterID(gomock.Any(), uuid.Nil.String(), reconcileBatchSize).Times(1).
+					Return(files, nil)
+			},
+			expectedMismatches: expectedMismatches[:1],
+		},
+		"dry_run_should_only_report_mismatches": {
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+				mockRepo.EXPECT().FindFileDetailsAfterID(gomock.Any(), uuid.Nil.String(), reconcileBatchSize).Times(1).
+					Return(files, nil)
+
+				// objects without file details of an active upload session are expected
+				mockRepo.EXPECT().FindUploadSessionByID(gomock.Any(), sessionID.String()).Times(1).
+					Return(domain.UploadSession{ID: sessionID}, nil)
+				mockRepo.EXPECT().FindUploadSessionByID(gomock.Any(), orphanID.String()).Times(1).
+					Return(domain.UploadSession{}, nil)
+			},
+			expectedMismatches: expectedMismatches,
+		},
+		"repair_should_fix_mismatches": {
+			repair: true,
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+				mockRepo.EXPECT().FindFileDetailsAfterID(gomock.Any(), uuid.Nil.String(), reconcileBatchSize).Times(1).
+					Return(files, nil)
+				mockRepo.EXPECT().FindUploadSessionByID(gomock.Any(), sessionID.String()).Times(1).
+					Return(domain.UploadSession{ID: sessionID}, nil)
+				mockRepo.EXPECT().FindUploadSessionByID(gomock.Any(), orphanID.String()).Times(1).
+					Return(domain.UploadSession{}, nil)
+
+				// expecting the missing and corrupted files marked as failed
+				mockRepo.EXPECT().UpdateFileDetailsStatus(gomock.Any(), missingID.String(), domain.UploadStatusFailed,
+					ErrFileMissing.Error()).Times(1).Return(nil)
+				mockRepo.EXPECT().UpdateFileDetailsStatus(gomock.Any(), corruptID.String(), domain.UploadStatusFailed,
+					gomock.Any()).Times(1).Return(nil)
+			},
+			expectedMismatches: func() []response.Mismatch {
+				repaired := append([]response.Mismatch{}, expectedMismatches...)
+				for i := range repaired {
+					repaired[i].Repaired = true
+				}
+				return repaired
+			}(),
+			// only the valid file and session objects should keep
+			expectedKeys: []string{fileKey(validID.String()), sessionPartKey(sessionID.String(), 0)},
+		},
+	}
+
+	for name, test := range testCases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			ctl := gomock.NewController(t)
+			mockRepo := mock_repo.NewMockStreamRepository(ctl)
+			memStorage := storage.NewMemoryBackend()
+			for key, data := range storedKeys {
+				_, err := memStorage.Put(context.Background(), key, strings.NewReader(data))
+				assert.NoError(t, err)
+			}
+
+			test.buildStub(mockRepo)
+			streamUseCase := NewStreamUseCase(config.Config{ReconcileGracePeriod: test.gracePeriod},
+				mockRepo, memStorage, discardLogger, metrics.NewMetrics())
+
+			report, err := streamUseCase.Reconcile(context.Background(), test.repair)
+
+			if test.expectedError != nil {
+				assert.ErrorContains(t, err, test.expectedError.Error())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, len(files), report.CheckedFiles)
+			assert.Equal(t, len(storedKeys), report.CheckedObjects)
+			assert.Equal(t, test.expectedMismatches, report.Mismatches)
+
+			if test.repair {
+				var keys []string
+				for _, key := range memStorage.Keys() {
+					if !strings.HasPrefix(key, tempFilesPrefix) {
+						keys = append(keys, key)
+					}
+				}
+				assert.ElementsMatch(t, test.expectedKeys, keys)
+			}
+		})
+	}
+}
@@ -2,33 +2,70 @@ package usecase
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
+	"stream-service/pkg/auth"
+	"stream-service/pkg/config"
 	"stream-service/pkg/domain"
-	"stream-service/pkg/file"
+	"stream-service/pkg/logger"
+	"stream-service/pkg/metrics"
 	"stream-service/pkg/models/request"
+	"stream-service/pkg/models/response"
 	repointerface "stream-service/pkg/repository/interfaces"
+	"stream-service/pkg/storage"
 	"stream-service/pkg/usecase/interfaces"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type streamUseCase struct {
-	repo        repointerface.StreamRepository
-	fileHandler file.Handler
+	logger          *slog.Logger
+	metrics         *metrics.Metrics
+	repo            repointerface.StreamRepository
+	storage         storage.Backend
+	retentionPeriod time.Duration // time to keep the deleted files before purge
+	recoveryGrace   time.Duration // min age of the temporary files to recover
+	reconcileGrace  time.Duration // min age of the files and objects to reconcile
+	sessionExpiry   time.Duration // time to keep a not completed upload session after the last upload
 }
 
 var (
-	funcMaxWait = time.Second * 5
-	uploadDir   = "./uploads/"
+	// max time to wait for the data on upload stream
+	uploadIdleTimeout = time.Second * 5
+
+	// time to hold the claim of an upload session, extended while the upload in progress
+	uploadSessionClaimTimeout = time.Minute
+
+	// size of data to store and acknowledge the progress
+	progressAckSize int64 = 1024 * 1024
+
+	defaultListLimit = 20
+	maxListLimit     = 100
+
+	// max files to purge on a batch
+	purgeBatchSize = 100
 )
 
-func NewStreamUseCase(repo repointerface.StreamRepository, fileHandler file.Handler) interfaces.StreamUseCase {
+// key prefix of the files while uploading
+const tempFilesPrefix = "tmp/"
+
+func NewStreamUseCase(cfg config.Config, repo repointerface.StreamRepository, backend storage.Backend,
+	logger *slog.Logger, metrics *metrics.Metrics) interfaces.StreamUseCase {
 	return &streamUseCase{
-		repo:        repo,
-		fileHandler: fileHandler,
+		logger:          logger,
+		metrics:         metrics,
+		repo:            repo,
+		storage:         backend,
+		retentionPeriod: cfg.FileRetentionPeriod,
+		recoveryGrace:   cfg.UploadRecoveryGracePeriod,
+		reconcileGrace:  cfg.ReconcileGracePeriod,
+		sessionExpiry:   cfg.UploadSessionExpiry,
 	}
 }
 
@@ -38,9 +75,11 @@ func (s *streamUseCase) UploadFileDetails(ctx context.Context, details request.F
 
 	fileDetails := domain.FileDetails{
 		ID:          fileID,
+		Owner:       auth.OwnerFromContext(ctx),
 		Name:        details.Name,
 		ContentType: details.ContentType,
 		UploadedAt:  time.Now(),
+		Status:      domain.UploadStatusPending,
 	}
 
 	// save file details on database
@@ -52,60 +91,989 @@ func (s *streamUseCase) UploadFileDetails(ctx context.Context, details request.F
 	return fileID.String(), nil
 }
 
-func (s *streamUseCase) UploadFileAsStream(ctx context.Context, fileID string,
-	dataChan <-chan []byte, errChan chan error) {
+func (s *streamUseCase) UploadFileAsStream(ctx context.Context, fileID string, expected request.Checksum,
+	stream io.Reader, progress func(response.UploadProgress)) error {
+
+	if err := s.startFileUpload(ctx, fileID); err != nil {
+		return err
+	}
+
+	// store the data from stream and compute the checksum of the data handed to storage,
+	// the progress reported on each ack size of data durably stored
+	checksum := newChecksumWriter()
+	var stored func(int64)
+	if progress != nil {
+		stored = func(written int64) {
+			progress(response.UploadProgress{Written: written})
+		}
+	}
+	size, err := s.putStream(ctx, tempFileKey(fileID), false, stream, checksum, stored)
+	if err != nil {
+		s.failFileUpload(ctx, fileID, err)
+		return err
+	}
+
+	// all the data received, so complete the upload even if the stream cancelled after
+	if err := s.completeFileUpload(context.WithoutCancel(ctx), fileID, expected, checksum.sum()); err != nil {
+		return err
+	}
+
+	if progress != nil {
+		progress(response.UploadProgress{Written: size, Completed: true})
+	}
+
+	return nil
+}
+
+// To mark the file details as uploading
+func (s *streamUseCase) startFileUpload(ctx context.Context, fileID string) error {
+
+	err := s.repo.UpdateFileDetailsStatus(ctx, fileID, domain.UploadStatusUploading, "")
+	if err != nil {
+		return fmt.Errorf("failed to update file upload status on database: %w", err)
+	}
+
+	return nil
+}
+
+// To verify the checksum of the uploaded file and complete the file details with it,
+// then commit the temporary file to the file key.
+// the file data will be removed and upload marked as failed if the checksum not matching
+func (s *streamUseCase) completeFileUpload(ctx context.Context, fileID string, expected request.Checksum,
+	actual checksum) error {
+
+	if err := verifyChecksum(expected, actual); err != nil {
+		s.deleteObjects(ctx, fileID)
+		s.failFileUpload(ctx, fileID, err)
+		return err
+	}
+
+	id, _ := uuid.Parse(fileID)
+	completedAt := time.Now()
+	err := s.repo.CompleteFileDetails(ctx, domain.FileDetails{
+		ID:          id,
+		Size:        actual.size,
+		SHA256:      actual.sha256,
+		CRC32C:      actual.crc32c,
+		CompletedAt: &completedAt,
+	})
+	if err != nil {
+		err = fmt.Errorf("failed to complete file details on database: %w", err)
+		s.discardFileUpload(ctx, fileID, err)
+		return err
+	}
+
+	return s.commitFile(ctx, fileID)
+}
+
+// To move the temporary file to the file key after the file details completed,
+// so a file partially written never exist on the file key
+func (s *streamUseCase) commitFile(ctx context.Context, fileID string) error {
+
+	if err := s.storage.Rename(ctx, tempFileKey(fileID), fileKey(fileID)); err != nil {
+		err = fmt.Errorf("failed to commit the file on storage: %w", err)
+		s.failFileUpload(ctx, fileID, err)
+		return err
+	}
+
+	return nil
+}
+
+// To remove the temporary file of an upload which can't complete and mark the upload as failed
+func (s *streamUseCase) discardFileUpload(ctx context.Context, fileID string, reason error) {
+
+	if err := s.storage.Delete(ctx, tempFileKey(fileID)); err != nil {
+		s.logger.ErrorContext(ctx, "failed to delete temporary file from storage", logger.KeyFileID, fileID,
+			logger.KeyError, err)
+	}
+	s.failFileUpload(ctx, fileID, reason)
+}
+
+// To mark the file upload as aborted if the stream cancelled or its deadline exceeded,
+// otherwise as failed with the reason.
+// using a new context because the stream context can be already cancelled
+func (s *streamUseCase) failFileUpload(ctx context.Context, fileID string, reason error) {
+
+	status := domain.UploadStatusFailed
+	if errors.Is(reason, context.Canceled) || errors.Is(reason, context.DeadlineExceeded) {
+		status = domain.UploadStatusAborted
+	}
+
+	err := s.repo.UpdateFileDetailsStatus(context.WithoutCancel(ctx), fileID, status, reason.Error())
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to update file upload status", logger.KeyFileID, fileID,
+			"status", status, logger.KeyError, err)
+	}
+}
+
+// To remove an uploaded file and its details which not completed successfully
+func (s *streamUseCase) removeUploadedFile(ctx context.Context, fileID string) {
+
+	s.deleteObjects(ctx, fileID)
+	if err := s.repo.DeleteFileDetails(ctx, fileID); err != nil {
+		s.logger.ErrorContext(ctx, "failed to delete file details from database", logger.KeyFileID, fileID,
+			logger.KeyError, err)
+	}
+}
+
+// To delete all the stored objects of the file (temporary file, file data and session parts).
+// returns false if any of the objects failed to delete
+func (s *streamUseCase) deleteObjects(ctx context.Context, fileID string) bool {
+
+	if err := s.storage.Delete(ctx, tempFileKey(fileID)); err != nil {
+		s.logger.ErrorContext(ctx, "failed to delete temporary file from storage", logger.KeyFileID, fileID,
+			logger.KeyError, err)
+		return false
+	}
+
+	objects, err := s.storage.List(ctx, objectsPrefix(fileID))
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to list objects of file from storage", logger.KeyFileID, fileID,
+			logger.KeyError, err)
+		return false
+	}
+
+	deleted := true
+	for _, object := range objects {
+		if err := s.storage.Delete(ctx, object.Key); err != nil {
+			s.logger.ErrorContext(ctx, "failed to delete object from storage", logger.KeyFileID, fileID,
+				"key", object.Key, logger.KeyError, err)
+			deleted = false
+		}
+	}
+
+	return deleted
+}
+
+// To store the data read from the stream as an object until the stream completed,
+// the data handed to storage written to the observer (if not nil) and the size of the data durably stored
+// reported to the progress (if not nil) on each ack size of data.
+// the data received before a stream error partially stored if keep partial is true.
+// returns the size of stored data and the reason if the stream not completed or failed to store
+func (s *streamUseCase) putStream(ctx context.Context, key string, keepPartial bool, stream io.Reader,
+	observer io.Writer, progress func(stored int64)) (int64, error) {
+
+	idleReader := newIdleTimeoutReader(ctx, stream, uploadIdleTimeout)
+	defer idleReader.Close()
+
+	var (
+		reader  io.Reader = idleReader
+		partial *partialReader
+	)
+	if keepPartial {
+		partial = &partialReader{reader: reader}
+		reader = partial
+	}
+	if observer != nil {
+		reader = io.TeeReader(reader, observer)
+	}
+
+	// storage not using the stream context to store the partial data after the stream cancelled
+	var (
+		size int64
+		err  error
+	)
+	if progress != nil {
+		size, err = s.storage.PutWithProgress(context.WithoutCancel(ctx), key, reader, progressAckSize, progress)
+	} else {
+		size, err = s.storage.Put(context.WithoutCancel(ctx), key, reader)
+	}
+	if err == nil {
+		s.metrics.BytesWritten.Add(float64(size))
+	}
+	// the reason of the stream ended is the error if the stream not completed
+	if streamErr := idleReader.Err(); streamErr != nil {
+		if keepPartial && err == nil {
+			return size, streamErr
+		}
+		return 0, streamErr
+	}
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrStorageWrite, err)
+	}
+
+	return size, nil
+}
+
+func (s *streamUseCase) DownloadFile(ctx context.Context, fileID string) (response.FileDetails, io.ReadCloser, error) {
+
+	// find the file details from database
+	details, err := s.findCompletedFileDetails(ctx, fileID)
+	if err != nil {
+		return response.FileDetails{}, nil, err
+	}
+
+	// get the stored file data to read
+	file, err := s.storage.GetRange(ctx, fileKey(details.ID.String()), 0, -1)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return response.FileDetails{}, nil, ErrFileNotFound
+		}
+		return response.FileDetails{}, nil, fmt.Errorf("failed to get file from storage: %w", err)
+	}
+
+	return toFileDetailsResponse(details), file, nil
+}
+
+func (s *streamUseCase) GetFileDetails(ctx context.Context, fileID string) (response.FileDetails, error) {
+
+	details, err := s.findFileDetails(ctx, fileID)
+	if err != nil {
+		return response.FileDetails{}, err
+	}
+
+	return toFileDetailsResponse(details), nil
+}
+
+func (s *streamUseCase) ListFiles(ctx context.Context, req request.ListFiles) (response.FileList, error) {
+
+	filter := request.FileFilter{
+		Owner:          auth.OwnerFromContext(ctx),
+		ContentType:    req.ContentType,
+		NamePrefix:     req.NamePrefix,
+		UploadedAfter:  req.UploadedAfter,
+		UploadedBefore: req.UploadedBefore,
+		SortBy:         req.SortBy,
+		Descending:     req.Descending,
+		Deleted:        req.Deleted,
+		Limit:          req.Limit,
+	}
+
+	switch filter.SortBy {
+	case "":
+		filter.SortBy = request.SortByUploadedAt
+	case request.SortByUploadedAt, request.SortByName:
+	default:
+		return response.FileList{}, ErrInvalidSortField
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultListLimit
+	} else if filter.Limit > maxListLimit {
+		filter.Limit = maxListLimit
+	}
+
+	if req.Cursor != "" {
+		cursor, err := decodeFileCursor(req.Cursor)
+		if err != nil {
+			return response.FileList{}, ErrInvalidCursor
+		}
+		filter.After = &cursor
+	}
+
+	// find one more file than limit to know there is a next page or not
+	limit := filter.Limit
+	filter.Limit++
+
+	files, err := s.repo.FindAllFileDetails(ctx, filter)
+	if err != nil {
+		return response.FileList{}, fmt.Errorf("failed to find file details from database: %w", err)
+	}
+
+	var fileList response.FileList
+	if len(files) > limit {
+		files = files[:limit]
+		last := files[limit-1]
+		fileList.NextCursor = encodeFileCursor(request.FileCursor{
+			ID:         last.ID.String(),
+			Name:       last.Name,
+			UploadedAt: last.UploadedAt,
+		})
+	}
+
+	fileList.Files = make([]response.FileDetails, len(files))
+	for i := range files {
+		fileList.Files[i] = toFileDetailsResponse(files[i])
+	}
+
+	return fileList, nil
+}
+
+func (s *streamUseCase) findFileDetails(ctx context.Context, fileID string) (domain.FileDetails, error) {
 
-	// create folder path and folder
-	folderPath := generateFolderPath(fileID)
-	if err := s.fileHandler.MkdirAll(folderPath, 0755); err != nil {
-		errChan <- fmt.Errorf("failed to create directory for upload file: %w", err)
-		return
+	id, err := uuid.Parse(fileID)
+	if err != nil {
+		return domain.FileDetails{}, ErrInvalidFileID
+	}
+
+	details, err := s.repo.FindFileDetailsByID(ctx, id.String())
+	if err != nil {
+		return domain.FileDetails{}, fmt.Errorf("failed to find file details from database: %w", err)
+	}
+	// deleted files are not visible until restored, and files of the other owners never visible
+	if details.ID == uuid.Nil || details.DeletedAt != nil || details.Owner != auth.OwnerFromContext(ctx) {
+		return domain.FileDetails{}, ErrFileNotFound
 	}
 
-	// create file path and file
-	filePath := generateFilePath(folderPath, fileID)
-	file, err := s.fileHandler.Create(filePath)
+	return details, nil
+}
+
+// To find the file details which upload completed, other uploads are not visible for file operations
+func (s *streamUseCase) findCompletedFileDetails(ctx context.Context, fileID string) (domain.FileDetails, error) {
+
+	details, err := s.findFileDetails(ctx, fileID)
 	if err != nil {
-		errChan <- fmt.Errorf("failed to create file details on server: %w", err)
-		return
+		return domain.FileDetails{}, err
+	}
+	if details.Status != domain.UploadStatusCompleted {
+		return domain.FileDetails{}, ErrFileNotFound
 	}
-	defer file.Close()
 
-	// start reading data
-	var buffer []byte
+	return details, nil
+}
+
+func (s *streamUseCase) DeleteFile(ctx context.Context, fileID string) (time.Time, error) {
+
+	details, err := s.findCompletedFileDetails(ctx, fileID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// move the file to trash, it will remove permanently by purge after retention period
+	deletedAt := time.Now()
+	err = s.repo.SoftDeleteFileDetails(ctx, details.ID.String(), deletedAt)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to delete file details on database: %w", err)
+	}
+
+	return deletedAt.Add(s.retentionPeriod), nil
+}
+
+func (s *streamUseCase) RestoreFile(ctx context.Context, fileID string) (response.FileDetails, error) {
+
+	id, err := uuid.Parse(fileID)
+	if err != nil {
+		return response.FileDetails{}, ErrInvalidFileID
+	}
+
+	details, err := s.repo.FindFileDetailsByID(ctx, id.String())
+	if err != nil {
+		return response.FileDetails{}, fmt.Errorf("failed to find file details from database: %w", err)
+	}
+	if details.ID == uuid.Nil || details.Owner != auth.OwnerFromContext(ctx) {
+		return response.FileDetails{}, ErrFileNotFound
+	}
+	if details.DeletedAt == nil {
+		return response.FileDetails{}, ErrFileNotDeleted
+	}
+
+	err = s.repo.RestoreFileDetails(ctx, id.String())
+	if err != nil {
+		return response.FileDetails{}, fmt.Errorf("failed to restore file details on database: %w", err)
+	}
+	details.DeletedAt = nil
+
+	return toFileDetailsResponse(details), nil
+}
+
+func (s *streamUseCase) PurgeDeletedFiles(ctx context.Context) (int, error) {
+
+	deletedBefore := time.Now().Add(-s.retentionPeriod)
+
+	var purged int
 	for {
-		select {
-		case buffer = <-dataChan: // get data
-			// write data on file
-			_, err := file.Write(buffer)
-			if err != nil {
-				errChan <- fmt.Errorf("failed to write data on file: %w", err)
-				return
+		files, err := s.repo.FindDeletedFileDetails(ctx, deletedBefore, purgeBatchSize)
+		if err != nil {
+			return purged, fmt.Errorf("failed to find deleted file details from database: %w", err)
+		}
+
+		var batchPurged int
+		for _, details := range files {
+			fileID := details.ID.String()
+			// first remove the file from storage then the details, so a failure can retry on next purge
+			if !s.deleteObjects(ctx, fileID) {
+				continue
+			}
+			if err := s.repo.DeleteFileDetails(ctx, fileID); err != nil {
+				s.logger.ErrorContext(ctx, "failed to delete file details from database", logger.KeyFileID, fileID,
+					logger.KeyError, err)
+				continue
+			}
+			batchPurged++
+		}
+		purged += batchPurged
+
+		// stop when no more files or nothing purged on this batch to avoid retrying the same files
+		if len(files) < purgeBatchSize || batchPurged == 0 {
+			return purged, nil
+		}
+	}
+}
+
+func (s *streamUseCase) RecoverUploads(ctx context.Context) (int, error) {
+
+	objects, err := s.storage.List(ctx, tempFilesPrefix)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list temporary files from storage: %w", err)
+	}
+
+	var recovered int
+	for _, object := range objects {
+		// skip the recently modified files, which can be uploading by another instance
+		if time.Since(object.ModifiedAt) < s.recoveryGrace {
+			continue
+		}
+		if s.recoverUpload(ctx, strings.TrimPrefix(object.Key, tempFilesPrefix)) {
+			recovered++
+		}
+	}
+
+	return recovered, nil
+}
+
+// To commit the leftover temporary file if the file details already completed,
+// otherwise remove it and mark the interrupted upload as failed.
+// returns false if failed to recover
+func (s *streamUseCase) recoverUpload(ctx context.Context, fileID string) bool {
+
+	var details domain.FileDetails
+	if _, err := uuid.Parse(fileID); err == nil {
+		details, err = s.repo.FindFileDetailsByID(ctx, fileID)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "failed to find file details from database", logger.KeyFileID, fileID,
+				logger.KeyError, err)
+			return false
+		}
+	}
+
+	// completed before the commit, so resume the commit
+	if details.Status == domain.UploadStatusCompleted {
+		if err := s.commitFile(ctx, fileID); err != nil {
+			s.logger.ErrorContext(ctx, "failed to commit recovered file", logger.KeyFileID, fileID, logger.KeyError, err)
+			return false
+		}
+		s.logger.InfoContext(ctx, "committed recovered file", logger.KeyFileID, fileID)
+		return true
+	}
+
+	if err := s.storage.Delete(ctx, tempFileKey(fileID)); err != nil {
+		s.logger.ErrorContext(ctx, "failed to delete temporary file from storage", logger.KeyFileID, fileID,
+			logger.KeyError, err)
+		return false
+	}
+	if details.Status == domain.UploadStatusUploading {
+		s.failFileUpload(ctx, fileID, ErrUploadInterrupted)
+	}
+	s.logger.InfoContext(ctx, "removed interrupted upload", logger.KeyFileID, fileID)
+
+	return true
+}
+
+func (s *streamUseCase) CreateUploadSession(ctx context.Context, details request.FileDetails) (string, error) {
+
+	sessionID := uuid.New()
+
+	session := domain.UploadSession{
+		ID:             sessionID,
+		Owner:          auth.OwnerFromContext(ctx),
+		Name:           details.Name,
+		ContentType:    details.ContentType,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+		ExpectedSHA256: details.Checksum.SHA256,
+		ExpectedCRC32C: details.Checksum.CRC32C,
+		ExpectedSize:   details.Checksum.Size,
+	}
+
+	// save upload session on database
+	err := s.repo.SaveUploadSession(ctx, session)
+	if err != nil {
+		return "", fmt.Errorf("failed to save upload session on database: %w", err)
+	}
+
+	return sessionID.String(), nil
+}
+
+func (s *streamUseCase) GetUploadSession(ctx context.Context, sessionID string) (response.UploadSession, error) {
+
+	session, err := s.findUploadSession(ctx, sessionID)
+	if err != nil {
+		return response.UploadSession{}, err
+	}
+
+	if s.isUploadSessionExpired(session) {
+		return response.UploadSession{}, ErrUploadSessionExpired
+	}
+
+	return response.UploadSession{
+		ID:        session.ID.String(),
+		Offset:    session.CommittedOffset,
+		Completed: session.Completed,
+		Size:      session.ExpectedSize,
+		ExpiresAt: s.uploadSessionExpiresAt(session),
+	}, nil
+}
+
+func (s *streamUseCase) CheckUploadSessionOffset(ctx context.Context, sessionID string,
+	offset int64) (response.UploadSession, error) {
+
+	session, err := s.findUploadSession(ctx, sessionID)
+	if err != nil {
+		return response.UploadSession{}, err
+	}
+
+	if session.Completed {
+		return response.UploadSession{}, ErrUploadSessionCompleted
+	}
+	if s.isUploadSessionExpired(session) {
+		return response.UploadSession{}, ErrUploadSessionExpired
+	}
+	// upload only can resume from the committed offset
+	if session.CommittedOffset != offset {
+		return response.UploadSession{}, fmt.Errorf("%w: committed offset is %d", ErrUploadOffsetMismatch,
+			session.CommittedOffset)
+	}
+
+	return response.UploadSession{
+		ID:        session.ID.String(),
+		Offset:    session.CommittedOffset,
+		Size:      session.ExpectedSize,
+		ExpiresAt: s.uploadSessionExpiresAt(session),
+	}, nil
+}
+
+func (s *streamUseCase) UploadSessionAsStream(ctx context.Context, sessionID string, offset int64,
+	stream io.Reader) error {
+
+	release, err := s.claimUploadSession(ctx, sessionID, offset)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	// remove the parts after the committed offset which stored before a failure
+	if err := s.removeSessionParts(ctx, sessionID, offset); err != nil {
+		return err
+	}
+
+	// store the data as the part of committed offset
+	stored, err := s.putStream(ctx, sessionPartKey(sessionID, offset), true, stream, nil, nil)
+	offset += stored
+
+	// all the data received, so complete the session even if the stream cancelled after
+	if err == nil {
+		return s.completeUploadSession(context.WithoutCancel(ctx), sessionID, offset)
+	}
+
+	// save the committed offset to resume the upload later
+	// using a new context because the stream context can be already cancelled
+	if err := s.repo.UpdateUploadSessionOffset(context.WithoutCancel(ctx), sessionID, offset); err != nil {
+		s.logger.ErrorContext(ctx, "failed to update upload session offset", logger.KeyFileID, sessionID,
+			logger.KeyError, err)
+	}
+
+	return err
+}
+
+func (s *streamUseCase) UploadSessionPartAsStream(ctx context.Context, sessionID string, offset int64,
+	expected *request.PartChecksum, stream io.Reader) (response.UploadSession, error) {
+
+	partHash, err := newPartHash(expected)
+	if err != nil {
+		return response.UploadSession{}, err
+	}
+
+	release, err := s.claimUploadSession(ctx, sessionID, offset)
+	if err != nil {
+		return response.UploadSession{}, err
+	}
+	defer release()
+
+	// remove the parts after the committed offset which stored before a failure
+	if err := s.removeSessionParts(ctx, sessionID, offset); err != nil {
+		return response.UploadSession{}, err
+	}
+
+	// store the data as the part of committed offset,
+	// the data received before a stream error kept only if the part not verified with checksum
+	key := sessionPartKey(sessionID, offset)
+	var observer io.Writer
+	if partHash != nil {
+		observer = partHash
+	}
+	stored, err := s.putStream(ctx, key, partHash == nil, stream, observer, nil)
+	if err == nil && partHash != nil {
+		if err = verifyPartChecksum(expected, partHash); err != nil {
+			// the part not matching the checksum never committed
+			if err := s.storage.Delete(context.WithoutCancel(ctx), key); err != nil {
+				s.logger.ErrorContext(ctx, "failed to delete upload session part from storage", logger.KeyFileID, sessionID,
+					"key", key, logger.KeyError, err)
 			}
-		case err = <-errChan:
-			if err == io.EOF { // if EOF means stream completed so sending file id
-				log.Println("EOF received on usecase and returning")
+			stored = 0
+		}
+	}
+	offset += stored
+
+	// save the committed offset to resume the upload later
+	// using a new context because the stream context can be already cancelled
+	if err != nil {
+		if err := s.repo.UpdateUploadSessionOffset(context.WithoutCancel(ctx), sessionID, offset); err != nil {
+			s.logger.ErrorContext(ctx, "failed to update upload session offset", logger.KeyFileID, sessionID,
+				logger.KeyError, err)
+		}
+		return response.UploadSession{}, err
+	}
+
+	// all the data of the part received, so commit it even if the stream cancelled after
+	ctx = context.WithoutCancel(ctx)
+	session, err := s.findUploadSession(ctx, sessionID)
+	if err != nil {
+		return response.UploadSession{}, err
+	}
+
+	// wait for the next parts until the declared size received
+	if session.ExpectedSize == nil || offset < *session.ExpectedSize {
+		if err := s.repo.UpdateUploadSessionOffset(ctx, sessionID, offset); err != nil {
+			return response.UploadSession{}, fmt.Errorf("failed to update upload session offset: %w", err)
+		}
+		session.UpdatedAt = time.Now()
+		return response.UploadSession{
+			ID:        sessionID,
+			Offset:    offset,
+			Size:      session.ExpectedSize,
+			ExpiresAt: s.uploadSessionExpiresAt(session),
+		}, nil
+	}
+
+	if err := s.completeUploadSession(ctx, sessionID, offset); err != nil {
+		return response.UploadSession{}, err
+	}
+
+	return response.UploadSession{
+		ID:        sessionID,
+		Offset:    offset,
+		Completed: true,
+		Size:      session.ExpectedSize,
+	}, nil
+}
+
+func (s *streamUseCase) DeleteUploadSession(ctx context.Context, sessionID string) error {
+
+	session, err := s.findUploadSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	// the completed file can only delete as a file
+	if session.Completed {
+		return ErrUploadSessionCompleted
+	}
+
+	// first remove the parts from storage then the session, so a failure can retry
+	if !s.deleteObjects(ctx, sessionID) {
+		return errors.New("failed to delete upload session parts from storage")
+	}
+	if err := s.repo.DeleteFileDetails(ctx, sessionID); err != nil {
+		return fmt.Errorf("failed to delete upload session from database: %w", err)
+	}
+
+	return nil
+}
+
+func (s *streamUseCase) PurgeExpiredUploadSessions(ctx context.Context) (int, error) {
+
+	// sessions kept forever if no expiry
+	if s.sessionExpiry <= 0 {
+		return 0, nil
+	}
+
+	updatedBefore := time.Now().Add(-s.sessionExpiry)
+
+	var purged int
+	for {
+		sessions, err := s.repo.FindExpiredUploadSessions(ctx, updatedBefore, purgeBatchSize)
+		if err != nil {
+			return purged, fmt.Errorf("failed to find expired upload sessions from database: %w", err)
+		}
+
+		var batchPurged int
+		for _, session := range sessions {
+			sessionID := session.ID.String()
+			// first remove the parts from storage then the session, so a failure can retry on next purge
+			if !s.deleteObjects(ctx, sessionID) {
+				continue
+			}
+			if err := s.repo.DeleteFileDetails(ctx, sessionID); err != nil {
+				s.logger.ErrorContext(ctx, "failed to delete upload session from database", logger.KeyFileID, sessionID,
+					logger.KeyError, err)
+				continue
+			}
+			batchPurged++
+		}
+		purged += batchPurged
+
+		// stop when no more sessions or nothing purged on this batch to avoid retrying the same sessions
+		if len(sessions) < purgeBatchSize || batchPurged == 0 {
+			return purged, nil
+		}
+	}
+}
+
+// To check the not completed session expired after the last upload
+func (s *streamUseCase) isUploadSessionExpired(session domain.UploadSession) bool {
+	expiresAt := s.uploadSessionExpiresAt(session)
+	return !expiresAt.IsZero() && time.Now().After(expiresAt)
+}
+
+// To get the expire time of the session, zero if completed or not expire
+func (s *streamUseCase) uploadSessionExpiresAt(session domain.UploadSession) time.Time {
+	if session.Completed || s.sessionExpiry <= 0 {
+		return time.Time{}
+	}
+	return session.UpdatedAt.Add(s.sessionExpiry)
+}
+
+// To claim the upload session to store the data from the committed offset, so the concurrent uploads
+// of the session can not store the same part. the claim extended until the returned release called
+func (s *streamUseCase) claimUploadSession(ctx context.Context, sessionID string, offset int64) (func(), error) {
+
+	claimed, err := s.repo.ClaimUploadSession(ctx, sessionID, offset, time.Now().Add(uploadSessionClaimTimeout))
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim upload session: %w", err)
+	}
+	if !claimed {
+		return nil, fmt.Errorf("%w: upload session resumed by another upload", ErrUploadOffsetMismatch)
+	}
+
+	// using a new context to extend and release the claim after the stream cancelled
+	ctx = context.WithoutCancel(ctx)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(uploadSessionClaimTimeout / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
 				return
+			case <-ticker.C:
+				err := s.repo.ExtendUploadSessionClaim(ctx, sessionID, time.Now().Add(uploadSessionClaimTimeout))
+				if err != nil {
+					s.logger.ErrorContext(ctx, "failed to extend upload session claim", logger.KeyFileID, sessionID,
+						logger.KeyError, err)
+				}
 			}
-			log.Println("received error while receiving data on stream: ", err)
-			return
-		case <-ctx.Done(): // check return signal from context to cancel then return
-			log.Println("usecase returned by cancel signal")
-			return
-		case <-time.After(funcMaxWait): // exit from the function if nothing happened for max function wait time
-			log.Println("stream usecase function time out")
-			return
+		}
+	}()
+
+	return func() {
+		close(stop)
+		<-done
+		if err := s.repo.ReleaseUploadSession(ctx, sessionID); err != nil {
+			s.logger.ErrorContext(ctx, "failed to release upload session", logger.KeyFileID, sessionID,
+				logger.KeyError, err)
+		}
+	}, nil
+}
+
+// To remove the session parts stored from the offset
+func (s *streamUseCase) removeSessionParts(ctx context.Context, sessionID string, offset int64) error {
+
+	parts, err := s.storage.List(ctx, sessionPartsPrefix(sessionID))
+	if err != nil {
+		return fmt.Errorf("failed to list upload session parts from storage: %w", err)
+	}
+
+	for _, part := range parts {
+		if part.Key < sessionPartKey(sessionID, offset) {
+			continue
+		}
+		if err := s.storage.Delete(ctx, part.Key); err != nil {
+			return fmt.Errorf("failed to delete upload session part from storage: %w", err)
 		}
 	}
+
+	return nil
 }
 
-// To generate folder path according to folder path and file id
-func generateFolderPath(fileID string) string {
-	return uploadDir + fileID
+// To complete the upload session after combining all the parts and verifying the size and checksum of the file.
+// the session kept resumable if the file is smaller than the declared size,
+// otherwise the file and session will be removed if the size or checksum not matching
+func (s *streamUseCase) completeUploadSession(ctx context.Context, sessionID string, offset int64) error {
+
+	session, err := s.findUploadSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	if session.ExpectedSize != nil && offset < *session.ExpectedSize {
+		if err := s.repo.UpdateUploadSessionOffset(ctx, sessionID, offset); err != nil {
+			return fmt.Errorf("failed to update upload session offset: %w", err)
+		}
+		return fmt.Errorf("%w: upload incomplete with %d of %d bytes", ErrSizeMismatch, offset, *session.ExpectedSize)
+	}
+
+	parts, err := s.storage.List(ctx, sessionPartsPrefix(sessionID))
+	if err != nil {
+		return fmt.Errorf("failed to list upload session parts from storage: %w", err)
+	}
+	keys := make([]string, len(parts))
+	for i := range parts {
+		keys[i] = parts[i].Key
+	}
+
+	return s.commitSessionParts(ctx, session, keys, nil)
 }
 
-// To generate file path
-func generateFilePath(folderPath, fileID string) string {
-	return folderPath + "/" + fileID
+// To combine the parts in order as the file of the session and complete the session after verifying
+// the checksum of the file and the parts (if the verifier not nil), then remove the parts.
+// the file and session will be removed if the checksum of the file not matching,
+// but only the file removed if a part not matching to upload the part again
+func (s *streamUseCase) commitSessionParts(ctx context.Context, session domain.UploadSession, keys []string,
+	verifier *partsVerifier) error {
+
+	sessionID := session.ID.String()
+
+	// combine all the parts as the file and compute the checksum while storing
+	checksum := newChecksumWriter()
+	observer := io.Writer(checksum)
+	if verifier != nil {
+		observer = io.MultiWriter(checksum, verifier)
+	}
+	partsReader := storage.NewConcatReader(ctx, s.storage, keys)
+	defer partsReader.Close()
+	if _, err := s.storage.Put(ctx, tempFileKey(sessionID), io.TeeReader(partsReader, observer)); err != nil {
+		return fmt.Errorf("failed to store the file from upload session parts: %w", err)
+	}
+	actual := checksum.sum()
+
+	if verifier != nil {
+		if err := verifier.verify(); err != nil {
+			if err := s.storage.Delete(ctx, tempFileKey(sessionID)); err != nil {
+				s.logger.ErrorContext(ctx, "failed to delete temporary file from storage", logger.KeyFileID, sessionID,
+					logger.KeyError, err)
+			}
+			return err
+		}
+	}
+
+	expected := request.Checksum{
+		Size:   session.ExpectedSize,
+		SHA256: session.ExpectedSHA256,
+		CRC32C: session.ExpectedCRC32C,
+	}
+	if err := verifyChecksum(expected, actual); err != nil {
+		s.removeUploadedFile(ctx, sessionID)
+		return err
+	}
+
+	completedAt := time.Now()
+	err := s.repo.CompleteUploadSession(ctx, domain.FileDetails{
+		ID:          session.ID,
+		UploadedAt:  completedAt,
+		CompletedAt: &completedAt,
+		Size:        actual.size,
+		SHA256:      actual.sha256,
+		CRC32C:      actual.crc32c,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to complete upload session: %w", err)
+	}
+	if err := s.commitFile(ctx, sessionID); err != nil {
+		return err
+	}
+
+	// parts are not needed after the file stored
+	for _, key := range keys {
+		if err := s.storage.Delete(ctx, key); err != nil {
+			s.logger.ErrorContext(ctx, "failed to delete upload session part from storage", logger.KeyFileID, sessionID,
+				"key", key, logger.KeyError, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *streamUseCase) findUploadSession(ctx context.Context, sessionID string) (domain.UploadSession, error) {
+
+	id, err := uuid.Parse(sessionID)
+	if err != nil {
+		return domain.UploadSession{}, ErrInvalidUploadSessionID
+	}
+
+	session, err := s.repo.FindUploadSessionByID(ctx, id.String())
+	if err != nil {
+		return domain.UploadSession{}, fmt.Errorf("failed to find upload session from database: %w", err)
+	}
+	// sessions of the other owners are not visible
+	if session.ID == uuid.Nil || session.Owner != auth.OwnerFromContext(ctx) {
+		return domain.UploadSession{}, ErrUploadSessionNotFound
+	}
+
+	return session, nil
+}
+
+func toFileDetailsResponse(details domain.FileDetails) response.FileDetails {
+
+	fileDetails := response.FileDetails{
+		ID:            details.ID.String(),
+		Owner:         details.Owner,
+		Name:          details.Name,
+		ContentType:   details.ContentType,
+		UploadedAt:    details.UploadedAt,
+		Size:          details.Size,
+		SHA256:        details.SHA256,
+		CRC32C:        details.CRC32C,
+		Status:        string(details.Status),
+		FailureReason: details.FailureReason,
+	}
+	if details.DeletedAt != nil {
+		fileDetails.DeletedAt = *details.DeletedAt
+	}
+	if details.CompletedAt != nil {
+		fileDetails.CompletedAt = *details.CompletedAt
+	}
+
+	return fileDetails
+}
+
+// To encode the cursor as an opaque string for client
+func encodeFileCursor(cursor request.FileCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeFileCursor(value string) (cursor request.FileCursor, err error) {
+
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return cursor, err
+	}
+	if err = json.Unmarshal(data, &cursor); err != nil {
+		return cursor, err
+	}
+	if _, err = uuid.Parse(cursor.ID); err != nil {
+		return cursor, err
+	}
+
+	return cursor, nil
+}
+
+// To generate the storage key of the file data
+func fileKey(fileID string) string {
+	return objectsPrefix(fileID) + fileID
+}
+
+// To generate the key of the file while uploading, moved to the file key after completed.
+// temporary files kept under a common prefix to find the leftovers on recovery
+func tempFileKey(fileID string) string {
+	return tempFilesPrefix + fileID
+}
+
+// To generate the key prefix of all the objects of a file
+func objectsPrefix(fileID string) string {
+	return fileID + "/"
+}
+
+// To generate the key prefix of the upload session parts
+func sessionPartsPrefix(sessionID string) string {
+	return objectsPrefix(sessionID) + "parts/"
+}
 
+// To generate the key of upload session part starting from the offset,
+// offset padded with zero to keep the parts sorted by key
+func sessionPartKey(sessionID string, offset int64) string {
+	return fmt.Sprintf("%s%020d", sessionPartsPrefix(sessionID), offset)
 }
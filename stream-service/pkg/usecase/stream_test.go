@@ -1,18 +1,40 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"hash/crc32"
 	"io"
-	"stream-service/pkg/mock/mock_file"
+	"log/slog"
+	"sort"
+	"stream-service/pkg/auth"
+	"stream-service/pkg/config"
+	"stream-service/pkg/domain"
+	"stream-service/pkg/metrics"
 	"stream-service/pkg/mock/mock_repo"
+	"stream-service/pkg/mock/mock_storage"
 	"stream-service/pkg/models/request"
+	"stream-service/pkg/models/response"
+	"stream-service/pkg/storage"
+	"strings"
+	"sync"
 	"testing"
+	"testing/iotest"
+	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 )
 
+// logger to discard the logs of the tests
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
 func TestUploadFileDetails(t *testing.T) {
 
 	testCases := map[string]struct {
@@ -36,7 +58,10 @@ func TestUploadFileDetails(t *testing.T) {
 		"successful_to_upload_return_id": {
 			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
 				mockRepo.EXPECT().SaveFileDetails(gomock.Any(), gomock.Any()).Times(1).
-					Return(nil)
+					DoAndReturn(func(ctx context.Context, details domain.FileDetails) error {
+						assert.Equal(t, "owner", details.Owner, "file should be saved with the owner")
+						return nil
+					})
 			},
 			input: request.FileDetails{
 				Name:        "File_Name",
@@ -57,9 +82,9 @@ func TestUploadFileDetails(t *testing.T) {
 			repo := mock_repo.NewMockStreamRepository(ctl)
 
 			test.buildStub(repo)
-			usecase := NewStreamUseCase(repo, nil)
+			usecase := NewStreamUseCase(config.Config{}, repo, nil, discardLogger, metrics.NewMetrics())
 
-			out, err := usecase.UploadFileDetails(context.TODO(), test.input)
+			out, err := usecase.UploadFileDetails(auth.ContextWithOwner(context.TODO(), "owner"), test.input)
 
 			if test.isExpectingOutput {
 				assert.NotEmpty(t, out, "expecting output of random generated file id")
@@ -81,267 +106,1560 @@ func TestUploadFileDetails(t *testing.T) {
 func TestUploadFileAsStream(t *testing.T) {
 
 	testCases := map[string]struct {
-		buildStub func(t *testing.T, mockFileHandler *mock_file.MockHandler)
-		input     string // initial file information
-		// data      []byte              // data which will stream
-		// running this function concurrently to get data
-		sendAndCheck  func(t *testing.T, cancel context.CancelFunc, dataChan chan<- []byte, errChan chan error, expError error)
+		buildStub func(t *testing.T, mockRepo *mock_repo.MockStreamRepository, mockStorage *mock_storage.MockBackend)
+		input     string           // initial file information
+		expected  request.Checksum // expected checksum of the file
+		// client stream to read the data
+		stream        func(t *testing.T, cancel context.CancelFunc) io.Reader
 		expectedError error
 	}{
-		"failed_to_create_dir_should_return_error": {
+		"failed_to_update_status_should_return_error": {
 			input: "file_id",
-			buildStub: func(t *testing.T, mockFileHandler *mock_file.MockHandler) {
-
-				folderPath := generateFolderPath("file_id")
-				// expect call mkdir and returning an error
-				mockFileHandler.EXPECT().MkdirAll(folderPath, gomock.Any()).Times(1).
-					Return(errors.New("failed to create directory"))
-			},
-			expectedError: errors.New("failed to create directory"),
-			sendAndCheck: func(t *testing.T, cancel context.CancelFunc, dataChan chan<- []byte,
-				errChan chan error, expError error) {
-
-				// wait for the error no data send
-				err := <-errChan
-				if expError != nil {
-					assert.ErrorContains(t, err, expError.Error())
-				} else {
-					assert.NoError(t, err)
-				}
+			buildStub: func(t *testing.T, mockRepo *mock_repo.MockStreamRepository,
+				mockStorage *mock_storage.MockBackend) {
+
+				// expect call to mark the upload as uploading and returning an error
+				mockRepo.EXPECT().UpdateFileDetailsStatus(gomock.Any(), "file_id", domain.UploadStatusUploading, "").
+					Times(1).Return(errors.New("db error"))
+			},
+			expectedError: errors.New("db error"),
+			stream: func(t *testing.T, cancel context.CancelFunc) io.Reader {
+				return strings.NewReader("")
 			},
 		},
-		"failed_to_create_file_should_return_error": {
+		"storage_error_should_return_error": {
 			input: "file_id",
-			buildStub: func(t *testing.T, mockFileHandler *mock_file.MockHandler) {
+			buildStub: func(t *testing.T, mockRepo *mock_repo.MockStreamRepository,
+				mockStorage *mock_storage.MockBackend) {
 
-				folderPath := generateFolderPath("file_id")
-				// expect call mkdir and returning no error
-				mockFileHandler.EXPECT().MkdirAll(folderPath, gomock.Any()).Times(1).
-					Return(nil)
+				// expect call to store the file and returning an error without reading data
+				mockStorage.EXPECT().Put(gomock.Any(), tempFileKey("file_id"), gomock.Any()).Times(1).
+					Return(int64(0), errors.New("storage error"))
 
-				filePath := generateFilePath(folderPath, "file_id")
-				// expecting call to create and returning nil file with an error
-				mockFileHandler.EXPECT().Create(filePath).Times(1).
-					Return(nil, errors.New("create file error"))
-			},
-			expectedError: errors.New("create file error"),
-			sendAndCheck: func(t *testing.T, cancel context.CancelFunc, dataChan chan<- []byte,
-				errChan chan error, expError error) {
-
-				// wait for the error no data send
-				err := <-errChan
-				if expError != nil {
-					assert.ErrorContains(t, err, expError.Error())
-				} else {
-					assert.NoError(t, err)
-				}
+				// expecting the upload marked as uploading and then failed
+				gomock.InOrder(
+					mockRepo.EXPECT().UpdateFileDetailsStatus(gomock.Any(), "file_id", domain.UploadStatusUploading, "").
+						Times(1).Return(nil),
+					mockRepo.EXPECT().UpdateFileDetailsStatus(gomock.Any(), "file_id", domain.UploadStatusFailed, gomock.Any()).
+						Times(1).Return(nil),
+				)
+			},
+			expectedError: ErrStorageWrite,
+			stream: func(t *testing.T, cancel context.CancelFunc) io.Reader {
+				return strings.NewReader("first data")
 			},
 		},
-		"file_write_error_should_return_error": {
+		"no_data_upload_will_function_return_after_max_function_wait": {
 			input: "file_id",
-			buildStub: func(t *testing.T, mockFileHandler *mock_file.MockHandler) {
-				folderPath := generateFolderPath("file_id")
-				// expect call mkdir and returning no error
-				mockFileHandler.EXPECT().MkdirAll(folderPath, gomock.Any()).Times(1).
-					Return(nil)
-
-				// create a mock file to send data
-				ctl := gomock.NewController(t)
-				mockFile := mock_file.NewMockFile(ctl)
-
-				// always expecting a call for closing file
-				mockFile.EXPECT().Close().Times(1).Return(nil)
+			buildStub: func(t *testing.T, mockRepo *mock_repo.MockStreamRepository,
+				mockStorage *mock_storage.MockBackend) {
 
-				// expect a call to mock file write and returning an error
-				mockFile.EXPECT().Write(gomock.Any()).Times(1).
-					Return(0, errors.New("failed to write data on file"))
+				mockStorage.EXPECT().Put(gomock.Any(), tempFileKey("file_id"), gomock.Any()).Times(1).
+					DoAndReturn(readAllPut)
 
-				filePath := generateFilePath(folderPath, "file_id")
+				// expecting the upload marked as uploading and then failed by time out
+				gomock.InOrder(
+					mockRepo.EXPECT().UpdateFileDetailsStatus(gomock.Any(), "file_id", domain.UploadStatusUploading, "").
+						Times(1).Return(nil),
+					mockRepo.EXPECT().UpdateFileDetailsStatus(gomock.Any(), "file_id", domain.UploadStatusFailed,
+						ErrUploadTimeout.Error()).Times(1).Return(nil),
+				)
+			},
+			expectedError: ErrUploadTimeout,
+			stream: func(t *testing.T, cancel context.CancelFunc) io.Reader {
+				/**
+				*  not sending data, the client who calling this function
+				*  not sending data will return with in the idle timeout on usecase
+				* if its not return with in 30s(test default timeout ) will fire an error by test
+				**/
+				return blockingStream(t, nil)
+			},
+		},
+		"client_side_error_send_on_chan_should_return": {
+			input: "file_id",
+			buildStub: func(t *testing.T, mockRepo *mock_repo.MockStreamRepository,
+				mockStorage *mock_storage.MockBackend) {
 
-				// expecting call to create and returning no mock file with error
-				mockFileHandler.EXPECT().Create(filePath).Times(1).
-					Return(mockFile, nil)
+				mockStorage.EXPECT().Put(gomock.Any(), tempFileKey("file_id"), gomock.Any()).Times(1).
+					DoAndReturn(readAllPut)
 
+				// expecting the upload marked as uploading and then failed
+				gomock.InOrder(
+					mockRepo.EXPECT().UpdateFileDetailsStatus(gomock.Any(), "file_id", domain.UploadStatusUploading, "").
+						Times(1).Return(nil),
+					mockRepo.EXPECT().UpdateFileDetailsStatus(gomock.Any(), "file_id", domain.UploadStatusFailed, gomock.Any()).
+						Times(1).Return(nil),
+				)
 			},
-			expectedError: errors.New("failed to write data on file"),
-			sendAndCheck: func(t *testing.T, cancel context.CancelFunc, dataChan chan<- []byte,
-				errChan chan error, expError error) {
-
-				// send one data through channel and wait for the error
-				dataChan <- []byte("first data")
-				err := <-errChan
-				if expError != nil {
-					assert.ErrorContains(t, err, expError.Error())
-				} else {
-					assert.NoError(t, err)
-				}
+			expectedError: errors.New("error on client side to send data"),
+			stream: func(t *testing.T, cancel context.CancelFunc) io.Reader {
+				// error from client stream after the first data should return the error
+				return io.MultiReader(strings.NewReader("data"),
+					iotest.ErrReader(errors.New("error on client side to send data")))
 			},
 		},
-		"no_data_upload_will_function_return_after_max_function_wait": {
+		"cancel_on_context_should_return_function": {
 			input: "file_id",
-			buildStub: func(t *testing.T, mockFileHandler *mock_file.MockHandler) {
-				folderPath := generateFolderPath("file_id")
-				// expect call mkdir and returning no error
-				mockFileHandler.EXPECT().MkdirAll(folderPath, gomock.Any()).Times(1).
-					Return(nil)
+			buildStub: func(t *testing.T, mockRepo *mock_repo.MockStreamRepository,
+				mockStorage *mock_storage.MockBackend) {
 
-				// create a mock file to send data
-				ctl := gomock.NewController(t)
-				mockFile := mock_file.NewMockFile(ctl)
+				mockStorage.EXPECT().Put(gomock.Any(), tempFileKey("file_id"), gomock.Any()).Times(1).
+					DoAndReturn(readAllPut)
 
-				// always expecting a call for closing file
-				mockFile.EXPECT().Close().Times(1).Return(nil)
+				// expecting the upload marked as uploading and then aborted
+				gomock.InOrder(
+					mockRepo.EXPECT().UpdateFileDetailsStatus(gomock.Any(), "file_id", domain.UploadStatusUploading, "").
+						Times(1).Return(nil),
+					mockRepo.EXPECT().UpdateFileDetailsStatus(gomock.Any(), "file_id", domain.UploadStatusAborted, gomock.Any()).
+						Times(1).Return(nil),
+				)
+			},
+			expectedError: context.Canceled,
+			stream: func(t *testing.T, cancel context.CancelFunc) io.Reader {
+				/**
+				* calling ctx cancel func while waiting for data should return usecase function
+				* if its not return with in 30s(test default timeout ) will fire an error by test
+				**/
+				return blockingStream(t, cancel, "data")
+			},
+		},
+		"successful_send_5_data_should_store_data": {
+			input: "file_id",
+			buildStub: func(t *testing.T, mockRepo *mock_repo.MockStreamRepository,
+				mockStorage *mock_storage.MockBackend) {
 
-				filePath := generateFilePath(folderPath, "file_id")
+				// expecting all the data stored as the temporary file
+				mockStorage.EXPECT().Put(gomock.Any(), tempFileKey("file_id"), gomock.Any()).Times(1).
+					DoAndReturn(func(ctx context.Context, key string, reader io.Reader) (int64, error) {
+						data, err := io.ReadAll(reader)
+						assert.Equal(t, strings.Repeat("data", 5), string(data))
+						return int64(len(data)), err
+					})
 
-				// expecting call to create and returning no mock file with error
-				mockFileHandler.EXPECT().Create(filePath).Times(1).
-					Return(mockFile, nil)
+				mockRepo.EXPECT().UpdateFileDetailsStatus(gomock.Any(), "file_id", domain.UploadStatusUploading, "").
+					Times(1).Return(nil)
 
+				// expecting the checksum of all data saved
+				sum := sha256.Sum256([]byte(strings.Repeat("data", 5)))
+				gomock.InOrder(
+					mockRepo.EXPECT().CompleteFileDetails(gomock.Any(), gomock.Any()).Times(1).
+						DoAndReturn(func(ctx context.Context, details domain.FileDetails) error {
+							assert.Equal(t, int64(20), details.Size)
+							assert.Equal(t, hex.EncodeToString(sum[:]), details.SHA256)
+							assert.Equal(t, crc32.Checksum([]byte(strings.Repeat("data", 5)),
+								crc32.MakeTable(crc32.Castagnoli)), details.CRC32C)
+							assert.NotNil(t, details.CompletedAt)
+							return nil
+						}),
+					// expecting the temporary file committed after the details completed
+					mockStorage.EXPECT().Rename(gomock.Any(), tempFileKey("file_id"), fileKey("file_id")).
+						Times(1).Return(nil),
+				)
 			},
 			expectedError: nil,
+			stream: func(t *testing.T, cancel context.CancelFunc) io.Reader {
+				// five data and then EOF
+				readers := make([]io.Reader, 5)
+				for i := range readers {
+					readers[i] = strings.NewReader("data")
+				}
+				return io.MultiReader(readers...)
+			},
+		},
+		"checksum_mismatch_should_remove_data_and_mark_failed": {
+			input:    "file_id",
+			expected: request.Checksum{SHA256: "invalid_sha256"},
+			buildStub: func(t *testing.T, mockRepo *mock_repo.MockStreamRepository,
+				mockStorage *mock_storage.MockBackend) {
 
-			sendAndCheck: func(t *testing.T, cancel context.CancelFunc, dataChan chan<- []byte,
-				errChan chan error, expError error) {
-				/**
-				*  not sending data or expecting an error
-				*  considering the client who calling this function
-				*  not sending data will return with in max time out on usecase
-				* if its not return with in 30s(test default timeout ) will fire an error by test
+				mockStorage.EXPECT().Put(gomock.Any(), tempFileKey("file_id"), gomock.Any()).Times(1).
+					DoAndReturn(readAllPut)
 
-				**/
+				// expecting the stored data removed and upload marked as failed
+				mockStorage.EXPECT().Delete(gomock.Any(), tempFileKey("file_id")).Times(1).Return(nil)
+				mockStorage.EXPECT().List(gomock.Any(), objectsPrefix("file_id")).Times(1).Return(nil, nil)
+				gomock.InOrder(
+					mockRepo.EXPECT().UpdateFileDetailsStatus(gomock.Any(), "file_id", domain.UploadStatusUploading, "").
+						Times(1).Return(nil),
+					mockRepo.EXPECT().UpdateFileDetailsStatus(gomock.Any(), "file_id", domain.UploadStatusFailed, gomock.Any()).
+						Times(1).Return(nil),
+				)
+			},
+			expectedError: ErrChecksumMismatch,
+			stream: func(t *testing.T, cancel context.CancelFunc) io.Reader {
+				return strings.NewReader("data")
 			},
 		},
-		"client_side_error_send_on_chan_should_return": {
-			input: "file_id",
-			buildStub: func(t *testing.T, mockFileHandler *mock_file.MockHandler) {
-				folderPath := generateFolderPath("file_id")
-				// expect call mkdir and returning no error
-				mockFileHandler.EXPECT().MkdirAll(folderPath, gomock.Any()).Times(1).
-					Return(nil)
+	}
+
+	for name, test := range testCases {
+
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// create mocks
+			ctl := gomock.NewController(t)
+			mockRepo := mock_repo.NewMockStreamRepository(ctl)
+			mockStorage := mock_storage.NewMockBackend(ctl)
+
+			test.buildStub(t, mockRepo, mockStorage)
+			streamUseCase := NewStreamUseCase(config.Config{}, mockRepo, mockStorage, discardLogger, metrics.NewMetrics())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			err := streamUseCase.UploadFileAsStream(ctx, test.input, test.expected, test.stream(t, cancel), nil)
+			if test.expectedError == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, test.expectedError.Error())
+			}
+		})
+	}
+}
+
+func TestUploadFileAsStreamWithProgress(t *testing.T) {
 
-				// create a mock file to send data
-				ctl := gomock.NewController(t)
-				mockFile := mock_file.NewMockFile(ctl)
+	ctl := gomock.NewController(t)
+	mockStorage := mock_storage.NewMockBackend(ctl)
+	// the storage reports the data durably stored only once all the data read
+	mockStorage.EXPECT().PutWithProgress(gomock.Any(), tempFileKey("file_id"), gomock.Any(), progressAckSize,
+		gomock.Any()).Times(1).
+		DoAndReturn(func(ctx context.Context, key string, reader io.Reader, interval int64,
+			progress func(int64)) (int64, error) {
+			size, err := readAllPut(ctx, key, reader)
+			progress(size)
+			return size, err
+		})
+
+	mockRepo := mock_repo.NewMockStreamRepository(ctl)
+	mockRepo.EXPECT().UpdateFileDetailsStatus(gomock.Any(), "file_id", domain.UploadStatusUploading, "").
+		Times(1).Return(nil)
+	mockRepo.EXPECT().CompleteFileDetails(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+	mockStorage.EXPECT().Rename(gomock.Any(), tempFileKey("file_id"), fileKey("file_id")).Times(1).Return(nil)
+
+	uploadMetrics := metrics.NewMetrics()
+	streamUseCase := NewStreamUseCase(config.Config{}, mockRepo, mockStorage, discardLogger, uploadMetrics)
+
+	stream := io.MultiReader(bytes.NewReader(make([]byte, progressAckSize)), bytes.NewReader(make([]byte, progressAckSize)))
 
-				// always expecting a call for closing file
-				mockFile.EXPECT().Close().Times(1).Return(nil)
+	// collect all progress until the usecase returned
+	var progresses []response.UploadProgress
+	err := streamUseCase.UploadFileAsStream(context.Background(), "file_id", request.Checksum{}, stream,
+		func(progress response.UploadProgress) {
+			progresses = append(progresses, progress)
+		})
+	assert.NoError(t, err)
+
+	// only the data reported as stored by the storage should acknowledge, not the data read from the stream
+	assert.Equal(t, []response.UploadProgress{
+		{Written: progressAckSize * 2},
+		{Written: progressAckSize * 2, Completed: true},
+	}, progresses)
+	assert.Equal(t, float64(progressAckSize*2), testutil.ToFloat64(uploadMetrics.BytesWritten))
+}
 
-				filePath := generateFilePath(folderPath, "file_id")
+func TestDownloadFile(t *testing.T) {
 
-				// expecting call to create and returning no mock file with error
-				mockFileHandler.EXPECT().Create(filePath).Times(1).
-					Return(mockFile, nil)
+	fileID := uuid.New()
 
+	testCases := map[string]struct {
+		input             string
+		buildStub         func(t *testing.T, mockRepo *mock_repo.MockStreamRepository, mockStorage *mock_storage.MockBackend)
+		isExpectingOutput bool
+		expectedError     error
+	}{
+		"invalid_file_id_should_return_error": {
+			input: "invalid_id",
+			buildStub: func(t *testing.T, mockRepo *mock_repo.MockStreamRepository,
+				mockStorage *mock_storage.MockBackend) {
 			},
-			expectedError: errors.New("failed to write data on file"),
+			isExpectingOutput: false,
+			expectedError:     ErrInvalidFileID,
+		},
+		"db_error_should_return_error": {
+			input: fileID.String(),
+			buildStub: func(t *testing.T, mockRepo *mock_repo.MockStreamRepository,
+				mockStorage *mock_storage.MockBackend) {
 
-			sendAndCheck: func(t *testing.T, cancel context.CancelFunc, dataChan chan<- []byte,
-				errChan chan error, expError error) {
-				/**
-				* sending error from client through errChan should return function
-				* if its not return with in 30s(test default timeout ) will fire an error by test
-				**/
-				errChan <- errors.New("error on client side to send data")
+				mockRepo.EXPECT().FindFileDetailsByID(gomock.Any(), fileID.String()).Times(1).
+					Return(domain.FileDetails{}, errors.New("db error"))
 			},
+			isExpectingOutput: false,
+			expectedError:     errors.New("db error"),
 		},
+		"file_details_not_exist_should_return_not_found_error": {
+			input: fileID.String(),
+			buildStub: func(t *testing.T, mockRepo *mock_repo.MockStreamRepository,
+				mockStorage *mock_storage.MockBackend) {
 
-		"cancel_on_context_should_return_function": {
-			input: "file_id",
-			buildStub: func(t *testing.T, mockFileHandler *mock_file.MockHandler) {
-				folderPath := generateFolderPath("file_id")
-				// expect call mkdir and returning no error
-				mockFileHandler.EXPECT().MkdirAll(folderPath, gomock.Any()).Times(1).
-					Return(nil)
+				// returning empty file details
+				mockRepo.EXPECT().FindFileDetailsByID(gomock.Any(), fileID.String()).Times(1).
+					Return(domain.FileDetails{}, nil)
+			},
+			isExpectingOutput: false,
+			expectedError:     ErrFileNotFound,
+		},
+		"file_of_other_owner_should_return_not_found_error": {
+			input: fileID.String(),
+			buildStub: func(t *testing.T, mockRepo *mock_repo.MockStreamRepository,
+				mockStorage *mock_storage.MockBackend) {
 
-				// create a mock file to send data
-				ctl := gomock.NewController(t)
-				mockFile := mock_file.NewMockFile(ctl)
+				mockRepo.EXPECT().FindFileDetailsByID(gomock.Any(), fileID.String()).Times(1).
+					Return(domain.FileDetails{ID: fileID, Owner: "other_owner", Status: domain.UploadStatusCompleted}, nil)
+			},
+			isExpectingOutput: false,
+			expectedError:     ErrFileNotFound,
+		},
+		"file_not_exist_on_storage_should_return_not_found_error": {
+			input: fileID.String(),
+			buildStub: func(t *testing.T, mockRepo *mock_repo.MockStreamRepository,
+				mockStorage *mock_storage.MockBackend) {
 
-				// always expecting a call for closing file
-				mockFile.EXPECT().Close().Times(1).Return(nil)
+				mockRepo.EXPECT().FindFileDetailsByID(gomock.Any(), fileID.String()).Times(1).
+					Return(domain.FileDetails{ID: fileID, Name: "file_name", Status: domain.UploadStatusCompleted}, nil)
 
-				filePath := generateFilePath(folderPath, "file_id")
+				mockStorage.EXPECT().GetRange(gomock.Any(), fileKey(fileID.String()), int64(0), int64(-1)).Times(1).
+					Return(nil, storage.ErrNotFound)
+			},
+			isExpectingOutput: false,
+			expectedError:     ErrFileNotFound,
+		},
+		"successful_should_return_file_details_and_file": {
+			input: fileID.String(),
+			buildStub: func(t *testing.T, mockRepo *mock_repo.MockStreamRepository,
+				mockStorage *mock_storage.MockBackend) {
 
-				// expecting call to create and returning no mock file with error
-				mockFileHandler.EXPECT().Create(filePath).Times(1).
-					Return(mockFile, nil)
+				mockRepo.EXPECT().FindFileDetailsByID(gomock.Any(), fileID.String()).Times(1).
+					Return(domain.FileDetails{ID: fileID, Name: "file_name", Status: domain.UploadStatusCompleted}, nil)
 
+				mockStorage.EXPECT().GetRange(gomock.Any(), fileKey(fileID.String()), int64(0), int64(-1)).Times(1).
+					Return(io.NopCloser(strings.NewReader("data")), nil)
 			},
-			expectedError: nil,
+			isExpectingOutput: true,
+			expectedError:     nil,
+		},
+	}
 
-			sendAndCheck: func(t *testing.T, cancel context.CancelFunc, dataChan chan<- []byte,
-				errChan chan error, expError error) {
-				/**
-				* calling ctx cancel func should return usecase function
-				* if its not return with in 30s(test default timeout ) will fire an error by test
-				**/
-				cancel()
+	for name, test := range testCases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			ctl := gomock.NewController(t)
+			repo := mock_repo.NewMockStreamRepository(ctl)
+			mockStorage := mock_storage.NewMockBackend(ctl)
+
+			test.buildStub(t, repo, mockStorage)
+			usecase := NewStreamUseCase(config.Config{}, repo, mockStorage, discardLogger, metrics.NewMetrics())
+
+			details, file, err := usecase.DownloadFile(context.TODO(), test.input)
+
+			if test.isExpectingOutput {
+				assert.Equal(t, test.input, details.ID)
+				assert.NotNil(t, file, "expecting file to read")
+			} else {
+				assert.Nil(t, file, "not expecting file")
+			}
+
+			if test.expectedError == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, test.expectedError.Error(), "should contain this error string")
+			}
+		})
+	}
+}
+
+func TestListFiles(t *testing.T) {
+
+	files := []domain.FileDetails{
+		{ID: uuid.New(), Name: "file_1", UploadedAt: time.Now()},
+		{ID: uuid.New(), Name: "file_2", UploadedAt: time.Now()},
+		{ID: uuid.New(), Name: "file_3", UploadedAt: time.Now()},
+	}
+
+	testCases := map[string]struct {
+		input             request.ListFiles
+		buildStub         func(mockRepo *mock_repo.MockStreamRepository)
+		expectedFileCount int
+		expectNextCursor  bool
+		expectedError     error
+	}{
+		"invalid_sort_field_should_return_error": {
+			input:         request.ListFiles{SortBy: "invalid"},
+			buildStub:     func(mockRepo *mock_repo.MockStreamRepository) {},
+			expectedError: ErrInvalidSortField,
+		},
+		"invalid_cursor_should_return_error": {
+			input:         request.ListFiles{Cursor: "invalid_cursor"},
+			buildStub:     func(mockRepo *mock_repo.MockStreamRepository) {},
+			expectedError: ErrInvalidCursor,
+		},
+		"db_error_should_return_error": {
+			input: request.ListFiles{},
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+				mockRepo.EXPECT().FindAllFileDetails(gomock.Any(), gomock.Any()).Times(1).
+					Return(nil, errors.New("db error"))
+			},
+			expectedError: errors.New("db error"),
+		},
+		"no_limit_should_find_with_default_limit": {
+			input: request.ListFiles{},
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+				// expecting one more than limit to check next page
+				mockRepo.EXPECT().FindAllFileDetails(gomock.Any(), request.FileFilter{
+					Owner:  "owner",
+					SortBy: request.SortByUploadedAt,
+					Limit:  defaultListLimit + 1,
+				}).Times(1).Return(files, nil)
+			},
+			expectedFileCount: 3,
+			expectNextCursor:  false,
+		},
+		"more_files_than_limit_should_return_next_cursor": {
+			input: request.ListFiles{Limit: 2, SortBy: request.SortByName},
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+				mockRepo.EXPECT().FindAllFileDetails(gomock.Any(), request.FileFilter{
+					Owner:  "owner",
+					SortBy: request.SortByName,
+					Limit:  3,
+				}).Times(1).Return(files, nil)
 			},
+			expectedFileCount: 2,
+			expectNextCursor:  true,
 		},
+	}
 
-		"successful_send_5_data_should_write_data": {
-			input: "file_id",
-			buildStub: func(t *testing.T, mockFileHandler *mock_file.MockHandler) {
-				folderPath := generateFolderPath("file_id")
-				// expect call mkdir and returning no error
-				mockFileHandler.EXPECT().MkdirAll(folderPath, gomock.Any()).Times(1).
-					Return(nil)
+	for name, test := range testCases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			ctl := gomock.NewController(t)
+			repo := mock_repo.NewMockStreamRepository(ctl)
+
+			test.buildStub(repo)
+			usecase := NewStreamUseCase(config.Config{}, repo, nil, discardLogger, metrics.NewMetrics())
+
+			// only the files of the owner should be listed
+			fileList, err := usecase.ListFiles(auth.ContextWithOwner(context.TODO(), "owner"), test.input)
+
+			if test.expectedError != nil {
+				assert.ErrorContains(t, err, test.expectedError.Error())
+				return
+			}
 
-				// create a mock file to send data
-				ctl := gomock.NewController(t)
-				mockFile := mock_file.NewMockFile(ctl)
+			assert.NoError(t, err)
+			assert.Len(t, fileList.Files, test.expectedFileCount)
 
-				// always expecting a call for closing file
-				mockFile.EXPECT().Close().Times(1).Return(nil)
+			if !test.expectNextCursor {
+				assert.Empty(t, fileList.NextCursor)
+				return
+			}
 
-				// expect a call to mock file write and returning an error
-				mockFile.EXPECT().Write(gomock.Any()).Times(5).
-					Return(0, nil)
+			// next cursor should point to the last file on the page
+			cursor, err := decodeFileCursor(fileList.NextCursor)
+			assert.NoError(t, err)
+			assert.Equal(t, files[test.expectedFileCount-1].ID.String(), cursor.ID)
+		})
+	}
+}
 
-				filePath := generateFilePath(folderPath, "file_id")
+func TestDeleteFile(t *testing.T) {
 
-				// expecting call to create and returning no mock file with error
-				mockFileHandler.EXPECT().Create(filePath).Times(1).
-					Return(mockFile, nil)
+	fileID := uuid.New()
+	deletedAt := time.Now()
 
+	testCases := map[string]struct {
+		buildStub     func(mockRepo *mock_repo.MockStreamRepository)
+		expectedError error
+	}{
+		"already_deleted_file_should_return_not_found_error": {
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+				mockRepo.EXPECT().FindFileDetailsByID(gomock.Any(), fileID.String()).Times(1).
+					Return(domain.FileDetails{ID: fileID, DeletedAt: &deletedAt}, nil)
+			},
+			expectedError: ErrFileNotFound,
+		},
+		"upload_not_completed_should_return_not_found_error": {
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+				mockRepo.EXPECT().FindFileDetailsByID(gomock.Any(), fileID.String()).Times(1).
+					Return(domain.FileDetails{ID: fileID, Status: domain.UploadStatusUploading}, nil)
+			},
+			expectedError: ErrFileNotFound,
+		},
+		"db_error_on_delete_should_return_error": {
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+				mockRepo.EXPECT().FindFileDetailsByID(gomock.Any(), fileID.String()).Times(1).
+					Return(domain.FileDetails{ID: fileID, Status: domain.UploadStatusCompleted}, nil)
+				mockRepo.EXPECT().SoftDeleteFileDetails(gomock.Any(), fileID.String(), gomock.Any()).Times(1).
+					Return(errors.New("db error"))
+			},
+			expectedError: errors.New("db error"),
+		},
+		"successful_delete_should_return_purge_time": {
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+				mockRepo.EXPECT().FindFileDetailsByID(gomock.Any(), fileID.String()).Times(1).
+					Return(domain.FileDetails{ID: fileID, Status: domain.UploadStatusCompleted}, nil)
+				mockRepo.EXPECT().SoftDeleteFileDetails(gomock.Any(), fileID.String(), gomock.Any()).Times(1).
+					Return(nil)
 			},
 			expectedError: nil,
+		},
+	}
+
+	for name, test := range testCases {
+		test := test
+		t.Run(name, func(t *testing.T) {
 
-			sendAndCheck: func(t *testing.T, cancel context.CancelFunc, dataChan chan<- []byte,
-				errChan chan error, expError error) {
+			t.Parallel()
 
-				// send five data
-				for i := 1; i <= 5; i++ {
-					dataChan <- []byte("data")
-				}
-				// send EOF to notify stream completed
-				errChan <- io.EOF
+			ctl := gomock.NewController(t)
+			repo := mock_repo.NewMockStreamRepository(ctl)
+
+			test.buildStub(repo)
+			retention := time.Hour
+			usecase := NewStreamUseCase(config.Config{FileRetentionPeriod: retention}, repo, nil, discardLogger, metrics.NewMetrics())
+
+			purgeAt, err := usecase.DeleteFile(context.TODO(), fileID.String())
+
+			if test.expectedError != nil {
+				assert.ErrorContains(t, err, test.expectedError.Error())
+				return
+			}
+			assert.NoError(t, err)
+			assert.WithinDuration(t, time.Now().Add(retention), purgeAt, time.Minute)
+		})
+	}
+}
+
+func TestRestoreFile(t *testing.T) {
+
+	fileID := uuid.New()
+	deletedAt := time.Now()
+
+	testCases := map[string]struct {
+		buildStub     func(mockRepo *mock_repo.MockStreamRepository)
+		expectedError error
+	}{
+		"file_not_exist_should_return_not_found_error": {
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+				mockRepo.EXPECT().FindFileDetailsByID(gomock.Any(), fileID.String()).Times(1).
+					Return(domain.FileDetails{}, nil)
+			},
+			expectedError: ErrFileNotFound,
+		},
+		"file_of_other_owner_should_return_not_found_error": {
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+				mockRepo.EXPECT().FindFileDetailsByID(gomock.Any(), fileID.String()).Times(1).
+					Return(domain.FileDetails{ID: fileID, Owner: "other_owner", DeletedAt: &deletedAt}, nil)
+			},
+			expectedError: ErrFileNotFound,
+		},
+		"not_deleted_file_should_return_error": {
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+				mockRepo.EXPECT().FindFileDetailsByID(gomock.Any(), fileID.String()).Times(1).
+					Return(domain.FileDetails{ID: fileID}, nil)
+			},
+			expectedError: ErrFileNotDeleted,
+		},
+		"deleted_file_should_restore": {
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+				mockRepo.EXPECT().FindFileDetailsByID(gomock.Any(), fileID.String()).Times(1).
+					Return(domain.FileDetails{ID: fileID, DeletedAt: &deletedAt}, nil)
+				mockRepo.EXPECT().RestoreFileDetails(gomock.Any(), fileID.String()).Times(1).
+					Return(nil)
 			},
+			expectedError: nil,
 		},
 	}
 
 	for name, test := range testCases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			ctl := gomock.NewController(t)
+			repo := mock_repo.NewMockStreamRepository(ctl)
+
+			test.buildStub(repo)
+			usecase := NewStreamUseCase(config.Config{}, repo, nil, discardLogger, metrics.NewMetrics())
 
+			fileDetails, err := usecase.RestoreFile(context.TODO(), fileID.String())
+
+			if test.expectedError != nil {
+				assert.ErrorIs(t, err, test.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, fileDetails.DeletedAt.IsZero(), "restored file should not have deleted time")
+		})
+	}
+}
+
+func TestPurgeDeletedFiles(t *testing.T) {
+
+	files := []domain.FileDetails{{ID: uuid.New()}, {ID: uuid.New()}}
+
+	testCases := map[string]struct {
+		buildStub      func(mockRepo *mock_repo.MockStreamRepository, mockStorage *mock_storage.MockBackend)
+		expectedPurged int
+		expectedError  error
+	}{
+		"db_error_should_return_error": {
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository, mockStorage *mock_storage.MockBackend) {
+				mockRepo.EXPECT().FindDeletedFileDetails(gomock.Any(), gomock.Any(), purgeBatchSize).Times(1).
+					Return(nil, errors.New("db error"))
+			},
+			expectedError: errors.New("db error"),
+		},
+		"failed_to_remove_file_should_skip_the_file": {
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository, mockStorage *mock_storage.MockBackend) {
+				mockRepo.EXPECT().FindDeletedFileDetails(gomock.Any(), gomock.Any(), purgeBatchSize).Times(1).
+					Return(files, nil)
+
+				// first file failed to remove and second file removed
+				mockStorage.EXPECT().Delete(gomock.Any(), tempFileKey(files[0].ID.String())).Times(1).Return(nil)
+				mockStorage.EXPECT().Delete(gomock.Any(), tempFileKey(files[1].ID.String())).Times(1).Return(nil)
+				mockStorage.EXPECT().List(gomock.Any(), objectsPrefix(files[0].ID.String())).Times(1).
+					Return(nil, errors.New("storage error"))
+				mockStorage.EXPECT().List(gomock.Any(), objectsPrefix(files[1].ID.String())).Times(1).
+					Return([]storage.ObjectInfo{{Key: fileKey(files[1].ID.String())}}, nil)
+				mockStorage.EXPECT().Delete(gomock.Any(), fileKey(files[1].ID.String())).Times(1).
+					Return(nil)
+				mockRepo.EXPECT().DeleteFileDetails(gomock.Any(), files[1].ID.String()).Times(1).
+					Return(nil)
+			},
+			expectedPurged: 1,
+		},
+	}
+
+	for name, test := range testCases {
 		test := test
 		t.Run(name, func(t *testing.T) {
+
 			t.Parallel()
 
-			// create mocks
 			ctl := gomock.NewController(t)
-			fileHandler := mock_file.NewMockHandler(ctl)
+			repo := mock_repo.NewMockStreamRepository(ctl)
+			mockStorage := mock_storage.NewMockBackend(ctl)
 
-			test.buildStub(t, fileHandler)
-			streamUseCase := NewStreamUseCase(nil, fileHandler)
+			test.buildStub(repo, mockStorage)
+			usecase := NewStreamUseCase(config.Config{}, repo, mockStorage, discardLogger, metrics.NewMetrics())
 
-			ctx, cancel := context.WithCancel(context.Background())
-			dataChan, errChan := make(chan []byte), make(chan error)
+			purged, err := usecase.PurgeDeletedFiles(context.TODO())
+
+			if test.expectedError != nil {
+				assert.ErrorContains(t, err, test.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.expectedPurged, purged)
+		})
+	}
+}
+
+func TestCheckUploadSessionOffset(t *testing.T) {
+
+	sessionID := uuid.New()
+	sessionSize := int64(200)
+	updatedAt := time.Now()
+
+	testCases := map[string]struct {
+		sessionID       string
+		offset          int64
+		buildStub       func(mockRepo *mock_repo.MockStreamRepository)
+		expectedSession response.UploadSession
+		expectedError   error
+	}{
+		"invalid_session_id_should_return_error": {
+			sessionID:     "invalid_id",
+			buildStub:     func(mockRepo *mock_repo.MockStreamRepository) {},
+			expectedError: ErrInvalidUploadSessionID,
+		},
+		"session_not_exist_should_return_not_found_error": {
+			sessionID: sessionID.String(),
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+				mockRepo.EXPECT().FindUploadSessionByID(gomock.Any(), sessionID.String()).Times(1).
+					Return(domain.UploadSession{}, nil)
+			},
+			expectedError: ErrUploadSessionNotFound,
+		},
+		"completed_session_should_return_error": {
+			sessionID: sessionID.String(),
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+				mockRepo.EXPECT().FindUploadSessionByID(gomock.Any(), sessionID.String()).Times(1).
+					Return(domain.UploadSession{ID: sessionID, Completed: true}, nil)
+			},
+			expectedError: ErrUploadSessionCompleted,
+		},
+		"offset_not_matching_committed_offset_should_return_error": {
+			sessionID: sessionID.String(),
+			offset:    50,
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+				mockRepo.EXPECT().FindUploadSessionByID(gomock.Any(), sessionID.String()).Times(1).
+					Return(domain.UploadSession{ID: sessionID, CommittedOffset: 100, UpdatedAt: time.Now()}, nil)
+			},
+			expectedError: ErrUploadOffsetMismatch,
+		},
+		"offset_matching_committed_offset_should_return_session": {
+			sessionID: sessionID.String(),
+			offset:    100,
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+				mockRepo.EXPECT().FindUploadSessionByID(gomock.Any(), sessionID.String()).Times(1).
+					Return(domain.UploadSession{ID: sessionID, CommittedOffset: 100, ExpectedSize: &sessionSize,
+						UpdatedAt: updatedAt}, nil)
+			},
+			expectedSession: response.UploadSession{ID: sessionID.String(), Offset: 100, Size: &sessionSize,
+				ExpiresAt: updatedAt.Add(time.Hour)},
+		},
+		"session_not_uploaded_within_expiry_should_return_expired_error": {
+			sessionID: sessionID.String(),
+			offset:    100,
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+				mockRepo.EXPECT().FindUploadSessionByID(gomock.Any(), sessionID.String()).Times(1).
+					Return(domain.UploadSession{ID: sessionID, CommittedOffset: 100,
+						UpdatedAt: time.Now().Add(-time.Hour * 2)}, nil)
+			},
+			expectedError: ErrUploadSessionExpired,
+		},
+	}
+
+	for name, test := range testCases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			ctl := gomock.NewController(t)
+			repo := mock_repo.NewMockStreamRepository(ctl)
+
+			test.buildStub(repo)
+			usecase := NewStreamUseCase(config.Config{UploadSessionExpiry: time.Hour}, repo, nil, discardLogger, metrics.NewMetrics())
+
+			session, err := usecase.CheckUploadSessionOffset(context.TODO(), test.sessionID, test.offset)
+
+			if test.expectedError == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, test.expectedError)
+			}
+			assert.Equal(t, test.expectedSession, session)
+		})
+	}
+}
+
+func TestUploadSessionAsStream(t *testing.T) {
+
+	sessionID := uuid.New().String()
+
+	testCases := map[string]struct {
+		offset    int64
+		buildStub func(t *testing.T, mockRepo *mock_repo.MockStreamRepository, mockStorage *mock_storage.MockBackend)
+		// client stream to read the data
+		stream          func(t *testing.T, cancel context.CancelFunc) io.Reader
+		expectedError   error
+		expectedWritten float64 // bytes of the stream stored on storage
+	}{
+		"stream_completed_should_complete_session_with_offset": {
+			offset: 10,
+			buildStub: func(t *testing.T, mockRepo *mock_repo.MockStreamRepository,
+				mockStorage *mock_storage.MockBackend) {
+
+				expectSessionClaim(mockRepo, sessionID, 10)
+
+				firstPart := storage.ObjectInfo{Key: sessionPartKey(sessionID, 0), Size: 10}
+				secondPart := storage.ObjectInfo{Key: sessionPartKey(sessionID, 10), Size: 8}
+
+				gomock.InOrder(
+					// expecting the parts listed before the upload to remove the stale parts
+					mockStorage.EXPECT().List(gomock.Any(), sessionPartsPrefix(sessionID)).Times(1).
+						Return([]storage.ObjectInfo{firstPart}, nil),
+					// expecting the parts listed again to combine them as the file
+					mockStorage.EXPECT().List(gomock.Any(), sessionPartsPrefix(sessionID)).Times(1).
+						Return([]storage.ObjectInfo{firstPart, secondPart}, nil),
+				)
+
+				// expecting the data stored as the part of the committed offset
+				mockStorage.EXPECT().Put(gomock.Any(), secondPart.Key, gomock.Any()).Times(1).
+					DoAndReturn(func(ctx context.Context, key string, reader io.Reader) (int64, error) {
+						data, err := io.ReadAll(reader)
+						assert.Equal(t, "datadata", string(data))
+						return int64(len(data)), err
+					})
+
+				mockRepo.EXPECT().FindUploadSessionByID(gomock.Any(), sessionID).Times(1).
+					Return(domain.UploadSession{ID: uuid.MustParse(sessionID)}, nil)
+
+				// expecting the parts read in order and stored as the file
+				mockStorage.EXPECT().GetRange(gomock.Any(), firstPart.Key, int64(0), int64(-1)).Times(1).
+					Return(io.NopCloser(strings.NewReader(strings.Repeat("d", 10))), nil)
+				mockStorage.EXPECT().GetRange(gomock.Any(), secondPart.Key, int64(0), int64(-1)).Times(1).
+					Return(io.NopCloser(strings.NewReader("datadata")), nil)
+				mockStorage.EXPECT().Put(gomock.Any(), tempFileKey(sessionID), gomock.Any()).Times(1).
+					DoAndReturn(func(ctx context.Context, key string, reader io.Reader) (int64, error) {
+						data, err := io.ReadAll(reader)
+						assert.Equal(t, strings.Repeat("d", 10)+"datadata", string(data))
+						return int64(len(data)), err
+					})
+
+				// expecting size as committed offset + written data size
+				mockRepo.EXPECT().CompleteUploadSession(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(ctx context.Context, details domain.FileDetails) error {
+						assert.Equal(t, sessionID, details.ID.String())
+						assert.Equal(t, int64(18), details.Size)
+						return nil
+					})
+				mockStorage.EXPECT().Rename(gomock.Any(), tempFileKey(sessionID), fileKey(sessionID)).Times(1).Return(nil)
+
+				// expecting the parts removed after the file stored
+				mockStorage.EXPECT().Delete(gomock.Any(), firstPart.Key).Times(1).Return(nil)
+				mockStorage.EXPECT().Delete(gomock.Any(), secondPart.Key).Times(1).Return(nil)
+			},
+			stream: func(t *testing.T, cancel context.CancelFunc) io.Reader {
+				return io.MultiReader(strings.NewReader("data"), strings.NewReader("data"))
+			},
+			expectedWritten: 8,
+		},
+		"stale_parts_after_committed_offset_should_remove": {
+			offset: 10,
+			buildStub: func(t *testing.T, mockRepo *mock_repo.MockStreamRepository,
+				mockStorage *mock_storage.MockBackend) {
+
+				expectSessionClaim(mockRepo, sessionID, 10)
+
+				// returning a part stored after the committed offset before a failure
+				mockStorage.EXPECT().List(gomock.Any(), sessionPartsPrefix(sessionID)).Times(1).
+					Return([]storage.ObjectInfo{
+						{Key: sessionPartKey(sessionID, 0)},
+						{Key: sessionPartKey(sessionID, 10)},
+						{Key: sessionPartKey(sessionID, 14)},
+					}, nil)
+
+				// expecting only the parts from the committed offset removed and returning an error
+				mockStorage.EXPECT().Delete(gomock.Any(), sessionPartKey(sessionID, 10)).Times(1).Return(nil)
+				mockStorage.EXPECT().Delete(gomock.Any(), sessionPartKey(sessionID, 14)).Times(1).
+					Return(errors.New("storage error"))
+			},
+			stream: func(t *testing.T, cancel context.CancelFunc) io.Reader {
+				return strings.NewReader("")
+			},
+			expectedError: errors.New("storage error"),
+		},
+		"stream_completed_before_declared_size_should_save_offset_and_return_error": {
+			offset: 0,
+			buildStub: func(t *testing.T, mockRepo *mock_repo.MockStreamRepository,
+				mockStorage *mock_storage.MockBackend) {
+
+				expectSessionClaim(mockRepo, sessionID, 0)
+
+				mockStorage.EXPECT().List(gomock.Any(), sessionPartsPrefix(sessionID)).Times(1).Return(nil, nil)
+				mockStorage.EXPECT().Put(gomock.Any(), sessionPartKey(sessionID, 0), gomock.Any()).Times(1).
+					DoAndReturn(readAllPut)
+
+				// returning the session with a larger declared size
+				size := int64(10)
+				mockRepo.EXPECT().FindUploadSessionByID(gomock.Any(), sessionID).Times(1).
+					Return(domain.UploadSession{ID: uuid.MustParse(sessionID), ExpectedSize: &size}, nil)
+
+				// expecting the written data size saved to resume later
+				mockRepo.EXPECT().UpdateUploadSessionOffset(gomock.Any(), sessionID, int64(4)).
+					Times(1).Return(nil)
+			},
+			stream: func(t *testing.T, cancel context.CancelFunc) io.Reader {
+				return strings.NewReader("data")
+			},
+			expectedError:   ErrSizeMismatch,
+			expectedWritten: 4,
+		},
+		"checksum_mismatch_should_remove_session_and_return_error": {
+			offset: 0,
+			buildStub: func(t *testing.T, mockRepo *mock_repo.MockStreamRepository,
+				mockStorage *mock_storage.MockBackend) {
+
+				expectSessionClaim(mockRepo, sessionID, 0)
+
+				part := storage.ObjectInfo{Key: sessionPartKey(sessionID, 0), Size: 4}
+				gomock.InOrder(
+					mockStorage.EXPECT().List(gomock.Any(), sessionPartsPrefix(sessionID)).Times(1).Return(nil, nil),
+					mockStorage.EXPECT().List(gomock.Any(), sessionPartsPrefix(sessionID)).Times(1).
+						Return([]storage.ObjectInfo{part}, nil),
+				)
+				mockStorage.EXPECT().Put(gomock.Any(), part.Key, gomock.Any()).Times(1).
+					DoAndReturn(readAllPut)
+
+				// returning the session with a different expected checksum
+				crc32c := uint32(1)
+				mockRepo.EXPECT().FindUploadSessionByID(gomock.Any(), sessionID).Times(1).
+					Return(domain.UploadSession{ID: uuid.MustParse(sessionID), ExpectedCRC32C: &crc32c}, nil)
+
+				mockStorage.EXPECT().GetRange(gomock.Any(), part.Key, int64(0), int64(-1)).Times(1).
+					Return(io.NopCloser(strings.NewReader("data")), nil)
+				mockStorage.EXPECT().Put(gomock.Any(), tempFileKey(sessionID), gomock.Any()).Times(1).
+					DoAndReturn(readAllPut)
+
+				// expecting the file, parts and session removed
+				mockStorage.EXPECT().Delete(gomock.Any(), tempFileKey(sessionID)).Times(1).Return(nil)
+				mockStorage.EXPECT().List(gomock.Any(), objectsPrefix(sessionID)).Times(1).
+					Return([]storage.ObjectInfo{part}, nil)
+				mockStorage.EXPECT().Delete(gomock.Any(), part.Key).Times(1).Return(nil)
+				mockRepo.EXPECT().DeleteFileDetails(gomock.Any(), sessionID).Times(1).Return(nil)
+			},
+			stream: func(t *testing.T, cancel context.CancelFunc) io.Reader {
+				return strings.NewReader("data")
+			},
+			expectedError:   ErrChecksumMismatch,
+			expectedWritten: 4,
+		},
+		"cancel_on_context_should_save_committed_offset": {
+			offset: 0,
+			buildStub: func(t *testing.T, mockRepo *mock_repo.MockStreamRepository,
+				mockStorage *mock_storage.MockBackend) {
+
+				expectSessionClaim(mockRepo, sessionID, 0)
+
+				mockStorage.EXPECT().List(gomock.Any(), sessionPartsPrefix(sessionID)).Times(1).Return(nil, nil)
+				mockStorage.EXPECT().Put(gomock.Any(), sessionPartKey(sessionID, 0), gomock.Any()).Times(1).
+					DoAndReturn(readAllPut)
+
+				// expecting to save the written data size as offset
+				mockRepo.EXPECT().UpdateUploadSessionOffset(gomock.Any(), sessionID, int64(4)).
+					Times(1).Return(nil)
+			},
+			stream: func(t *testing.T, cancel context.CancelFunc) io.Reader {
+				return blockingStream(t, cancel, "data")
+			},
+			expectedError:   context.Canceled,
+			expectedWritten: 4,
+		},
+		"session_claimed_by_another_upload_should_return_offset_mismatch": {
+			offset: 10,
+			buildStub: func(t *testing.T, mockRepo *mock_repo.MockStreamRepository,
+				mockStorage *mock_storage.MockBackend) {
+
+				// expecting no parts removed or stored while another upload storing the part
+				mockRepo.EXPECT().ClaimUploadSession(gomock.Any(), sessionID, int64(10), gomock.Any()).Times(1).
+					Return(false, nil)
+			},
+			stream: func(t *testing.T, cancel context.CancelFunc) io.Reader {
+				return strings.NewReader("data")
+			},
+			expectedError: ErrUploadOffsetMismatch,
+		},
+	}
+
+	for name, test := range testCases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			ctl := gomock.NewController(t)
+			repo := mock_repo.NewMockStreamRepository(ctl)
+			mockStorage := mock_storage.NewMockBackend(ctl)
+
+			test.buildStub(t, repo, mockStorage)
+			uploadMetrics := metrics.NewMetrics()
+			streamUseCase := NewStreamUseCase(config.Config{}, repo, mockStorage, discardLogger, uploadMetrics)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			err := streamUseCase.UploadSessionAsStream(ctx, sessionID, test.offset, test.stream(t, cancel))
+			if test.expectedError == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, test.expectedError.Error())
+			}
+			assert.Equal(t, test.expectedWritten, testutil.ToFloat64(uploadMetrics.BytesWritten))
+		})
+	}
+}
+
+// To expect the upload session claimed to upload from the offset and released after the upload
+func expectSessionClaim(mockRepo *mock_repo.MockStreamRepository, sessionID string, offset int64) {
+	mockRepo.EXPECT().ClaimUploadSession(gomock.Any(), sessionID, offset, gomock.Any()).Times(1).Return(true, nil)
+	mockRepo.EXPECT().ReleaseUploadSession(gomock.Any(), sessionID).Times(1).Return(nil)
+}
+
+// To store the data of a reader as a fake storage
+func readAllPut(ctx context.Context, key string, reader io.Reader) (int64, error) {
+	data, err := io.ReadAll(reader)
+	return int64(len(data)), err
+}
+
+type readerFunc func(data []byte) (int, error)
+
+func (f readerFunc) Read(data []byte) (int, error) {
+	return f(data)
+}
+
+// To create a client stream which returns the data and then blocks until the test completed,
+// the after func (if not nil) called once all the data read and waiting for more
+func blockingStream(t *testing.T, after func(), data ...string) io.Reader {
+
+	done := make(chan struct{})
+	t.Cleanup(func() { close(done) })
+
+	return io.MultiReader(strings.NewReader(strings.Join(data, "")), readerFunc(func([]byte) (int, error) {
+		if after != nil {
+			after()
+			after = nil
+		}
+		<-done
+		return 0, io.EOF
+	}))
+}
+
+func TestUploadFileAsStreamOnMemoryStorage(t *testing.T) {
+
+	testCases := map[string]struct {
+		buildStub    func(mockRepo *mock_repo.MockStreamRepository, memStorage *storage.MemoryBackend, cancel context.CancelFunc)
+		stream       func(t *testing.T, cancel context.CancelFunc) io.Reader // "datamore" if nil
+		expectedErr  error
+		expectedKeys []string
+	}{
+		"write_error_should_not_store_file_and_mark_failed": {
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository, memStorage *storage.MemoryBackend,
+				cancel context.CancelFunc) {
+
+				// fail the write after the first data
+				memStorage.InjectWriteError(tempFileKey("file_id"), 4, nil)
+
+				gomock.InOrder(
+					mockRepo.EXPECT().UpdateFileDetailsStatus(gomock.Any(), "file_id", domain.UploadStatusUploading, "").
+						Times(1).Return(nil),
+					mockRepo.EXPECT().UpdateFileDetailsStatus(gomock.Any(), "file_id", domain.UploadStatusFailed, gomock.Any()).
+						Times(1).Return(nil),
+				)
+			},
+			expectedErr:  storage.ErrInjectedWrite,
+			expectedKeys: []string{},
+		},
+		"cancel_while_streaming_should_not_store_file_and_mark_aborted": {
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository, memStorage *storage.MemoryBackend,
+				cancel context.CancelFunc) {
+
+				gomock.InOrder(
+					mockRepo.EXPECT().UpdateFileDetailsStatus(gomock.Any(), "file_id", domain.UploadStatusUploading, "").
+						Times(1).Return(nil),
+					mockRepo.EXPECT().UpdateFileDetailsStatus(gomock.Any(), "file_id", domain.UploadStatusAborted, gomock.Any()).
+						Times(1).Return(nil),
+				)
+			},
+			stream: func(t *testing.T, cancel context.CancelFunc) io.Reader {
+				return blockingStream(t, cancel, "data")
+			},
+			expectedErr:  context.Canceled,
+			expectedKeys: []string{},
+		},
+		"cancel_after_stream_completed_should_store_file": {
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository, memStorage *storage.MemoryBackend,
+				cancel context.CancelFunc) {
+
+				// expecting the completion not cancelled with the stream
+				mockRepo.EXPECT().UpdateFileDetailsStatus(gomock.Any(), "file_id", domain.UploadStatusUploading, "").
+					Times(1).Return(nil)
+				mockRepo.EXPECT().CompleteFileDetails(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(ctx context.Context, details domain.FileDetails) error {
+						cancel()
+						return ctx.Err()
+					})
+			},
+			expectedKeys: []string{fileKey("file_id")},
+		},
+		"successful_should_store_file": {
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository, memStorage *storage.MemoryBackend,
+				cancel context.CancelFunc) {
+
+				mockRepo.EXPECT().UpdateFileDetailsStatus(gomock.Any(), "file_id", domain.UploadStatusUploading, "").
+					Times(1).Return(nil)
+				mockRepo.EXPECT().CompleteFileDetails(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+			},
+			expectedKeys: []string{fileKey("file_id")},
+		},
+	}
+
+	for name, test := range testCases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			ctl := gomock.NewController(t)
+			mockRepo := mock_repo.NewMockStreamRepository(ctl)
+			memStorage := storage.NewMemoryBackend()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			test.buildStub(mockRepo, memStorage, cancel)
+			streamUseCase := NewStreamUseCase(config.Config{}, mockRepo, memStorage, discardLogger, metrics.NewMetrics())
+
+			stream := io.MultiReader(strings.NewReader("data"), strings.NewReader("more"))
+			if test.stream != nil {
+				stream = test.stream(t, cancel)
+			}
+			err := streamUseCase.UploadFileAsStream(ctx, "file_id", request.Checksum{}, stream, nil)
+			if test.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, test.expectedErr)
+			}
+
+			assert.Equal(t, test.expectedKeys, memStorage.Keys())
+			if test.expectedErr == nil {
+				data, _ := memStorage.Contents(fileKey("file_id"))
+				assert.Equal(t, "datamore", string(data))
+			}
+		})
+	}
+}
+
+func TestUploadSessionAsStreamResumeOnMemoryStorage(t *testing.T) {
+
+	sessionID := uuid.New().String()
+
+	ctl := gomock.NewController(t)
+	mockRepo := mock_repo.NewMockStreamRepository(ctl)
+	memStorage := storage.NewMemoryBackend()
+	streamUseCase := NewStreamUseCase(config.Config{}, mockRepo, memStorage, discardLogger, metrics.NewMetrics())
+
+	// first stream cancelled after sending the first data
+	expectSessionClaim(mockRepo, sessionID, 0)
+	mockRepo.EXPECT().UpdateUploadSessionOffset(gomock.Any(), sessionID, int64(4)).Times(1).Return(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	err := streamUseCase.UploadSessionAsStream(ctx, sessionID, 0, blockingStream(t, cancel, "data"))
+	assert.ErrorIs(t, err, context.Canceled)
+
+	assert.Equal(t, []string{sessionPartKey(sessionID, 0)}, memStorage.Keys())
+
+	// second stream resumed from the committed offset should complete the file
+	size := int64(8)
+	expectSessionClaim(mockRepo, sessionID, 4)
+	mockRepo.EXPECT().FindUploadSessionByID(gomock.Any(), sessionID).Times(1).
+		Return(domain.UploadSession{ID: uuid.MustParse(sessionID), CommittedOffset: 4, ExpectedSize: &size}, nil)
+	mockRepo.EXPECT().CompleteUploadSession(gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(ctx context.Context, details domain.FileDetails) error {
+			assert.Equal(t, int64(8), details.Size)
+			return nil
+		})
+
+	err = streamUseCase.UploadSessionAsStream(context.Background(), sessionID, 4, strings.NewReader("more"))
+	assert.NoError(t, err)
+
+	// parts should be removed after the file stored
+	assert.Equal(t, []string{fileKey(sessionID)}, memStorage.Keys())
+	data, _ := memStorage.Contents(fileKey(sessionID))
+	assert.Equal(t, "datamore", string(data))
+}
+
+func TestUploadSessionAsStreamConcurrentResumes(t *testing.T) {
+
+	sessionID := uuid.New().String()
+
+	ctl := gomock.NewController(t)
+	mockRepo := mock_repo.NewMockStreamRepository(ctl)
+	memStorage := storage.NewMemoryBackend()
+	streamUseCase := NewStreamUseCase(config.Config{}, mockRepo, memStorage, discardLogger, metrics.NewMetrics())
+
+	// claiming the session as the conditional update of the repository, so only one upload holds the claim
+	var (
+		mu      sync.Mutex
+		claimed bool
+	)
+	mockRepo.EXPECT().ClaimUploadSession(gomock.Any(), sessionID, int64(0), gomock.Any()).Times(2).
+		DoAndReturn(func(ctx context.Context, id string, offset int64, until time.Time) (bool, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if claimed {
+				return false, nil
+			}
+			claimed = true
+			return true, nil
+		})
+	mockRepo.EXPECT().ReleaseUploadSession(gomock.Any(), sessionID).Times(1).
+		DoAndReturn(func(ctx context.Context, id string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			claimed = false
+			return nil
+		})
+	mockRepo.EXPECT().UpdateUploadSessionOffset(gomock.Any(), sessionID, int64(4)).Times(1).Return(nil)
+
+	// first upload waiting for more data after storing a part of it
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	waiting := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- streamUseCase.UploadSessionAsStream(ctx, sessionID, 0,
+			blockingStream(t, func() { close(waiting) }, "data"))
+	}()
+	<-waiting
+
+	// second upload from the same offset should be rejected without removing or replacing the part
+	err := streamUseCase.UploadSessionAsStream(context.Background(), sessionID, 0, strings.NewReader("other"))
+	assert.ErrorIs(t, err, ErrUploadOffsetMismatch)
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+
+	assert.Equal(t, []string{sessionPartKey(sessionID, 0)}, memStorage.Keys())
+	data, _ := memStorage.Contents(sessionPartKey(sessionID, 0))
+	assert.Equal(t, "data", string(data))
+}
+
+func TestUploadSessionPartAsStream(t *testing.T) {
+
+	sessionID := uuid.New()
+	size := int64(8)
+	sha1Sum := func(data string) []byte {
+		sum := sha1.Sum([]byte(data))
+		return sum[:]
+	}
+
+	testCases := map[string]struct {
+		offset    int64
+		checksum  *request.PartChecksum
+		stream    func(t *testing.T, cancel context.CancelFunc) io.Reader
+		buildStub func(mockRepo *mock_repo.MockStreamRepository)
+		// storage state before the upload
+		buildStorage     func(memStorage *storage.MemoryBackend)
+		expectedSession  response.UploadSession
+		expectedError    error
+		expectedContents map[string]string // contents of the objects after the upload
+	}{
+		"invalid_checksum_algorithm_should_return_error": {
+			checksum:         &request.PartChecksum{Algorithm: "crc64"},
+			stream:           func(t *testing.T, cancel context.CancelFunc) io.Reader { return strings.NewReader("data") },
+			buildStub:        func(mockRepo *mock_repo.MockStreamRepository) {},
+			expectedError:    ErrInvalidChecksumAlgorithm,
+			expectedContents: map[string]string{},
+		},
+		"session_claimed_by_another_upload_should_return_offset_mismatch": {
+			offset: 4,
+			stream: func(t *testing.T, cancel context.CancelFunc) io.Reader { return strings.NewReader("da") },
+			buildStorage: func(memStorage *storage.MemoryBackend) {
+				memStorage.Put(context.Background(), sessionPartKey(sessionID.String(), 0), strings.NewReader("data"))
+				memStorage.Put(context.Background(), sessionPartKey(sessionID.String(), 4), strings.NewReader("mo"))
+			},
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+				mockRepo.EXPECT().ClaimUploadSession(gomock.Any(), sessionID.String(), int64(4), gomock.Any()).Times(1).
+					Return(false, nil)
+			},
+			expectedError: ErrUploadOffsetMismatch,
+			// expecting the part of the upload in progress not removed
+			expectedContents: map[string]string{
+				sessionPartKey(sessionID.String(), 0): "data",
+				sessionPartKey(sessionID.String(), 4): "mo",
+			},
+		},
+		"part_before_declared_size_should_save_offset": {
+			offset:   4,
+			checksum: &request.PartChecksum{Algorithm: "sha1", Digest: sha1Sum("da")},
+			stream:   func(t *testing.T, cancel context.CancelFunc) io.Reader { return strings.NewReader("da") },
+			buildStorage: func(memStorage *storage.MemoryBackend) {
+				memStorage.Put(context.Background(), sessionPartKey(sessionID.String(), 0), strings.NewReader("data"))
+			},
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+				expectSessionClaim(mockRepo, sessionID.String(), 4)
+				mockRepo.EXPECT().FindUploadSessionByID(gomock.Any(), sessionID.String()).Times(1).
+					Return(domain.UploadSession{ID: sessionID, CommittedOffset: 4, ExpectedSize: &size}, nil)
+				mockRepo.EXPECT().UpdateUploadSessionOffset(gomock.Any(), sessionID.String(), int64(6)).
+					Times(1).Return(nil)
+			},
+			expectedSession: response.UploadSession{ID: sessionID.String(), Offset: 6, Size: &size},
+			expectedContents: map[string]string{
+				sessionPartKey(sessionID.String(), 0): "data",
+				sessionPartKey(sessionID.String(), 4): "da",
+			},
+		},
+		"checksum_mismatch_should_discard_part": {
+			offset:   4,
+			checksum: &request.PartChecksum{Algorithm: "sha1", Digest: sha1Sum("more")},
+			stream:   func(t *testing.T, cancel context.CancelFunc) io.Reader { return strings.NewReader("mode") },
+			buildStorage: func(memStorage *storage.MemoryBackend) {
+				memStorage.Put(context.Background(), sessionPartKey(sessionID.String(), 0), strings.NewReader("data"))
+			},
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+				expectSessionClaim(mockRepo, sessionID.String(), 4)
+				// expecting the committed offset not changed
+				mockRepo.EXPECT().UpdateUploadSessionOffset(gomock.Any(), sessionID.String(), int64(4)).
+					Times(1).Return(nil)
+			},
+			expectedError: ErrChecksumMismatch,
+			expectedContents: map[string]string{
+				sessionPartKey(sessionID.String(), 0): "data",
+			},
+		},
+		"cancel_with_checksum_should_discard_part": {
+			offset:   0,
+			checksum: &request.PartChecksum{Algorithm: "md5", Digest: []byte("digest")},
+			stream: func(t *testing.T, cancel context.CancelFunc) io.Reader {
+				return blockingStream(t, cancel, "data")
+			},
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+				expectSessionClaim(mockRepo, sessionID.String(), 0)
+				mockRepo.EXPECT().UpdateUploadSessionOffset(gomock.Any(), sessionID.String(), int64(0)).
+					Times(1).Return(nil)
+			},
+			expectedError:    context.Canceled,
+			expectedContents: map[string]string{},
+		},
+		"cancel_without_checksum_should_keep_partial_part": {
+			offset: 0,
+			stream: func(t *testing.T, cancel context.CancelFunc) io.Reader {
+				return blockingStream(t, cancel, "data")
+			},
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+				expectSessionClaim(mockRepo, sessionID.String(), 0)
+				mockRepo.EXPECT().UpdateUploadSessionOffset(gomock.Any(), sessionID.String(), int64(4)).
+					Times(1).Return(nil)
+			},
+			expectedError: context.Canceled,
+			expectedContents: map[string]string{
+				sessionPartKey(sessionID.String(), 0): "data",
+			},
+		},
+		"part_reaching_declared_size_should_complete_session": {
+			offset:   4,
+			checksum: &request.PartChecksum{Algorithm: "sha256", Digest: func() []byte { sum := sha256.Sum256([]byte("more")); return sum[:] }()},
+			stream:   func(t *testing.T, cancel context.CancelFunc) io.Reader { return strings.NewReader("more") },
+			buildStorage: func(memStorage *storage.MemoryBackend) {
+				memStorage.Put(context.Background(), sessionPartKey(sessionID.String(), 0), strings.NewReader("data"))
+				// stale part stored after the committed offset before a failure
+				memStorage.Put(context.Background(), sessionPartKey(sessionID.String(), 6), strings.NewReader("stale"))
+			},
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+				expectSessionClaim(mockRepo, sessionID.String(), 4)
+				mockRepo.EXPECT().FindUploadSessionByID(gomock.Any(), sessionID.String()).Times(2).
+					Return(domain.UploadSession{ID: sessionID, CommittedOffset: 4, ExpectedSize: &size}, nil)
+				mockRepo.EXPECT().CompleteUploadSession(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(ctx context.Context, details domain.FileDetails) error {
+						assert.Equal(t, size, details.Size)
+						return nil
+					})
+			},
+			expectedSession: response.UploadSession{ID: sessionID.String(), Offset: 8, Completed: true, Size: &size},
+			expectedContents: map[string]string{
+				fileKey(sessionID.String()): "datamore",
+			},
+		},
+	}
+
+	for name, test := range testCases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			ctl := gomock.NewController(t)
+			mockRepo := mock_repo.NewMockStreamRepository(ctl)
+			memStorage := storage.NewMemoryBackend()
+			if test.buildStorage != nil {
+				test.buildStorage(memStorage)
+			}
+
+			test.buildStub(mockRepo)
+			streamUseCase := NewStreamUseCase(config.Config{}, mockRepo, memStorage, discardLogger, metrics.NewMetrics())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			session, err := streamUseCase.UploadSessionPartAsStream(ctx, sessionID.String(), test.offset,
+				test.checksum, test.stream(t, cancel))
+			if test.expectedError == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, test.expectedError)
+			}
+			assert.Equal(t, test.expectedSession, session)
+
+			contents := make(map[string]string)
+			for _, key := range memStorage.Keys() {
+				data, _ := memStorage.Contents(key)
+				contents[key] = string(data)
+			}
+			assert.Equal(t, test.expectedContents, contents)
+		})
+	}
+}
+
+func TestDeleteUploadSession(t *testing.T) {
+
+	sessionID := uuid.New()
+
+	testCases := map[string]struct {
+		buildStub     func(mockRepo *mock_repo.MockStreamRepository)
+		expectedError error
+		expectedKeys  []string
+	}{
+		"session_of_other_owner_should_return_not_found_error": {
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+				mockRepo.EXPECT().FindUploadSessionByID(gomock.Any(), sessionID.String()).Times(1).
+					Return(domain.UploadSession{ID: sessionID, Owner: "other_owner"}, nil)
+			},
+			expectedError: ErrUploadSessionNotFound,
+			expectedKeys:  []string{sessionPartKey(sessionID.String(), 0)},
+		},
+		"completed_session_should_return_error": {
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+				mockRepo.EXPECT().FindUploadSessionByID(gomock.Any(), sessionID.String()).Times(1).
+					Return(domain.UploadSession{ID: sessionID, Completed: true}, nil)
+			},
+			expectedError: ErrUploadSessionCompleted,
+			expectedKeys:  []string{sessionPartKey(sessionID.String(), 0)},
+		},
+		"not_completed_session_should_remove_session_and_parts": {
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+				mockRepo.EXPECT().FindUploadSessionByID(gomock.Any(), sessionID.String()).Times(1).
+					Return(domain.UploadSession{ID: sessionID, CommittedOffset: 4}, nil)
+				mockRepo.EXPECT().DeleteFileDetails(gomock.Any(), sessionID.String()).Times(1).Return(nil)
+			},
+			expectedKeys: []string{},
+		},
+	}
+
+	for name, test := range testCases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			ctl := gomock.NewController(t)
+			mockRepo := mock_repo.NewMockStreamRepository(ctl)
+			memStorage := storage.NewMemoryBackend()
+			memStorage.Put(context.Background(), sessionPartKey(sessionID.String(), 0), strings.NewReader("data"))
+
+			test.buildStub(mockRepo)
+			streamUseCase := NewStreamUseCase(config.Config{}, mockRepo, memStorage, discardLogger, metrics.NewMetrics())
+
+			err := streamUseCase.DeleteUploadSession(context.Background(), sessionID.String())
+			if test.expectedError == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, test.expectedError)
+			}
+			assert.Equal(t, test.expectedKeys, memStorage.Keys())
+		})
+	}
+}
+
+func TestPurgeExpiredUploadSessions(t *testing.T) {
+
+	sessions := []domain.UploadSession{{ID: uuid.New()}, {ID: uuid.New()}}
+
+	ctl := gomock.NewController(t)
+	mockRepo := mock_repo.NewMockStreamRepository(ctl)
+	memStorage := storage.NewMemoryBackend()
+	for _, session := range sessions {
+		memStorage.Put(context.Background(), sessionPartKey(session.ID.String(), 0), strings.NewReader("data"))
+	}
+
+	// expecting the sessions not updated within the expiry removed
+	mockRepo.EXPECT().FindExpiredUploadSessions(gomock.Any(), gomock.Any(), purgeBatchSize).Times(1).
+		DoAndReturn(func(ctx context.Context, updatedBefore time.Time, limit int) ([]domain.UploadSession, error) {
+			assert.WithinDuration(t, time.Now().Add(-time.Hour), updatedBefore, time.Minute)
+			return sessions, nil
+		})
+	mockRepo.EXPECT().DeleteFileDetails(gomock.Any(), sessions[0].ID.String()).Times(1).Return(nil)
+	mockRepo.EXPECT().DeleteFileDetails(gomock.Any(), sessions[1].ID.String()).Times(1).Return(nil)
+
+	streamUseCase := NewStreamUseCase(config.Config{UploadSessionExpiry: time.Hour}, mockRepo, memStorage, discardLogger, metrics.NewMetrics())
+
+	purged, err := streamUseCase.PurgeExpiredUploadSessions(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, purged)
+	assert.Equal(t, []string{}, memStorage.Keys())
+
+	// nothing purged without expiry
+	streamUseCase = NewStreamUseCase(config.Config{}, mockRepo, memStorage, discardLogger, metrics.NewMetrics())
+	purged, err = streamUseCase.PurgeExpiredUploadSessions(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, purged)
+}
+
+func TestRecoverUploads(t *testing.T) {
+
+	completedID, uploadingID, failedID := uuid.New().String(), uuid.New().String(), uuid.New().String()
+
+	testCases := map[string]struct {
+		gracePeriod       time.Duration
+		buildStub         func(mockRepo *mock_repo.MockStreamRepository)
+		expectedRecovered int
+		expectedKeys      []string
+	}{
+		"recent_temporary_files_should_skip": {
+			gracePeriod:       time.Hour,
+			buildStub:         func(mockRepo *mock_repo.MockStreamRepository) {},
+			expectedRecovered: 0,
+			expectedKeys: []string{
+				tempFileKey("invalid_id"), tempFileKey(completedID), tempFileKey(failedID), tempFileKey(uploadingID),
+			},
+		},
+		"should_commit_completed_and_remove_others": {
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+
+				mockRepo.EXPECT().FindFileDetailsByID(gomock.Any(), completedID).Times(1).
+					Return(domain.FileDetails{ID: uuid.MustParse(completedID), Status: domain.UploadStatusCompleted}, nil)
+				mockRepo.EXPECT().FindFileDetailsByID(gomock.Any(), failedID).Times(1).
+					Return(domain.FileDetails{ID: uuid.MustParse(failedID), Status: domain.UploadStatusFailed}, nil)
+				mockRepo.EXPECT().FindFileDetailsByID(gomock.Any(), uploadingID).Times(1).
+					Return(domain.FileDetails{ID: uuid.MustParse(uploadingID), Status: domain.UploadStatusUploading}, nil)
+
+				// expecting the interrupted upload marked as failed
+				mockRepo.EXPECT().UpdateFileDetailsStatus(gomock.Any(), uploadingID, domain.UploadStatusFailed,
+					ErrUploadInterrupted.Error()).Times(1).Return(nil)
+			},
+			expectedRecovered: 4,
+			expectedKeys:      []string{fileKey(completedID)},
+		},
+		"db_error_should_skip_the_file": {
+			buildStub: func(mockRepo *mock_repo.MockStreamRepository) {
+
+				mockRepo.EXPECT().FindFileDetailsByID(gomock.Any(), gomock.Any()).Times(3).
+					Return(domain.FileDetails{}, errors.New("db error"))
+			},
+			expectedRecovered: 1, // only the file of invalid id removed
+			expectedKeys:      []string{tempFileKey(completedID), tempFileKey(failedID), tempFileKey(uploadingID)},
+		},
+	}
+
+	for name, test := range testCases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			ctl := gomock.NewController(t)
+			mockRepo := mock_repo.NewMockStreamRepository(ctl)
+			memStorage := storage.NewMemoryBackend()
+
+			// leftover temporary files
+			for _, id := range []string{"invalid_id", completedID, failedID, uploadingID} {
+				_, err := memStorage.Put(context.Background(), tempFileKey(id), strings.NewReader("data"))
+				assert.NoError(t, err)
+			}
+
+			test.buildStub(mockRepo)
+			streamUseCase := NewStreamUseCase(config.Config{UploadRecoveryGracePeriod: test.gracePeriod},
+				mockRepo, memStorage, discardLogger, metrics.NewMetrics())
 
-			// run the send and check func on separate goroutine to check error and response
-			go test.sendAndCheck(t, cancel, dataChan, errChan, test.expectedError)
+			recovered, err := streamUseCase.RecoverUploads(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedRecovered, recovered)
 
-			// running this function direct on this test helps to identify orphaned(a state )
-			streamUseCase.UploadFileAsStream(ctx, test.input, dataChan, errChan)
+			expectedKeys := append([]string{}, test.expectedKeys...)
+			sort.Strings(expectedKeys)
+			assert.Equal(t, expectedKeys, memStorage.Keys())
 		})
 	}
 }